@@ -36,6 +36,19 @@ type Strategy struct {
 	SplitBrainThresholdSeconds int `json:"splitBrainThresholdSeconds,omitempty"`
 }
 
+// StrategyOverride overrides Gslb strategy for the host of particular rule. Unset fields are inherited
+// from the Gslb strategy.
+// +k8s:openapi-gen=true
+type StrategyOverride struct {
+	// Load balancing strategy type:(roundRobin|failover|geoip)
+	// +kubebuilder:validation:Enum=roundRobin;failover;geoip
+	Type string `json:"type,omitempty"`
+	// Primary Geo Tag. Valid for failover strategy only
+	PrimaryGeoTag string `json:"primaryGeoTag,omitempty"`
+	// Defines DNS record TTL in seconds
+	DNSTtlSeconds int `json:"dnsTtlSeconds,omitempty"`
+}
+
 // GslbSpec defines the desired state of Gslb
 // +k8s:openapi-gen=true
 type GslbSpec struct {
@@ -53,6 +66,54 @@ type GslbStatus struct {
 	HealthyRecords map[string][]string `json:"healthyRecords"`
 	// Cluster Geo Tag
 	GeoTag string `json:"geoTag"`
+	// Errors of edge DNS providers which failed during the last zone delegation, keyed by provider name
+	EdgeDNSErrors map[string]string `json:"edgeDNSErrors,omitempty"`
+	// DNS changes computed but not written in dry-run mode
+	DryRun *DryRunStatus `json:"dryRun,omitempty"`
+	// Time since the Gslb is being taken out of global rotation
+	DrainingSince *metav1.Time `json:"drainingSince,omitempty"`
+	// Effective strategy of every host, including per-rule overrides
+	Strategies map[string]Strategy `json:"strategies,omitempty"`
+	// Conditions of the Gslb; SpecValid reports validation of the spec against GslbPolicy
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Last changes of DNS targets of every host, oldest first
+	TargetHistory map[string][]TargetChange `json:"targetHistory,omitempty"`
+}
+
+// TargetChange is a change of DNS targets of the host
+type TargetChange struct {
+	// Time of the change
+	Time metav1.Time `json:"time"`
+	// Targets before the change
+	OldTargets []string `json:"oldTargets,omitempty"`
+	// Targets after the change
+	NewTargets []string `json:"newTargets,omitempty"`
+	// Reason of the change, e.g. Unhealthy, Drained or PeerTargetsChanged
+	Reason string `json:"reason"`
+	// Strategy type of the host when the change was made
+	Strategy string `json:"strategy"`
+}
+
+// DNSChange is a change of DNS record planned in dry-run mode
+type DNSChange struct {
+	// Action is one of create, update or delete
+	Action string `json:"action"`
+	// Name of the record
+	Name string `json:"name"`
+	// Type of the record, e.g. A, NS or TXT
+	Type string `json:"type"`
+	// Targets of the record after the change
+	Targets []string `json:"targets,omitempty"`
+	// TTL of the record after the change
+	TTL int64 `json:"ttl,omitempty"`
+}
+
+// DryRunStatus holds DNS changes computed but not written in dry-run mode
+type DryRunStatus struct {
+	// Changes of DNSEndpoint of the Gslb
+	DNSEndpoint []DNSChange `json:"dnsEndpoint,omitempty"`
+	// Changes of zone delegation and heartbeat records in edge DNS
+	ZoneDelegation []DNSChange `json:"zoneDelegation,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -0,0 +1,81 @@
+/*
+
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IntRange is an inclusive range of allowed values, zero bound is not checked
+type IntRange struct {
+	// Minimal allowed value
+	Min int `json:"min,omitempty"`
+	// Maximal allowed value
+	Max int `json:"max,omitempty"`
+}
+
+// PolicyDefaults are values of Gslb strategy used when the Gslb doesn't set them
+type PolicyDefaults struct {
+	// Defines DNS record TTL in seconds
+	DNSTtlSeconds int `json:"dnsTtlSeconds,omitempty"`
+	// Split brain TXT record expiration in seconds, i.e. how long external cluster is considered healthy
+	// without refreshing its heartbeat
+	SplitBrainThresholdSeconds int `json:"splitBrainThresholdSeconds,omitempty"`
+}
+
+// GslbPolicySpec defines defaults and guardrails for Gslbs in selected namespaces
+// +k8s:openapi-gen=true
+type GslbPolicySpec struct {
+	// Namespaces of Gslbs the policy applies to; empty selector selects all namespaces
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+	// Defaults of strategy fields not set in the Gslb
+	Defaults PolicyDefaults `json:"defaults,omitempty"`
+	// Allowed strategy types, including per-host overrides; empty list allows all strategies
+	AllowedStrategies []string `json:"allowedStrategies,omitempty"`
+	// Allowed range of DNS record TTL, including per-host overrides
+	DNSTtlSeconds *IntRange `json:"dnsTtlSeconds,omitempty"`
+	// Allowed range of split brain threshold
+	SplitBrainThresholdSeconds *IntRange `json:"splitBrainThresholdSeconds,omitempty"`
+	// Allowed hosts, either exact or with wildcard first label, e.g. *.app.cloud.example.com
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+	// Allowed zones; hosts of the zone and of its subdomains are allowed
+	AllowedZones []string `json:"allowedZones,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// GslbPolicy is the Schema for the gslbpolicies API
+type GslbPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GslbPolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GslbPolicyList contains a list of GslbPolicy
+type GslbPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GslbPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GslbPolicy{}, &GslbPolicyList{})
+}
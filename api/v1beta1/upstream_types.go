@@ -94,6 +94,9 @@ type IngressRule struct {
 	// currently the only supported IngressRuleValue.
 	// +optional
 	IngressRuleValue `json:",inline,omitempty" protobuf:"bytes,2,opt,name=ingressRuleValue"`
+	// Strategy overrides the Gslb strategy for the host of the rule. It isn't propagated to the Ingress.
+	// +optional
+	Strategy *StrategyOverride `json:"strategy,omitempty"`
 }
 
 // IngressRuleValue represents a rule to apply against incoming requests. If the
@@ -113,7 +116,8 @@ type IngressRuleValue struct {
 	// RFC 3986, this resource will be used to match against
 	// everything after the last '/' and before the first '?'
 	// or '#'.
-	HTTP *v1beta1.HTTPIngressRuleValue `json:"http" protobuf:"bytes,1,opt,name=http"`
+	// +optional
+	HTTP *v1beta1.HTTPIngressRuleValue `json:"http,omitempty" protobuf:"bytes,1,opt,name=http"`
 }
 
 // DeepCopyInto copying the receiver, writing into out. in must be non-nil.
@@ -149,6 +153,11 @@ func (in *IngressSpec) DeepCopyInto(out *IngressSpec) {
 func (in *IngressRule) DeepCopyInto(out *IngressRule) {
 	*out = *in
 	in.IngressRuleValue.DeepCopyInto(&out.IngressRuleValue)
+	if in.Strategy != nil {
+		in, out := &in.Strategy, &out.Strategy
+		*out = new(StrategyOverride)
+		**out = **in
+	}
 }
 
 // DeepCopyInto copying the receiver, writing into out. in must be non-nil.
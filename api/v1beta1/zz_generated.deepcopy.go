@@ -23,9 +23,59 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package v1beta1
 
 import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DNSChange) DeepCopyInto(out *DNSChange) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DNSChange.
+func (in *DNSChange) DeepCopy() *DNSChange {
+	if in == nil {
+		return nil
+	}
+	out := new(DNSChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DryRunStatus) DeepCopyInto(out *DryRunStatus) {
+	*out = *in
+	if in.DNSEndpoint != nil {
+		in, out := &in.DNSEndpoint, &out.DNSEndpoint
+		*out = make([]DNSChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ZoneDelegation != nil {
+		in, out := &in.ZoneDelegation, &out.ZoneDelegation
+		*out = make([]DNSChange, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DryRunStatus.
+func (in *DryRunStatus) DeepCopy() *DryRunStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DryRunStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Gslb) DeepCopyInto(out *Gslb) {
 	*out = *in
@@ -85,6 +135,110 @@ func (in *GslbList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GslbPolicy) DeepCopyInto(out *GslbPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GslbPolicy.
+func (in *GslbPolicy) DeepCopy() *GslbPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(GslbPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GslbPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GslbPolicyList) DeepCopyInto(out *GslbPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GslbPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GslbPolicyList.
+func (in *GslbPolicyList) DeepCopy() *GslbPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(GslbPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GslbPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GslbPolicySpec) DeepCopyInto(out *GslbPolicySpec) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	out.Defaults = in.Defaults
+	if in.AllowedStrategies != nil {
+		in, out := &in.AllowedStrategies, &out.AllowedStrategies
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DNSTtlSeconds != nil {
+		in, out := &in.DNSTtlSeconds, &out.DNSTtlSeconds
+		*out = new(IntRange)
+		**out = **in
+	}
+	if in.SplitBrainThresholdSeconds != nil {
+		in, out := &in.SplitBrainThresholdSeconds, &out.SplitBrainThresholdSeconds
+		*out = new(IntRange)
+		**out = **in
+	}
+	if in.AllowedHosts != nil {
+		in, out := &in.AllowedHosts, &out.AllowedHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedZones != nil {
+		in, out := &in.AllowedZones, &out.AllowedZones
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GslbPolicySpec.
+func (in *GslbPolicySpec) DeepCopy() *GslbPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GslbPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GslbSpec) DeepCopyInto(out *GslbSpec) {
 	*out = *in
@@ -127,6 +281,53 @@ func (in *GslbStatus) DeepCopyInto(out *GslbStatus) {
 			(*out)[key] = outVal
 		}
 	}
+	if in.EdgeDNSErrors != nil {
+		in, out := &in.EdgeDNSErrors, &out.EdgeDNSErrors
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.DryRun != nil {
+		in, out := &in.DryRun, &out.DryRun
+		*out = new(DryRunStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DrainingSince != nil {
+		in, out := &in.DrainingSince, &out.DrainingSince
+		*out = (*in).DeepCopy()
+	}
+	if in.Strategies != nil {
+		in, out := &in.Strategies, &out.Strategies
+		*out = make(map[string]Strategy, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TargetHistory != nil {
+		in, out := &in.TargetHistory, &out.TargetHistory
+		*out = make(map[string][]TargetChange, len(*in))
+		for key, val := range *in {
+			var outVal []TargetChange
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]TargetChange, len(*in))
+				for i := range *in {
+					(*in)[i].DeepCopyInto(&(*out)[i])
+				}
+			}
+			(*out)[key] = outVal
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GslbStatus.
@@ -169,6 +370,36 @@ func (in *IngressSpec) DeepCopy() *IngressSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IntRange) DeepCopyInto(out *IntRange) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IntRange.
+func (in *IntRange) DeepCopy() *IntRange {
+	if in == nil {
+		return nil
+	}
+	out := new(IntRange)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PolicyDefaults) DeepCopyInto(out *PolicyDefaults) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PolicyDefaults.
+func (in *PolicyDefaults) DeepCopy() *PolicyDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(PolicyDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Strategy) DeepCopyInto(out *Strategy) {
 	*out = *in
@@ -183,3 +414,44 @@ func (in *Strategy) DeepCopy() *Strategy {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StrategyOverride) DeepCopyInto(out *StrategyOverride) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StrategyOverride.
+func (in *StrategyOverride) DeepCopy() *StrategyOverride {
+	if in == nil {
+		return nil
+	}
+	out := new(StrategyOverride)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TargetChange) DeepCopyInto(out *TargetChange) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.OldTargets != nil {
+		in, out := &in.OldTargets, &out.OldTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NewTargets != nil {
+		in, out := &in.NewTargets, &out.NewTargets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetChange.
+func (in *TargetChange) DeepCopy() *TargetChange {
+	if in == nil {
+		return nil
+	}
+	out := new(TargetChange)
+	in.DeepCopyInto(out)
+	return out
+}
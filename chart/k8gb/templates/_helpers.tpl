@@ -61,3 +61,215 @@ Create the name of the service account to use
     {{ default "default" .Values.serviceAccount.name }}
 {{- end -}}
 {{- end -}}
+
+{{/*
+Environment of the k8gb operator, shared by the deployment and the uninstall hook
+*/}}
+{{- define "k8gb.env" -}}
+- name: WATCH_NAMESPACE
+  value: ""
+- name: POD_NAME
+  valueFrom:
+    fieldRef:
+      fieldPath: metadata.name
+- name: POD_NAMESPACE
+  valueFrom:
+    fieldRef:
+      fieldPath: metadata.namespace
+- name: OPERATOR_NAME
+  value: "k8gb"
+- name: K8GB_VERSION
+  value: {{ quote .Chart.AppVersion }}
+- name: CLUSTER_GEO_TAG
+  value: {{ quote .Values.k8gb.clusterGeoTag }}
+- name: EXT_GSLB_CLUSTERS_GEO_TAGS
+  value: {{ quote .Values.k8gb.extGslbClustersGeoTags }}
+- name: EDGE_DNS_ZONE
+  value: {{ .Values.k8gb.edgeDNSZone }}
+- name: EDGE_DNS_SERVER
+  value: {{ .Values.k8gb.edgeDNSServer }}
+- name: EDGE_DNS_SERVER_PORT
+  value: "53"
+- name: DNS_ZONE
+  value: {{ .Values.k8gb.dnsZone }}
+- name: RECONCILE_REQUEUE_SECONDS
+  value: {{ quote .Values.k8gb.reconcileRequeueSeconds}}
+- name: ZONE_DELEGATION_TTL_SECONDS
+  value: {{ quote .Values.k8gb.zoneDelegation.ttl }}
+- name: ZONE_DELEGATION_GLUE_IPS
+  value: {{ join "," .Values.k8gb.zoneDelegation.glueIPs | quote }}
+{{ if .Values.infoblox.enabled }}
+- name: INFOBLOX_GRID_HOST
+  valueFrom:
+    configMapKeyRef:
+      name: infoblox
+      key: INFOBLOX_GRID_HOST
+- name: INFOBLOX_WAPI_VERSION
+  valueFrom:
+    configMapKeyRef:
+      name: infoblox
+      key: INFOBLOX_WAPI_VERSION
+- name: INFOBLOX_WAPI_PORT
+  valueFrom:
+    configMapKeyRef:
+      name: infoblox
+      key: INFOBLOX_WAPI_PORT
+- name: INFOBLOX_HTTP_REQUEST_TIMEOUT
+  valueFrom:
+    configMapKeyRef:
+      name: infoblox
+      key: INFOBLOX_HTTP_REQUEST_TIMEOUT
+- name: INFOBLOX_HTTP_POOL_CONNECTIONS
+  valueFrom:
+    configMapKeyRef:
+      name: infoblox
+      key: INFOBLOX_HTTP_POOL_CONNECTIONS
+- name: INFOBLOX_SSL_VERIFY
+  valueFrom:
+    configMapKeyRef:
+      name: infoblox
+      key: INFOBLOX_SSL_VERIFY
+- name: EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME_FILE
+  value: /var/run/secrets/infoblox/EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME
+- name: EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD_FILE
+  value: /var/run/secrets/infoblox/EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD
+{{ if .Values.infoblox.caBundleConfigMap }}
+- name: INFOBLOX_CA_BUNDLE
+  value: /etc/k8gb/infoblox-ca/ca.crt
+{{ end }}
+{{ end }}
+{{ if .Values.route53.enabled }}
+- name: ROUTE53_ENABLED
+  value: "true"
+{{ end }}
+{{ if .Values.ns1.enabled }}
+- name: NS1_ENABLED
+  value: "true"
+{{ end }}
+{{ if .Values.powerdns.enabled }}
+- name: POWERDNS_API_URL
+  value: {{ quote .Values.powerdns.apiURL }}
+- name: POWERDNS_SERVER_ID
+  value: {{ quote .Values.powerdns.serverID }}
+- name: POWERDNS_HTTP_REQUEST_TIMEOUT
+  value: {{ quote .Values.powerdns.httpRequestTimeout }}
+- name: POWERDNS_API_KEY
+  valueFrom:
+    secretKeyRef:
+      name: powerdns
+      key: POWERDNS_API_KEY
+{{ end }}
+{{ if .Values.dnsProviderPlugin.enabled }}
+- name: DNS_PROVIDER_PLUGIN_SOCKET
+  value: {{ quote .Values.dnsProviderPlugin.socket }}
+- name: DNS_PROVIDER_PLUGIN_REQUEST_TIMEOUT
+  value: {{ quote .Values.dnsProviderPlugin.requestTimeout }}
+{{ end }}
+{{ if .Values.k8gb.exposeCoreDNS }}
+- name: COREDNS_EXPOSED
+  value: "true"
+{{ end }}
+- name: LOG_FORMAT
+  value: {{ quote .Values.k8gb.log.format }}
+- name: LOG_LEVEL
+  value: {{ quote .Values.k8gb.log.level }}
+- name: NO_COLOR
+  value: "true"
+- name: SPLIT_BRAIN_CHECK
+  value: {{ quote .Values.k8gb.splitBrainCheck }}
+- name: SPLIT_BRAIN_QUORUM
+  value: {{ quote .Values.k8gb.splitBrainQuorum }}
+- name: DRY_RUN
+  value: {{ quote .Values.k8gb.dryRun }}
+- name: DRAIN
+  value: {{ quote .Values.k8gb.drain.enabled }}
+- name: DRAIN_TTL_SECONDS
+  value: {{ quote .Values.k8gb.drain.ttl }}
+- name: DRAIN_PERIOD_SECONDS
+  value: {{ quote .Values.k8gb.drain.periodSeconds }}
+- name: METRICS_ADDRESS
+  value: {{ .Values.k8gb.metricsAddress }}
+- name: DEBUG_API_ENABLED
+  value: {{ quote .Values.k8gb.debugAPI }}
+- name: TRACING_ENABLED
+  value: {{ quote .Values.k8gb.tracing.enabled }}
+- name: OTEL_EXPORTER_OTLP_ENDPOINT
+  value: {{ quote .Values.k8gb.tracing.endpoint }}
+- name: OTEL_EXPORTER_OTLP_INSECURE
+  value: {{ quote .Values.k8gb.tracing.insecure }}
+- name: AUDIT_HISTORY_SIZE
+  value: {{ quote .Values.k8gb.audit.historySize }}
+- name: AUDIT_LOG_ENABLED
+  value: {{ quote .Values.k8gb.audit.logEnabled }}
+- name: PEER_STATUS_ENABLED
+  value: {{ quote .Values.k8gb.peerStatus.enabled }}
+{{- if .Values.k8gb.peerStatus.enabled }}
+- name: PEER_STATUS_PORT
+  value: {{ quote .Values.k8gb.peerStatus.port }}
+- name: PEER_STATUS_ENDPOINTS
+  value: {{ include "k8gb.peerStatusEndpoints" . | quote }}
+{{- end }}
+{{- if .Values.k8gb.tsig.enabled }}
+- name: TSIG_KEY_NAME
+  value: {{ quote .Values.k8gb.tsig.keyName }}
+- name: TSIG_ALGORITHM
+  value: {{ quote .Values.k8gb.tsig.algorithm }}
+- name: TSIG_SECRET
+  valueFrom:
+    secretKeyRef:
+      name: k8gb-tsig
+      key: TSIG_SECRET
+{{- end }}
+{{- if .Values.k8gb.heartbeatSigning.enabled }}
+- name: HEARTBEAT_HMAC_KEY
+  valueFrom:
+    secretKeyRef:
+      name: k8gb-heartbeat
+      key: HEARTBEAT_HMAC_KEY
+{{- end }}
+- name: HEARTBEAT_BACKEND
+  value: {{ quote .Values.k8gb.heartbeat.backend }}
+{{- if eq .Values.k8gb.heartbeat.backend "lease" }}
+- name: HEARTBEAT_LEASE_NAMESPACE
+  value: {{ quote .Values.k8gb.heartbeat.leaseNamespace }}
+- name: HEARTBEAT_LEASE_KUBECONFIG
+  value: /etc/k8gb/heartbeat-lease/kubeconfig
+{{- end }}
+{{- end -}}
+
+{{/*
+Infoblox credentials and CA bundle mounts
+*/}}
+{{- define "k8gb.peerStatusEndpoints" -}}
+{{- $endpoints := list }}
+{{- range $geoTag, $endpoint := .Values.k8gb.peerStatus.endpoints }}
+{{- $endpoints = append $endpoints (printf "%s=%s" $geoTag $endpoint) }}
+{{- end }}
+{{- join "," $endpoints }}
+{{- end -}}
+
+{{- define "k8gb.infobloxVolumeMounts" -}}
+{{- if .Values.infoblox.enabled }}
+- name: infoblox-credentials
+  mountPath: /var/run/secrets/infoblox
+  readOnly: true
+{{- if .Values.infoblox.caBundleConfigMap }}
+- name: infoblox-ca
+  mountPath: /etc/k8gb/infoblox-ca
+  readOnly: true
+{{- end }}
+{{- end }}
+{{- end -}}
+
+{{- define "k8gb.infobloxVolumes" -}}
+{{- if .Values.infoblox.enabled }}
+- name: infoblox-credentials
+  secret:
+    secretName: infoblox
+{{- if .Values.infoblox.caBundleConfigMap }}
+- name: infoblox-ca
+  configMap:
+    name: {{ .Values.infoblox.caBundleConfigMap }}
+{{- end }}
+{{- end }}
+{{- end -}}
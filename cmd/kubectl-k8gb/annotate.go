@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package main
+
+import (
+	"context"
+	"fmt"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers"
+	"k8s.io/api/networking/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const failoverStrategy = "failover"
+
+// drain takes the Gslb out of global rotation by annotation, or returns it back when off
+func (p *plugin) drain(ctx context.Context, name string, off bool) error {
+	gslb := &k8gbv1beta1.Gslb{}
+	if err := p.client.Get(ctx, client.ObjectKey{Namespace: p.namespace, Name: name}, gslb); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(gslb.DeepCopy())
+	if off {
+		delete(gslb.Annotations, controllers.DrainAnnotation)
+	} else {
+		if gslb.Annotations == nil {
+			gslb.Annotations = make(map[string]string)
+		}
+		gslb.Annotations[controllers.DrainAnnotation] = "true"
+	}
+	if err := p.client.Patch(ctx, gslb, patch); err != nil {
+		return err
+	}
+	if off {
+		fmt.Fprintf(p.out, "gslb %s/%s returns to global rotation\n", gslb.Namespace, gslb.Name)
+	} else {
+		fmt.Fprintf(p.out, "gslb %s/%s is taken out of global rotation\n", gslb.Namespace, gslb.Name)
+	}
+	return nil
+}
+
+// failover makes cluster of geoTag primary for failover strategy of the Gslb. Gslb created from annotated Ingress is
+// switched by annotation of the Ingress, otherwise the Ingress controller would revert the change. Per-host overrides
+// of primary geo tag are kept
+func (p *plugin) failover(ctx context.Context, name, geoTag string) error {
+	gslb := &k8gbv1beta1.Gslb{}
+	if err := p.client.Get(ctx, client.ObjectKey{Namespace: p.namespace, Name: name}, gslb); err != nil {
+		return err
+	}
+	if _, annotated := gslb.Annotations[controllers.StrategyAnnotation]; annotated {
+		ingress := &v1beta1.Ingress{}
+		if err := p.client.Get(ctx, client.ObjectKey{Namespace: p.namespace, Name: name}, ingress); err != nil {
+			return err
+		}
+		if ingress.Annotations[controllers.StrategyAnnotation] != failoverStrategy {
+			return fmt.Errorf("ingress %s/%s doesn't use failover strategy", ingress.Namespace, ingress.Name)
+		}
+		patch := client.MergeFrom(ingress.DeepCopy())
+		ingress.Annotations[controllers.PrimaryGeoTagAnnotation] = geoTag
+		if err := p.client.Patch(ctx, ingress, patch); err != nil {
+			return err
+		}
+		fmt.Fprintf(p.out, "ingress %s/%s annotated with %s=%s\n", ingress.Namespace, ingress.Name,
+			controllers.PrimaryGeoTagAnnotation, geoTag)
+		return nil
+	}
+	if gslb.Spec.Strategy.Type != failoverStrategy {
+		return fmt.Errorf("gslb %s/%s doesn't use failover strategy", gslb.Namespace, gslb.Name)
+	}
+	patch := client.MergeFrom(gslb.DeepCopy())
+	gslb.Spec.Strategy.PrimaryGeoTag = geoTag
+	if err := p.client.Patch(ctx, gslb, patch); err != nil {
+		return err
+	}
+	fmt.Fprintf(p.out, "gslb %s/%s fails over to %s\n", gslb.Namespace, gslb.Name, geoTag)
+	return nil
+}
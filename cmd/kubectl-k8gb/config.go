@@ -0,0 +1,207 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package main
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// k8gbDeployment is name of k8gb deployment and of its operator container
+const k8gbDeployment = "k8gb"
+
+// operatorAssistant reads configuration of k8gb from environment of its deployment and returns it together with
+// assistant which resolves nameservers, signs lookups of peer clusters, verifies heartbeats and reads them from the
+// heartbeat backend the same way the operator does. Secrets are read from the cluster; what can't be set up, e.g.
+// because the secret can't be read, is returned as warning
+func operatorAssistant(ctx context.Context, c client.Client, namespace string) (*depresolver.Config, *assistant.Gslb, []string, error) {
+	pod, container, err := operatorContainer(ctx, c, namespace)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	config := configFromEnv(container.Env)
+	a := assistant.NewGslbAssistant(c, namespace, config.EdgeDNSServer, config.EdgeDNSServerPort, nil)
+	var warnings []string
+	if config.DNSSecurity.TSIGKeyName != "" {
+		secret, err := envSecret(ctx, c, namespace, container.Env, depresolver.TSIGSecretKey)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("lookups of peer clusters aren't signed by TSIG key %s (%s)",
+				config.DNSSecurity.TSIGKeyName, err))
+		} else {
+			a.UseTSIG(config.DNSSecurity.TSIGKeyName, config.DNSSecurity.TSIGAlgorithm, secret)
+		}
+	}
+	key, err := envSecret(ctx, c, namespace, container.Env, depresolver.HeartbeatKeyKey)
+	if err != nil {
+		warnings = append(warnings, fmt.Sprintf("signatures of heartbeats aren't verified (%s)", err))
+	}
+	a.VerifyHeartbeats(key)
+	if config.Heartbeat.Backend == depresolver.HeartbeatBackendLease {
+		if err = readLeaseHeartbeats(ctx, c, namespace, pod, container, config, a); err != nil {
+			warnings = append(warnings, fmt.Sprintf("heartbeats can't be read from Leases of shared cluster (%s)", err))
+			a.UseHeartbeatBackend(unavailableHeartbeats{err: err})
+		}
+	}
+	return config, a, warnings, nil
+}
+
+func operatorContainer(ctx context.Context, c client.Reader, namespace string) (*corev1.PodSpec, *corev1.Container, error) {
+	deployment := &appsv1.Deployment{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: k8gbDeployment}, deployment)
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading k8gb deployment (%s)", err)
+	}
+	pod := &deployment.Spec.Template.Spec
+	for i := range pod.Containers {
+		if pod.Containers[i].Name == k8gbDeployment {
+			return pod, &pod.Containers[i], nil
+		}
+	}
+	return nil, nil, fmt.Errorf("container %s not found in deployment %s/%s", k8gbDeployment, namespace, k8gbDeployment)
+}
+
+// envSecret returns value of environment variable name of the operator, reading the secret it references. It is empty
+// when the variable isn't set
+func envSecret(ctx context.Context, c client.Reader, namespace string, vars []corev1.EnvVar, name string) (string, error) {
+	for _, v := range vars {
+		if v.Name != name {
+			continue
+		}
+		if v.ValueFrom == nil || v.ValueFrom.SecretKeyRef == nil {
+			return v.Value, nil
+		}
+		ref := v.ValueFrom.SecretKeyRef
+		data, err := secretData(ctx, c, namespace, ref.Name, ref.Key)
+		if err != nil {
+			return "", fmt.Errorf("%s: %w", name, err)
+		}
+		return string(data), nil
+	}
+	return "", nil
+}
+
+func secretData(ctx context.Context, c client.Reader, namespace, name, key string) ([]byte, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, fmt.Errorf("reading secret %s/%s (%s)", namespace, name, err)
+	}
+	data, found := secret.Data[key]
+	if !found {
+		return nil, fmt.Errorf("key %s not found in secret %s/%s", key, namespace, name)
+	}
+	return data, nil
+}
+
+// readLeaseHeartbeats makes the assistant read heartbeats from Leases of the shared cluster, using kubeconfig of the
+// operator from the secret mounted at the path of HEARTBEAT_LEASE_KUBECONFIG
+func readLeaseHeartbeats(ctx context.Context, c client.Reader, namespace string, pod *corev1.PodSpec, container *corev1.Container,
+	config *depresolver.Config, a *assistant.Gslb) error {
+	dir, file := path.Split(config.Heartbeat.LeaseKubeconfig)
+	var volume string
+	for _, mount := range container.VolumeMounts {
+		if path.Clean(mount.MountPath) == path.Clean(dir) {
+			volume = mount.Name
+		}
+	}
+	for _, v := range pod.Volumes {
+		if v.Name != volume || v.Secret == nil {
+			continue
+		}
+		kubeconfig, err := secretData(ctx, c, namespace, v.Secret.SecretName, file)
+		if err != nil {
+			return err
+		}
+		restConfig, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+		if err != nil {
+			return fmt.Errorf("kubeconfig of shared cluster (%s)", err)
+		}
+		return a.ReadLeaseHeartbeats(restConfig, config.Heartbeat.LeaseNamespace)
+	}
+	return fmt.Errorf("no secret mounted at %s", dir)
+}
+
+// unavailableHeartbeats fails reading of heartbeats whose backend can't be reached, so they aren't read from edge DNS
+type unavailableHeartbeats struct {
+	err error
+}
+
+func (u unavailableHeartbeats) Write(context.Context, string, string, time.Duration) error {
+	return u.err
+}
+
+func (u unavailableHeartbeats) Read(context.Context, string) ([]string, error) {
+	return nil, u.err
+}
+
+func (u unavailableHeartbeats) Delete(context.Context, string) error {
+	return u.err
+}
+
+// configFromEnv returns configuration of DNS zones, clusters, TSIG key and heartbeat backend. Values referenced from
+// secrets or config maps are not resolved, see operatorAssistant
+func configFromEnv(vars []corev1.EnvVar) *depresolver.Config {
+	env := make(map[string]string, len(vars))
+	for _, v := range vars {
+		env[v.Name] = v.Value
+	}
+	config := &depresolver.Config{
+		ClusterGeoTag:      env[depresolver.ClusterGeoTagKey],
+		ExtClustersGeoTags: []string{},
+		EdgeDNSServer:      env[depresolver.EdgeDNSServerKey],
+		EdgeDNSServerPort:  53,
+		EdgeDNSZone:        env[depresolver.EdgeDNSZoneKey],
+		DNSZone:            env[depresolver.DNSZoneKey],
+		K8gbNamespace:      env[depresolver.K8gbNamespaceKey],
+		DNSSecurity: depresolver.DNSSecurity{
+			TSIGKeyName:   env[depresolver.TSIGKeyNameKey],
+			TSIGAlgorithm: orDefault(env[depresolver.TSIGAlgorithmKey], "hmac-sha256"),
+		},
+		Heartbeat: depresolver.Heartbeat{
+			Backend:         orDefault(env[depresolver.HeartbeatBackendKey], depresolver.HeartbeatBackendDNS),
+			LeaseKubeconfig: orDefault(env[depresolver.HeartbeatLeaseKubeconfigKey], "/etc/k8gb/heartbeat-lease/kubeconfig"),
+			LeaseNamespace:  orDefault(env[depresolver.HeartbeatLeaseNamespaceKey], "k8gb"),
+		},
+	}
+	if port, err := strconv.Atoi(env[depresolver.EdgeDNSServerPortKey]); err == nil {
+		config.EdgeDNSServerPort = port
+	}
+	for _, tag := range strings.Split(env[depresolver.ExtClustersGeoTagsKey], ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			config.ExtClustersGeoTags = append(config.ExtClustersGeoTags, tag)
+		}
+	}
+	return config
+}
+
+func orDefault(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+)
+
+// dig resolves targets of the host from nameservers of peer clusters the same way k8gb does when it collects
+// external targets, including TSIG signature of the lookups
+func (p *plugin) dig(ctx context.Context, host string) error {
+	config, a, warnings, err := operatorAssistant(ctx, p.client, p.k8gbNamespace)
+	if err != nil {
+		return err
+	}
+	p.warn(warnings)
+	nameservers := config.GetExternalClusterNSNames()
+	tags := make([]string, 0, len(nameservers))
+	for tag := range nameservers {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CLUSTER\tNAMESERVER\tTARGETS")
+	for _, tag := range tags {
+		targets := a.GetExternalTargets(ctx, host, map[string]string{tag: nameservers[tag]})
+		fmt.Fprintf(w, "%s\t%s\t%s\n", tag, nameservers[tag], join(targets))
+	}
+	return w.Flush()
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/logging"
+	"github.com/rs/zerolog"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const usage = `kubectl k8gb inspects and operates k8gb
+
+Usage:
+  kubectl k8gb status [gslb] [-A] [--no-dns]  show hosts of Gslbs with health, local and external targets and active
+                                             failover cluster, together with delegated nameservers and heartbeats
+  kubectl k8gb dig <host>                    resolve targets of the host from nameservers of peer clusters,
+                                             the same way k8gb does
+  kubectl k8gb drain <gslb> [--off]          take the Gslb out of global rotation, or return it back
+  kubectl k8gb failover <gslb> <geotag>      make cluster of the geo tag primary for failover strategy
+
+Flags:
+`
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(k8gbv1beta1.AddToScheme(scheme))
+}
+
+// options are flags common to all commands
+type options struct {
+	kubeconfig    string
+	context       string
+	namespace     string
+	k8gbNamespace string
+	verbose       bool
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.kubeconfig, "kubeconfig", "", "path to the kubeconfig file")
+	fs.StringVar(&o.context, "context", "", "kubeconfig context to use")
+	fs.StringVar(&o.namespace, "n", "", "namespace of Gslbs; defaults to namespace of the kubeconfig context")
+	fs.StringVar(&o.k8gbNamespace, "k8gb-namespace", "k8gb", "namespace of k8gb deployment")
+	fs.BoolVar(&o.verbose, "v", false, "log DNS queries and k8gb internals to stderr")
+}
+
+// plugin runs commands against the cluster of kubeconfig context
+type plugin struct {
+	client        client.Client
+	namespace     string
+	k8gbNamespace string
+	out           io.Writer
+}
+
+// warn prints warnings about results which may differ from what the operator sees
+func (p *plugin) warn(warnings []string) {
+	for _, w := range warnings {
+		fmt.Fprintf(p.out, "Warning: %s\n", w)
+	}
+}
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(ctx context.Context, args []string, out io.Writer) error {
+	if len(args) == 0 {
+		args = []string{"-h"}
+	}
+	command := args[0]
+	fs := flag.NewFlagSet("kubectl k8gb "+command, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+	var o options
+	o.register(fs)
+	allNamespaces := fs.Bool("A", false, "status: show Gslbs of all namespaces")
+	noDNS := fs.Bool("no-dns", false, "status: show only Gslb status, without querying edge DNS and nameservers of clusters")
+	off := fs.Bool("off", false, "drain: return the Gslb to global rotation")
+	if command == "-h" || command == "--help" || command == "help" {
+		fs.Usage()
+		return nil
+	}
+	positional, err := parseArgs(fs, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	level := zerolog.ErrorLevel
+	if o.verbose {
+		level = zerolog.DebugLevel
+	}
+	logging.Init(&depresolver.Config{Log: depresolver.Log{Level: level, Format: depresolver.SimpleFormat}})
+
+	arg := func(i int, name string) (string, error) {
+		if len(positional) <= i {
+			return "", fmt.Errorf("%s expects %s argument", command, name)
+		}
+		return positional[i], nil
+	}
+	var p *plugin
+	switch command {
+	case "status", "dig", "drain", "failover":
+		p, err = newPlugin(o, out)
+		if err != nil {
+			return err
+		}
+	default:
+		fs.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+	switch command {
+	case "status":
+		var name string
+		if len(positional) > 0 {
+			name = positional[0]
+		}
+		return p.status(ctx, name, *allNamespaces, *noDNS)
+	case "dig":
+		host, err := arg(0, "host")
+		if err != nil {
+			return err
+		}
+		return p.dig(ctx, host)
+	case "drain":
+		name, err := arg(0, "gslb")
+		if err != nil {
+			return err
+		}
+		return p.drain(ctx, name, *off)
+	default:
+		name, err := arg(0, "gslb")
+		if err != nil {
+			return err
+		}
+		geoTag, err := arg(1, "geotag")
+		if err != nil {
+			return err
+		}
+		return p.failover(ctx, name, geoTag)
+	}
+}
+
+// parseArgs parses flags placed both before and after positional arguments, as kubectl does
+func parseArgs(fs *flag.FlagSet, args []string) (positional []string, err error) {
+	for {
+		if err = fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func newPlugin(o options, out io.Writer) (*plugin, error) {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = o.kubeconfig
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{CurrentContext: o.context})
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	namespace := o.namespace
+	if namespace == "" {
+		if namespace, _, err = clientConfig.Namespace(); err != nil {
+			return nil, err
+		}
+	}
+	c, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, err
+	}
+	return &plugin{client: c, namespace: namespace, k8gbNamespace: o.k8gbNamespace, out: out}, nil
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package main
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const namespace = "test-gslb"
+
+func TestConfigFromEnvResolvesNameserversOfClusters(t *testing.T) {
+	// arrange
+	env := []corev1.EnvVar{
+		{Name: depresolver.ClusterGeoTagKey, Value: "eu"},
+		{Name: depresolver.ExtClustersGeoTagsKey, Value: "us, za"},
+		{Name: depresolver.EdgeDNSZoneKey, Value: "example.com"},
+		{Name: depresolver.DNSZoneKey, Value: "cloud.example.com"},
+		{Name: depresolver.EdgeDNSServerKey, Value: "1.1.1.1"},
+		{Name: depresolver.InfobloxPasswordKey, ValueFrom: &corev1.EnvVarSource{}},
+	}
+	// act
+	config := configFromEnv(env)
+	// assert
+	assert.Equal(t, 53, config.EdgeDNSServerPort)
+	assert.Equal(t, "gslb-ns-eu-cloud.example.com", config.GetClusterNSName())
+	assert.Equal(t, map[string]string{"us": "gslb-ns-us-cloud.example.com", "za": "gslb-ns-za-cloud.example.com"},
+		config.GetExternalClusterNSNames())
+}
+
+func TestOperatorAssistantReadsSecretsOfOperator(t *testing.T) {
+	// arrange
+	env := []corev1.EnvVar{
+		{Name: depresolver.ClusterGeoTagKey, Value: "eu"},
+		{Name: depresolver.TSIGKeyNameKey, Value: "k8gb-peer"},
+		secretEnv(depresolver.TSIGSecretKey, "k8gb-tsig"),
+		secretEnv(depresolver.HeartbeatKeyKey, "k8gb-heartbeat"),
+	}
+	p, _ := providePlugin(operatorDeployment(env, nil, nil),
+		secret("k8gb-tsig", depresolver.TSIGSecretKey, "c2VjcmV0"),
+		secret("k8gb-heartbeat", depresolver.HeartbeatKeyKey, "heartbeat-secret"))
+	// act
+	config, a, warnings, err := operatorAssistant(context.TODO(), p.client, p.k8gbNamespace)
+	// assert
+	require.NoError(t, err)
+	assert.NotNil(t, a)
+	assert.Empty(t, warnings)
+	assert.Equal(t, "k8gb-peer", config.DNSSecurity.TSIGKeyName)
+	assert.Equal(t, "hmac-sha256", config.DNSSecurity.TSIGAlgorithm)
+	assert.Equal(t, depresolver.HeartbeatBackendDNS, config.Heartbeat.Backend)
+}
+
+func TestDigReportsUnsignedLookups(t *testing.T) {
+	// arrange
+	env := []corev1.EnvVar{
+		{Name: depresolver.ClusterGeoTagKey, Value: "eu"},
+		{Name: depresolver.TSIGKeyNameKey, Value: "k8gb-peer"},
+		secretEnv(depresolver.TSIGSecretKey, "k8gb-tsig"),
+	}
+	p, out := providePlugin(operatorDeployment(env, nil, nil))
+	// act
+	err := p.dig(context.TODO(), "app.cloud.example.com")
+	// assert
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Warning: lookups of peer clusters aren't signed by TSIG key k8gb-peer")
+}
+
+func TestOperatorAssistantReadsHeartbeatsFromLeases(t *testing.T) {
+	// arrange
+	env := []corev1.EnvVar{
+		{Name: depresolver.ClusterGeoTagKey, Value: "eu"},
+		{Name: depresolver.HeartbeatBackendKey, Value: depresolver.HeartbeatBackendLease},
+	}
+	mounts := []corev1.VolumeMount{{Name: "heartbeat-lease-kubeconfig", MountPath: "/etc/k8gb/heartbeat-lease"}}
+	volumes := []corev1.Volume{{Name: "heartbeat-lease-kubeconfig", VolumeSource: corev1.VolumeSource{
+		Secret: &corev1.SecretVolumeSource{SecretName: "k8gb-heartbeat-lease"}}}}
+	p, _ := providePlugin(operatorDeployment(env, mounts, volumes), secret("k8gb-heartbeat-lease", "kubeconfig", hubKubeconfig))
+	// act
+	_, _, warnings, err := operatorAssistant(context.TODO(), p.client, p.k8gbNamespace)
+	// assert
+	require.NoError(t, err)
+	assert.Empty(t, warnings)
+}
+
+func TestHeartbeatsOfUnreachableLeasesAreUnknown(t *testing.T) {
+	// arrange
+	env := []corev1.EnvVar{
+		{Name: depresolver.ClusterGeoTagKey, Value: "eu"},
+		{Name: depresolver.HeartbeatBackendKey, Value: depresolver.HeartbeatBackendLease},
+	}
+	p, _ := providePlugin(operatorDeployment(env, nil, nil))
+	// act
+	_, a, warnings, err := operatorAssistant(context.TODO(), p.client, p.k8gbNamespace)
+	// assert
+	require.NoError(t, err)
+	require.Len(t, warnings, 1)
+	assert.Contains(t, warnings[0], "heartbeats can't be read from Leases of shared cluster")
+	_, err = a.HeartbeatAge("test-gslb-heartbeat-eu.example.com")
+	assert.Error(t, err, "heartbeats must not be read from edge DNS")
+}
+
+func TestActiveClusterOfFailoverHost(t *testing.T) {
+	// arrange
+	byCluster := map[string][]string{"eu": {"10.0.0.1"}, "us": {"10.1.0.1", "10.1.0.2"}}
+	// act,assert
+	assert.Equal(t, "us", activeCluster([]string{"10.1.0.1", "10.1.0.2"}, byCluster))
+	assert.Equal(t, "eu", activeCluster([]string{"10.0.0.1"}, byCluster))
+	assert.Equal(t, metrics.NoActiveCluster, activeCluster(nil, byCluster))
+}
+
+func TestDrainTogglesAnnotation(t *testing.T) {
+	// arrange
+	p, out := providePlugin(gslb(k8gbv1beta1.Strategy{Type: "roundRobin"}, nil))
+	// act
+	err := p.drain(context.TODO(), "test-gslb", false)
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "true", getGslb(t, p).Annotations[controllers.DrainAnnotation])
+	assert.Contains(t, out.String(), "taken out of global rotation")
+	// act
+	err = p.drain(context.TODO(), "test-gslb", true)
+	// assert
+	require.NoError(t, err)
+	assert.NotContains(t, getGslb(t, p).Annotations, controllers.DrainAnnotation)
+}
+
+func TestFailoverChangesPrimaryGeoTagOfGslb(t *testing.T) {
+	// arrange
+	p, _ := providePlugin(gslb(k8gbv1beta1.Strategy{Type: failoverStrategy, PrimaryGeoTag: "eu"}, nil))
+	// act
+	err := p.failover(context.TODO(), "test-gslb", "us")
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "us", getGslb(t, p).Spec.Strategy.PrimaryGeoTag)
+}
+
+func TestFailoverOfRoundRobinGslbFails(t *testing.T) {
+	// arrange
+	p, _ := providePlugin(gslb(k8gbv1beta1.Strategy{Type: "roundRobin"}, nil))
+	// act
+	err := p.failover(context.TODO(), "test-gslb", "us")
+	// assert
+	assert.Error(t, err)
+}
+
+func TestFailoverAnnotatesIngressOfAnnotatedGslb(t *testing.T) {
+	// arrange
+	annotations := map[string]string{controllers.StrategyAnnotation: failoverStrategy, controllers.PrimaryGeoTagAnnotation: "eu"}
+	ingress := &v1beta1.Ingress{ObjectMeta: metav1.ObjectMeta{Name: "test-gslb", Namespace: namespace, Annotations: annotations}}
+	p, _ := providePlugin(gslb(k8gbv1beta1.Strategy{Type: failoverStrategy, PrimaryGeoTag: "eu"}, annotations), ingress)
+	// act
+	err := p.failover(context.TODO(), "test-gslb", "us")
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, p.client.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: "test-gslb"}, ingress))
+	assert.Equal(t, "us", ingress.Annotations[controllers.PrimaryGeoTagAnnotation])
+	assert.Equal(t, "eu", getGslb(t, p).Spec.Strategy.PrimaryGeoTag, "Gslb is updated by Ingress controller")
+}
+
+func TestStatusWithoutDNSShowsHostsOfGslb(t *testing.T) {
+	// arrange
+	g := gslb(k8gbv1beta1.Strategy{Type: failoverStrategy, PrimaryGeoTag: "eu"}, nil)
+	g.Status.ServiceHealth = map[string]string{"app.cloud.example.com": "Healthy"}
+	g.Status.HealthyRecords = map[string][]string{"app.cloud.example.com": {"10.0.0.1", "10.0.0.2"}}
+	p, out := providePlugin(g)
+	// act
+	err := p.status(context.TODO(), "", false, true)
+	// assert
+	require.NoError(t, err)
+	assert.Regexp(t, `test-gslb\s+test-gslb\s+app.cloud.example.com\s+failover\s+Healthy\s+10.0.0.1,10.0.0.2\s+-\s+-\s+-`, out.String())
+}
+
+func TestUnknownCommandFails(t *testing.T) {
+	// act
+	err := run(context.TODO(), []string{"promote"}, &bytes.Buffer{})
+	// assert
+	assert.Error(t, err)
+}
+
+func gslb(strategy k8gbv1beta1.Strategy, annotations map[string]string) *k8gbv1beta1.Gslb {
+	return &k8gbv1beta1.Gslb{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-gslb", Namespace: namespace, Annotations: annotations},
+		Spec: k8gbv1beta1.GslbSpec{
+			Ingress:  k8gbv1beta1.IngressSpec{Rules: []k8gbv1beta1.IngressRule{{Host: "app.cloud.example.com"}}},
+			Strategy: strategy,
+		},
+	}
+}
+
+func providePlugin(objects ...runtime.Object) (*plugin, *bytes.Buffer) {
+	out := &bytes.Buffer{}
+	c := fake.NewFakeClientWithScheme(scheme, objects...)
+	return &plugin{client: c, namespace: namespace, k8gbNamespace: "k8gb", out: out}, out
+}
+
+// hubKubeconfig points to unreachable shared cluster, which isn't contacted until heartbeats are read
+const hubKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: hub
+  cluster:
+    server: https://127.0.0.1:1
+contexts:
+- name: hub
+  context:
+    cluster: hub
+current-context: hub
+`
+
+func operatorDeployment(env []corev1.EnvVar, mounts []corev1.VolumeMount, volumes []corev1.Volume) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: k8gbDeployment, Namespace: "k8gb"},
+		Spec: appsv1.DeploymentSpec{Template: corev1.PodTemplateSpec{Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: k8gbDeployment, Env: env, VolumeMounts: mounts}},
+			Volumes:    volumes,
+		}}},
+	}
+}
+
+func secretEnv(name, secretName string) corev1.EnvVar {
+	return corev1.EnvVar{Name: name, ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+		LocalObjectReference: corev1.LocalObjectReference{Name: secretName}, Key: name}}}
+}
+
+func secret(name, key, value string) *corev1.Secret {
+	return &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "k8gb"}, Data: map[string][]byte{key: []byte(value)}}
+}
+
+func getGslb(t *testing.T, p *plugin) *k8gbv1beta1.Gslb {
+	t.Helper()
+	gslb := &k8gbv1beta1.Gslb{}
+	require.NoError(t, p.client.Get(context.TODO(), client.ObjectKey{Namespace: namespace, Name: "test-gslb"}, gslb))
+	return gslb
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// none is printed in place of values which are unknown or empty
+const none = "-"
+
+// status prints hosts of Gslbs with their health, local and external targets and active failover cluster. Unless
+// noDNS, targets of clusters are resolved from their nameservers, delegation from edge DNS and heartbeats from edge
+// DNS or from the heartbeat backend of the operator
+func (p *plugin) status(ctx context.Context, name string, allNamespaces, noDNS bool) error {
+	gslbs, err := p.gslbs(ctx, name, allNamespaces)
+	if err != nil {
+		return err
+	}
+	var config *depresolver.Config
+	var a *assistant.Gslb
+	if !noDNS {
+		var warnings []string
+		if config, a, warnings, err = operatorAssistant(ctx, p.client, p.k8gbNamespace); err != nil {
+			return err
+		}
+		p.warn(warnings)
+	}
+
+	w := tabwriter.NewWriter(p.out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAMESPACE\tGSLB\tHOST\tSTRATEGY\tHEALTH\tTARGETS\tLOCAL\tEXTERNAL\tACTIVE")
+	for _, gslb := range gslbs {
+		for _, host := range gslbHosts(gslb) {
+			strategy := gslb.Spec.Strategy
+			if s, found := gslb.Status.Strategies[host]; found {
+				strategy = s
+			}
+			targets := gslb.Status.HealthyRecords[host]
+			local, external, active := none, none, none
+			if !noDNS {
+				byCluster := clusterTargets(ctx, a, config, host)
+				local = join(byCluster[config.ClusterGeoTag])
+				var externalTargets []string
+				for _, tag := range config.ExtClustersGeoTags {
+					externalTargets = append(externalTargets, byCluster[tag]...)
+				}
+				external = join(externalTargets)
+				if strategy.Type == "failover" {
+					active = activeCluster(targets, byCluster)
+				}
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", gslb.Namespace, gslb.Name, host, strategy.Type,
+				orNone(gslb.Status.ServiceHealth[host]), join(targets), local, external, active)
+		}
+	}
+	if err = w.Flush(); err != nil || noDNS {
+		return err
+	}
+
+	nameservers, err := a.LookupRecords(config.DNSZone, "NS")
+	if err != nil {
+		fmt.Fprintf(p.out, "\nDelegated zone %s: unknown (%s)\n", config.DNSZone, err)
+	} else {
+		sort.Strings(nameservers)
+		fmt.Fprintf(p.out, "\nDelegated zone %s: %s\n", config.DNSZone, join(nameservers))
+	}
+
+	fmt.Fprintln(p.out)
+	fmt.Fprintln(w, "HEARTBEAT\tCLUSTER\tAGE")
+	for _, gslb := range gslbs {
+		heartbeats := config.GetExternalClusterHeartbeatFQDNs(gslb.Name)
+		heartbeats[config.ClusterGeoTag] = config.GetClusterHeartbeatFQDN(gslb.Name)
+		for _, tag := range append([]string{config.ClusterGeoTag}, config.ExtClustersGeoTags...) {
+			age := "unknown"
+			if d, err := a.HeartbeatAge(heartbeats[tag]); err == nil {
+				age = d.Round(time.Second).String()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", heartbeats[tag], tag, age)
+		}
+	}
+	return w.Flush()
+}
+
+// gslbs returns Gslbs of the namespace, or of all namespaces, ordered by namespace and name. If name is given,
+// only the Gslb of that name is returned
+func (p *plugin) gslbs(ctx context.Context, name string, allNamespaces bool) ([]k8gbv1beta1.Gslb, error) {
+	if name != "" && !allNamespaces {
+		gslb := k8gbv1beta1.Gslb{}
+		err := p.client.Get(ctx, client.ObjectKey{Namespace: p.namespace, Name: name}, &gslb)
+		return []k8gbv1beta1.Gslb{gslb}, err
+	}
+	list := &k8gbv1beta1.GslbList{}
+	var opts []client.ListOption
+	if !allNamespaces {
+		opts = append(opts, client.InNamespace(p.namespace))
+	}
+	if err := p.client.List(ctx, list, opts...); err != nil {
+		return nil, err
+	}
+	var gslbs []k8gbv1beta1.Gslb
+	for _, gslb := range list.Items {
+		if name == "" || gslb.Name == name {
+			gslbs = append(gslbs, gslb)
+		}
+	}
+	sort.Slice(gslbs, func(i, j int) bool {
+		if gslbs[i].Namespace != gslbs[j].Namespace {
+			return gslbs[i].Namespace < gslbs[j].Namespace
+		}
+		return gslbs[i].Name < gslbs[j].Name
+	})
+	return gslbs, nil
+}
+
+// gslbHosts returns hosts of the Gslb in order of its rules
+func gslbHosts(gslb k8gbv1beta1.Gslb) (hosts []string) {
+	for _, rule := range gslb.Spec.Ingress.Rules {
+		hosts = append(hosts, rule.Host)
+	}
+	return hosts
+}
+
+// clusterTargets resolves local targets of the host from nameserver of every cluster, including this one, the same
+// way k8gb resolves external targets
+func clusterTargets(ctx context.Context, a *assistant.Gslb, config *depresolver.Config, host string) map[string][]string {
+	nameservers := config.GetExternalClusterNSNames()
+	nameservers[config.ClusterGeoTag] = config.GetClusterNSName()
+	targets := make(map[string][]string, len(nameservers))
+	for tag, nameserver := range nameservers {
+		targets[tag] = a.GetExternalTargets(ctx, host, map[string]string{tag: nameserver})
+	}
+	return targets
+}
+
+// activeCluster returns geo tags of clusters whose targets are published for the failover host, or none
+func activeCluster(published []string, byCluster map[string][]string) string {
+	var active []string
+	for tag, targets := range byCluster {
+		for _, target := range targets {
+			if contains(published, target) {
+				active = append(active, tag)
+				break
+			}
+		}
+	}
+	if len(active) == 0 {
+		return metrics.NoActiveCluster
+	}
+	sort.Strings(active)
+	return strings.Join(active, ",")
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func join(list []string) string {
+	return orNone(strings.Join(list, ","))
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return none
+	}
+	return s
+}
@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+	"github.com/AbsaOSS/k8gb/controllers/providers/dns"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+)
+
+// DebugState keeps DNSEndpoints last computed for Gslbs, including those not written in dry-run mode. Nil state
+// records nothing
+type DebugState struct {
+	mu        sync.RWMutex
+	endpoints map[types.NamespacedName]*externaldns.DNSEndpoint
+}
+
+// NewDebugState creates empty state
+func NewDebugState() *DebugState {
+	return &DebugState{endpoints: make(map[types.NamespacedName]*externaldns.DNSEndpoint)}
+}
+
+func (s *DebugState) recordDNSEndpoint(gslb *k8gbv1beta1.Gslb, dnsEndpoint *externaldns.DNSEndpoint) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.endpoints[types.NamespacedName{Namespace: gslb.Namespace, Name: gslb.Name}] = dnsEndpoint.DeepCopy()
+}
+
+func (s *DebugState) forget(gslb *k8gbv1beta1.Gslb) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.endpoints, types.NamespacedName{Namespace: gslb.Namespace, Name: gslb.Name})
+}
+
+func (s *DebugState) dnsEndpoint(key types.NamespacedName) *externaldns.DNSEndpoint {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.endpoints[key]
+}
+
+// lookupRecorder is assistant keeping results of its last lookups
+type lookupRecorder interface {
+	assistant.Assistant
+	PeerLookups() []assistant.PeerLookup
+	Heartbeats() []assistant.Heartbeat
+}
+
+// DebugHandler serves internal decision state of the operator as JSON
+type DebugHandler struct {
+	client    client.Reader
+	config    *depresolver.Config
+	state     *DebugState
+	assistant lookupRecorder
+	provider  dns.Provider
+}
+
+// debugReport is the body served by DebugHandler
+type debugReport struct {
+	// Config of the operator with credentials redacted
+	Config depresolver.Config `json:"config"`
+	// Provider of edge DNS
+	Provider string `json:"provider"`
+	// Gslbs holds state keyed by namespace/name of the Gslb
+	Gslbs map[string]debugGslb `json:"gslbs"`
+	// PeerLookups are the last lookups of targets from nameservers of other clusters
+	PeerLookups []assistant.PeerLookup `json:"peerLookups"`
+	// Heartbeats are the last inspections of heartbeat records; they are inspected with split brain check only
+	Heartbeats []assistant.Heartbeat `json:"heartbeats"`
+	// DelegatedZone is delegation of DNSZone resolved from edge DNS when the report is served
+	DelegatedZone      *dns.DelegatedZone `json:"delegatedZone,omitempty"`
+	DelegatedZoneError string             `json:"delegatedZoneError,omitempty"`
+}
+
+// debugGslb is state of single Gslb
+type debugGslb struct {
+	// DryRun is true when DNS changes of the Gslb are not written
+	DryRun bool `json:"dryRun"`
+	// DNSEndpoint last computed for the Gslb; empty until the Gslb is reconciled
+	DNSEndpoint *externaldns.DNSEndpointSpec `json:"dnsEndpoint,omitempty"`
+}
+
+// NewDebugHandler creates handler of state recorded by reconciler and assistant
+func NewDebugHandler(c client.Reader, config *depresolver.Config, state *DebugState, a lookupRecorder,
+	provider dns.Provider) *DebugHandler {
+	return &DebugHandler{client: c, config: config, state: state, assistant: a, provider: provider}
+}
+
+func (h *DebugHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	gslbList := &k8gbv1beta1.GslbList{}
+	if err := h.client.List(req.Context(), gslbList); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	report := debugReport{
+		Config:      h.config.Redacted(),
+		Provider:    providerName(h.provider),
+		Gslbs:       make(map[string]debugGslb, len(gslbList.Items)),
+		PeerLookups: h.assistant.PeerLookups(),
+		Heartbeats:  h.assistant.Heartbeats(),
+	}
+	for i := range gslbList.Items {
+		gslb := &gslbList.Items[i]
+		state := debugGslb{DryRun: isDryRun(h.config, gslb)}
+		if dnsEndpoint := h.state.dnsEndpoint(types.NamespacedName{Namespace: gslb.Namespace, Name: gslb.Name}); dnsEndpoint != nil {
+			state.DNSEndpoint = &dnsEndpoint.Spec
+		}
+		report.Gslbs[fmt.Sprintf("%s/%s", gslb.Namespace, gslb.Name)] = state
+	}
+	zone, err := dns.ResolveDelegatedZone(*h.config, h.assistant)
+	if err != nil {
+		report.DelegatedZoneError = err.Error()
+	}
+	report.DelegatedZone = zone
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Err(err).Msg("Can't write debug report")
+	}
+}
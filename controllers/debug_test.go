@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// recordingAssistant returns fixed results of the last lookups
+type recordingAssistant struct {
+	*assistant.MockAssistant
+	peerLookups []assistant.PeerLookup
+}
+
+func (a *recordingAssistant) PeerLookups() []assistant.PeerLookup {
+	return a.peerLookups
+}
+
+func (a *recordingAssistant) Heartbeats() []assistant.Heartbeat {
+	return nil
+}
+
+func TestDebugHandlerServesDecisionState(t *testing.T) {
+	// arrange
+	config := predefinedConfig
+	config.Infoblox.Password = "secret"
+	settings := provideSettings(t, config)
+	settings.reconciler.Debug = NewDebugState()
+	settings.ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+	require.NoError(t, settings.client.Status().Update(context.TODO(), settings.ingress))
+	createHealthyService(t, &settings, "frontend-podinfo")
+	_, err := settings.reconciler.Reconcile(context.TODO(), settings.request)
+	require.NoError(t, err)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	a := &recordingAssistant{MockAssistant: assistant.NewMockAssistant(ctrl), peerLookups: []assistant.PeerLookup{
+		{Peer: "za", Nameserver: "gslb-ns-za-cloud.example.com", Host: "roundrobin.cloud.example.com", Error: "timeout"},
+	}}
+	a.EXPECT().LookupRecords(config.DNSZone, "NS").Return(nil, fmt.Errorf("edge DNS unavailable")).Times(1)
+	handler := NewDebugHandler(settings.client, &config, settings.reconciler.Debug, a, settings.reconciler.DNSProvider)
+	recorder := httptest.NewRecorder()
+	// act
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/state", nil))
+	// assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.NotContains(t, recorder.Body.String(), "secret", "credentials are redacted")
+	report := debugReport{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	gslb := report.Gslbs[settings.gslb.Namespace+"/"+settings.gslb.Name]
+	require.NotNil(t, gslb.DNSEndpoint, "computed DNSEndpoint is served")
+	assert.Contains(t, gslb.DNSEndpoint.Endpoints[0].Targets, "10.0.0.1")
+	assert.Equal(t, "timeout", report.PeerLookups[0].Error)
+	assert.Equal(t, "edge DNS unavailable", report.DelegatedZoneError)
+}
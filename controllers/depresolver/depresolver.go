@@ -26,8 +26,6 @@ package depresolver
 import (
 	"sync"
 
-	"github.com/AbsaOSS/k8gb/api/v1beta1"
-
 	"github.com/rs/zerolog"
 )
 
@@ -71,7 +69,11 @@ const (
 	DNSTypeRoute53 EdgeDNSType = "Route53"
 	// DNSTypeNS1 type
 	DNSTypeNS1 EdgeDNSType = "NS1"
-	// DNSTypeMultipleProviders type
+	// DNSTypePowerDNS type
+	DNSTypePowerDNS EdgeDNSType = "PowerDNS"
+	// DNSTypePlugin is out-of-process provider talking gRPC over Unix socket
+	DNSTypePlugin EdgeDNSType = "Plugin"
+	// DNSTypeMultipleProviders is set when several edge DNS providers are configured; zone delegation is fanned out to all of them
 	DNSTypeMultipleProviders EdgeDNSType = "MultipleProviders"
 )
 
@@ -101,6 +103,53 @@ type Infoblox struct {
 	HTTPRequestTimeout int
 	// HTTPPoolConnections seconds; default = 10
 	HTTPPoolConnections int
+	// SSLVerify enables verification of WAPI server certificate; default = true
+	SSLVerify bool
+	// CABundle is path to PEM encoded CA certificates used to verify WAPI server instead of system roots
+	CABundle string
+	// UsernameFile is path to mounted secret file with username; takes precedence over Username
+	UsernameFile string
+	// PasswordFile is path to mounted secret file with password; takes precedence over Password
+	PasswordFile string
+}
+
+// PowerDNS configuration
+type PowerDNS struct {
+	// APIURL of PowerDNS Authoritative HTTP API; e.g. http://pdns.example.com:8081
+	APIURL string
+	// APIKey sent in X-API-Key header
+	APIKey string
+	// ServerID; default = localhost
+	ServerID string
+	// HTTPRequestTimeout seconds; default = 20
+	HTTPRequestTimeout int
+}
+
+// ProviderPlugin configuration
+type ProviderPlugin struct {
+	// Socket is path to Unix socket the plugin listens on; e.g. /var/run/k8gb/provider.sock
+	Socket string
+	// RequestTimeout seconds; default = 20
+	RequestTimeout int
+}
+
+// ZoneDelegation configuration of cluster-wide delegation of DNSZone to the cluster nameserver
+type ZoneDelegation struct {
+	// TTL of NS and glue records in seconds; default = 30
+	TTL int
+	// GlueIPs are static addresses of the cluster nameserver. If empty, exposed CoreDNS addresses are used
+	// when CoreDNSExposed, otherwise addresses of all Gslb ingresses
+	GlueIPs []string
+}
+
+// Drain configuration of taking the cluster out of global rotation
+type Drain struct {
+	// Enabled takes all Gslbs of the cluster out of rotation; single Gslb is drained by annotation
+	Enabled bool
+	// TTL of records of draining Gslb in seconds; default = 5
+	TTL int
+	// PeriodSeconds keeps targets of the cluster published with lowered TTL before removing them; default = 60
+	PeriodSeconds int
 }
 
 // Override configuration
@@ -109,6 +158,67 @@ type Override struct {
 	FakeInfobloxEnabled bool
 }
 
+// Tracing configuration of OpenTelemetry traces exported over OTLP gRPC
+type Tracing struct {
+	// Enabled exports spans of reconciliation; default = false
+	Enabled bool
+	// Endpoint of OTLP collector in form host:port; default = localhost:4317
+	Endpoint string
+	// Insecure disables TLS of connection to the collector; default = false
+	Insecure bool
+}
+
+// Audit configuration of history of DNS target changes
+type Audit struct {
+	// HistorySize is number of the last target changes kept per host in Gslb status, 0 disables history; default = 10
+	HistorySize int
+	// LogEnabled writes target changes to dedicated JSON audit log stream; default = false
+	LogEnabled bool
+}
+
+// PeerStatus configuration of status API exchanged between clusters over mutual TLS
+type PeerStatus struct {
+	// Enabled serves status of the cluster to peers and consumes status of peers; default = false
+	Enabled bool
+	// Port of the status API; default = 8443
+	Port int
+	// CertDir is directory with tls.crt and tls.key of the cluster and ca.crt verifying peers; default = /etc/k8gb/peer-status
+	CertDir string
+	// Endpoints of status API of peers in form host:port, by geo tag. Targets of peers without endpoint are resolved
+	// from DNS only
+	Endpoints map[string]string
+}
+
+// DNSSecurity configuration of authentication of DNS data exchanged between clusters
+type DNSSecurity struct {
+	// TSIGKeyName of the key signing lookups of targets from nameservers of peer clusters; lookups aren't signed when empty
+	TSIGKeyName string
+	// TSIGAlgorithm of the key, e.g. hmac-sha256; default = hmac-sha256
+	TSIGAlgorithm string
+	// TSIGSecret is base64 encoded secret of the key
+	TSIGSecret string
+	// HeartbeatKey is secret shared by all clusters, signing heartbeat TXT records; heartbeats aren't signed when empty
+	HeartbeatKey string
+}
+
+// Heartbeat backends
+const (
+	// HeartbeatBackendDNS stores heartbeats as TXT records in edge DNS
+	HeartbeatBackendDNS = "dns"
+	// HeartbeatBackendLease stores heartbeats as coordination.k8s.io Leases in a cluster shared by all clusters
+	HeartbeatBackendLease = "lease"
+)
+
+// Heartbeat configuration of the backend storing heartbeats of the split brain check
+type Heartbeat struct {
+	// Backend storing heartbeats, dns or lease; default = dns
+	Backend string
+	// LeaseKubeconfig is path to kubeconfig of the shared cluster; default = /etc/k8gb/heartbeat-lease/kubeconfig
+	LeaseKubeconfig string
+	// LeaseNamespace of Leases in the shared cluster; default = k8gb
+	LeaseNamespace string
+}
+
 // Config is operator configuration returned by depResolver
 type Config struct {
 	// Reschedule of Reconcile loop to pickup external Gslb targets
@@ -129,8 +239,26 @@ type Config struct {
 	DNSZone string
 	// K8gbNamespace k8gb namespace
 	K8gbNamespace string
+	// K8gbVersion version of the operator, published in heartbeats
+	K8gbVersion string
 	// Infoblox configuration
 	Infoblox Infoblox
+	// PowerDNS configuration
+	PowerDNS PowerDNS
+	// ProviderPlugin configuration
+	ProviderPlugin ProviderPlugin
+	// ZoneDelegation configuration
+	ZoneDelegation ZoneDelegation
+	// Drain configuration
+	Drain Drain
+	// Tracing configuration
+	Tracing Tracing
+	// Audit configuration
+	Audit Audit
+	// PeerStatus configuration
+	PeerStatus PeerStatus
+	// DNSSecurity configuration
+	DNSSecurity DNSSecurity
 	// Override the behavior of GSLB in the test environments
 	Override Override
 	// CoreDNSExposed flag
@@ -139,12 +267,20 @@ type Config struct {
 	Log Log
 	// MetricsAddress in format address:port where address can be empty, IP address, or hostname, default: 0.0.0.0:8080
 	MetricsAddress string
+	// DebugAPI serves internal state of the operator as JSON at /debug/state of MetricsAddress; default = false
+	DebugAPI bool
 	// route53Enabled hidden. EdgeDNSType defines all enabled Enabled types
 	route53Enabled bool
 	// ns1Enabled flag
 	ns1Enabled bool
 	// SplitBrainCheck flag decides whether split brain TXT records will be stored in edge DNS
 	SplitBrainCheck bool
+	// SplitBrainQuorum flag; stale cluster is removed from delegation only when majority of clusters consider it stale
+	SplitBrainQuorum bool
+	// Heartbeat configuration
+	Heartbeat Heartbeat
+	// DryRun flag; DNS changes of all Gslbs are computed and reported, but not written
+	DryRun bool
 }
 
 // DependencyResolver resolves configuration for GSLB
@@ -152,8 +288,6 @@ type DependencyResolver struct {
 	config      *Config
 	onceConfig  sync.Once
 	errorConfig error
-	errorSpec   error
-	spec        v1beta1.GslbSpec
 }
 
 // NewDependencyResolver returns a new depresolver.DependencyResolver
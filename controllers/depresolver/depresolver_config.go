@@ -18,7 +18,9 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package depresolver
 
 import (
+	"encoding/base64"
 	"fmt"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -47,18 +49,59 @@ const (
 	InfobloxHTTPPoolConnectionsKey = "INFOBLOX_HTTP_POOL_CONNECTIONS"
 	OverrideFakeInfobloxKey        = "FAKE_INFOBLOX"
 	K8gbNamespaceKey               = "POD_NAMESPACE"
+	K8gbVersionKey                 = "K8GB_VERSION"
 	CoreDNSExposedKey              = "COREDNS_EXPOSED"
 	LogLevelKey                    = "LOG_LEVEL"
 	LogFormatKey                   = "LOG_FORMAT"
 	LogNoColorKey                  = "NO_COLOR"
 	SplitBrainCheckKey             = "SPLIT_BRAIN_CHECK"
+	SplitBrainQuorumKey            = "SPLIT_BRAIN_QUORUM"
 	MetricsAddressKey              = "METRICS_ADDRESS"
+	PowerDNSAPIURLKey              = "POWERDNS_API_URL"
+	PowerDNSServerIDKey            = "POWERDNS_SERVER_ID"
+	PowerDNSHTTPRequestTimeoutKey  = "POWERDNS_HTTP_REQUEST_TIMEOUT"
+	// #nosec G101; ignore false positive gosec; see: https://securego.io/docs/rules/g101.html
+	PowerDNSAPIKeyKey               = "POWERDNS_API_KEY"
+	ProviderPluginSocketKey         = "DNS_PROVIDER_PLUGIN_SOCKET"
+	ProviderPluginRequestTimeoutKey = "DNS_PROVIDER_PLUGIN_REQUEST_TIMEOUT"
+	InfobloxSSLVerifyKey            = "INFOBLOX_SSL_VERIFY"
+	InfobloxCABundleKey             = "INFOBLOX_CA_BUNDLE"
+	InfobloxUsernameFileKey         = "EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME_FILE"
+	// #nosec G101; ignore false positive gosec; see: https://securego.io/docs/rules/g101.html
+	InfobloxPasswordFileKey  = "EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD_FILE"
+	ZoneDelegationTTLKey     = "ZONE_DELEGATION_TTL_SECONDS"
+	ZoneDelegationGlueIPsKey = "ZONE_DELEGATION_GLUE_IPS"
+	DryRunKey                = "DRY_RUN"
+	DrainKey                 = "DRAIN"
+	DrainTTLKey              = "DRAIN_TTL_SECONDS"
+	DrainPeriodKey           = "DRAIN_PERIOD_SECONDS"
+	TracingEnabledKey        = "TRACING_ENABLED"
+	TracingEndpointKey       = "OTEL_EXPORTER_OTLP_ENDPOINT"
+	TracingInsecureKey       = "OTEL_EXPORTER_OTLP_INSECURE"
+	AuditHistorySizeKey      = "AUDIT_HISTORY_SIZE"
+	AuditLogEnabledKey       = "AUDIT_LOG_ENABLED"
+	DebugAPIKey              = "DEBUG_API_ENABLED"
+	PeerStatusEnabledKey     = "PEER_STATUS_ENABLED"
+	PeerStatusPortKey        = "PEER_STATUS_PORT"
+	PeerStatusCertDirKey     = "PEER_STATUS_CERT_DIR"
+	PeerStatusEndpointsKey   = "PEER_STATUS_ENDPOINTS"
+	TSIGKeyNameKey           = "TSIG_KEY_NAME"
+	TSIGAlgorithmKey         = "TSIG_ALGORITHM"
+	// #nosec G101; ignore false positive gosec; see: https://securego.io/docs/rules/g101.html
+	TSIGSecretKey = "TSIG_SECRET"
+	// #nosec G101; ignore false positive gosec; see: https://securego.io/docs/rules/g101.html
+	HeartbeatKeyKey             = "HEARTBEAT_HMAC_KEY"
+	HeartbeatBackendKey         = "HEARTBEAT_BACKEND"
+	HeartbeatLeaseKubeconfigKey = "HEARTBEAT_LEASE_KUBECONFIG"
+	HeartbeatLeaseNamespaceKey  = "HEARTBEAT_LEASE_NAMESPACE"
 )
 
+// tsigAlgorithms supported for signing of lookups
+var tsigAlgorithms = []string{"hmac-sha1", "hmac-sha224", "hmac-sha256", "hmac-sha384", "hmac-sha512"}
+
 // ResolveOperatorConfig executes once. It reads operator's configuration
 // from environment variables into &Config and validates
 func (dr *DependencyResolver) ResolveOperatorConfig() (*Config, error) {
-	var recognizedDNSTypes []EdgeDNSType
 	dr.onceConfig.Do(func() {
 		dr.config = &Config{}
 		dr.config.ReconcileRequeueSeconds, _ = env.GetEnvAsIntOrFallback(ReconcileRequeueSecondsKey, 30)
@@ -72,6 +115,7 @@ func (dr *DependencyResolver) ResolveOperatorConfig() (*Config, error) {
 		dr.config.EdgeDNSZone = env.GetEnvAsStringOrFallback(EdgeDNSZoneKey, "")
 		dr.config.DNSZone = env.GetEnvAsStringOrFallback(DNSZoneKey, "")
 		dr.config.K8gbNamespace = env.GetEnvAsStringOrFallback(K8gbNamespaceKey, "")
+		dr.config.K8gbVersion = env.GetEnvAsStringOrFallback(K8gbVersionKey, "")
 		dr.config.Infoblox.Host = env.GetEnvAsStringOrFallback(InfobloxGridHostKey, "")
 		dr.config.Infoblox.Version = env.GetEnvAsStringOrFallback(InfobloxVersionKey, "")
 		dr.config.Infoblox.Port, _ = env.GetEnvAsIntOrFallback(InfobloxPortKey, 0)
@@ -79,19 +123,53 @@ func (dr *DependencyResolver) ResolveOperatorConfig() (*Config, error) {
 		dr.config.Infoblox.Password = env.GetEnvAsStringOrFallback(InfobloxPasswordKey, "")
 		dr.config.Infoblox.HTTPPoolConnections, _ = env.GetEnvAsIntOrFallback(InfobloxHTTPPoolConnectionsKey, 10)
 		dr.config.Infoblox.HTTPRequestTimeout, _ = env.GetEnvAsIntOrFallback(InfobloxHTTPRequestTimeoutKey, 20)
+		dr.config.Infoblox.SSLVerify = env.GetEnvAsBoolOrFallback(InfobloxSSLVerifyKey, true)
+		dr.config.Infoblox.CABundle = env.GetEnvAsStringOrFallback(InfobloxCABundleKey, "")
+		dr.config.Infoblox.UsernameFile = env.GetEnvAsStringOrFallback(InfobloxUsernameFileKey, "")
+		dr.config.Infoblox.PasswordFile = env.GetEnvAsStringOrFallback(InfobloxPasswordFileKey, "")
+		dr.config.PowerDNS.APIURL = env.GetEnvAsStringOrFallback(PowerDNSAPIURLKey, "")
+		dr.config.PowerDNS.APIKey = env.GetEnvAsStringOrFallback(PowerDNSAPIKeyKey, "")
+		dr.config.PowerDNS.ServerID = env.GetEnvAsStringOrFallback(PowerDNSServerIDKey, "localhost")
+		dr.config.PowerDNS.HTTPRequestTimeout, _ = env.GetEnvAsIntOrFallback(PowerDNSHTTPRequestTimeoutKey, 20)
+		dr.config.ProviderPlugin.Socket = env.GetEnvAsStringOrFallback(ProviderPluginSocketKey, "")
+		dr.config.ProviderPlugin.RequestTimeout, _ = env.GetEnvAsIntOrFallback(ProviderPluginRequestTimeoutKey, 20)
+		dr.config.ZoneDelegation.TTL, _ = env.GetEnvAsIntOrFallback(ZoneDelegationTTLKey, 30)
+		dr.config.ZoneDelegation.GlueIPs = env.GetEnvAsArrayOfStringsOrFallback(ZoneDelegationGlueIPsKey, []string{})
+		dr.config.Drain.Enabled = env.GetEnvAsBoolOrFallback(DrainKey, false)
+		dr.config.Drain.TTL, _ = env.GetEnvAsIntOrFallback(DrainTTLKey, 5)
+		dr.config.Drain.PeriodSeconds, _ = env.GetEnvAsIntOrFallback(DrainPeriodKey, 60)
+		dr.config.Tracing.Enabled = env.GetEnvAsBoolOrFallback(TracingEnabledKey, false)
+		dr.config.Tracing.Endpoint = env.GetEnvAsStringOrFallback(TracingEndpointKey, "localhost:4317")
+		dr.config.Tracing.Insecure = env.GetEnvAsBoolOrFallback(TracingInsecureKey, false)
+		dr.config.Audit.HistorySize, _ = env.GetEnvAsIntOrFallback(AuditHistorySizeKey, 10)
+		dr.config.Audit.LogEnabled = env.GetEnvAsBoolOrFallback(AuditLogEnabledKey, false)
+		dr.config.PeerStatus.Enabled = env.GetEnvAsBoolOrFallback(PeerStatusEnabledKey, false)
+		dr.config.PeerStatus.Port, _ = env.GetEnvAsIntOrFallback(PeerStatusPortKey, 8443)
+		dr.config.PeerStatus.CertDir = env.GetEnvAsStringOrFallback(PeerStatusCertDirKey, "/etc/k8gb/peer-status")
+		dr.config.PeerStatus.Endpoints = parseEndpoints(env.GetEnvAsArrayOfStringsOrFallback(PeerStatusEndpointsKey, []string{}))
+		dr.config.DNSSecurity.TSIGKeyName = env.GetEnvAsStringOrFallback(TSIGKeyNameKey, "")
+		dr.config.DNSSecurity.TSIGAlgorithm = env.GetEnvAsStringOrFallback(TSIGAlgorithmKey, "hmac-sha256")
+		dr.config.DNSSecurity.TSIGSecret = env.GetEnvAsStringOrFallback(TSIGSecretKey, "")
+		dr.config.DNSSecurity.HeartbeatKey = env.GetEnvAsStringOrFallback(HeartbeatKeyKey, "")
 		dr.config.Override.FakeInfobloxEnabled = env.GetEnvAsBoolOrFallback(OverrideFakeInfobloxKey, false)
 		dr.config.Log.Level, _ = zerolog.ParseLevel(strings.ToLower(env.GetEnvAsStringOrFallback(LogLevelKey, zerolog.InfoLevel.String())))
 		dr.config.Log.Format = parseLogOutputFormat(strings.ToLower(env.GetEnvAsStringOrFallback(LogFormatKey, SimpleFormat.String())))
 		dr.config.Log.NoColor = env.GetEnvAsBoolOrFallback(LogNoColorKey, false)
 		dr.config.MetricsAddress = env.GetEnvAsStringOrFallback(MetricsAddressKey, "0.0.0.0:8080")
 		dr.config.SplitBrainCheck = env.GetEnvAsBoolOrFallback(SplitBrainCheckKey, false)
-		dr.config.EdgeDNSType, recognizedDNSTypes = getEdgeDNSType(dr.config)
-		dr.errorConfig = dr.validateConfig(dr.config, recognizedDNSTypes)
+		dr.config.SplitBrainQuorum = env.GetEnvAsBoolOrFallback(SplitBrainQuorumKey, false)
+		dr.config.Heartbeat.Backend = env.GetEnvAsStringOrFallback(HeartbeatBackendKey, HeartbeatBackendDNS)
+		dr.config.Heartbeat.LeaseKubeconfig = env.GetEnvAsStringOrFallback(HeartbeatLeaseKubeconfigKey, "/etc/k8gb/heartbeat-lease/kubeconfig")
+		dr.config.Heartbeat.LeaseNamespace = env.GetEnvAsStringOrFallback(HeartbeatLeaseNamespaceKey, "k8gb")
+		dr.config.DryRun = env.GetEnvAsBoolOrFallback(DryRunKey, false)
+		dr.config.DebugAPI = env.GetEnvAsBoolOrFallback(DebugAPIKey, false)
+		dr.config.EdgeDNSType, _ = getEdgeDNSType(dr.config)
+		dr.errorConfig = dr.validateConfig(dr.config)
 	})
 	return dr.config, dr.errorConfig
 }
 
-func (dr *DependencyResolver) validateConfig(config *Config, recognizedDNSTypes []EdgeDNSType) (err error) {
+func (dr *DependencyResolver) validateConfig(config *Config) (err error) {
 	const dnsNameMax = 253
 	const dnsLabelMax = 63
 	if config.Log.Level == zerolog.NoLevel {
@@ -102,9 +180,6 @@ func (dr *DependencyResolver) validateConfig(config *Config, recognizedDNSTypes
 	if config.Log.Format == NoFormat {
 		return fmt.Errorf("invalid '%s', allowed values ['','%s','%s']", LogFormatKey, JSONFormat, SimpleFormat)
 	}
-	if config.EdgeDNSType == DNSTypeMultipleProviders {
-		return fmt.Errorf("several EdgeDNS recognized %s", recognizedDNSTypes)
-	}
 	err = field(K8gbNamespaceKey, config.K8gbNamespace).isNotEmpty().matchRegexp(k8sNamespaceRegex).err
 	if err != nil {
 		return err
@@ -158,14 +233,32 @@ func (dr *DependencyResolver) validateConfig(config *Config, recognizedDNSTypes
 		if err != nil {
 			return err
 		}
-		err = field(InfobloxUsernameKey, config.Infoblox.Username).isNotEmpty().err
+		// credentials are read either from environment or from mounted secret files
+		if isNotEmpty(config.Infoblox.UsernameFile) {
+			err = absolutePath(InfobloxUsernameFileKey, config.Infoblox.UsernameFile)
+		} else {
+			err = field(InfobloxUsernameKey, config.Infoblox.Username).isNotEmpty().err
+		}
 		if err != nil {
 			return err
 		}
-		err = field(InfobloxPasswordKey, config.Infoblox.Password).isNotEmpty().err
+		if isNotEmpty(config.Infoblox.PasswordFile) {
+			err = absolutePath(InfobloxPasswordFileKey, config.Infoblox.PasswordFile)
+		} else {
+			err = field(InfobloxPasswordKey, config.Infoblox.Password).isNotEmpty().err
+		}
 		if err != nil {
 			return err
 		}
+		if isNotEmpty(config.Infoblox.CABundle) {
+			if !config.Infoblox.SSLVerify {
+				return fmt.Errorf("'%s' requires '%s' to be enabled", InfobloxCABundleKey, InfobloxSSLVerifyKey)
+			}
+			err = absolutePath(InfobloxCABundleKey, config.Infoblox.CABundle)
+			if err != nil {
+				return err
+			}
+		}
 		err = field(InfobloxHTTPPoolConnectionsKey, config.Infoblox.HTTPPoolConnections).isHigherOrEqualToZero().err
 		if err != nil {
 			return err
@@ -175,6 +268,77 @@ func (dr *DependencyResolver) validateConfig(config *Config, recognizedDNSTypes
 			return err
 		}
 	}
+	// do full PowerDNS validation only in case that API URL exists
+	if isNotEmpty(config.PowerDNS.APIURL) {
+		err = field(PowerDNSAPIURLKey, config.PowerDNS.APIURL).matchRegexp(httpURLRegex).err
+		if err != nil {
+			return err
+		}
+		err = field(PowerDNSAPIKeyKey, config.PowerDNS.APIKey).isNotEmpty().err
+		if err != nil {
+			return err
+		}
+		err = field(PowerDNSServerIDKey, config.PowerDNS.ServerID).isNotEmpty().err
+		if err != nil {
+			return err
+		}
+		err = field(PowerDNSHTTPRequestTimeoutKey, config.PowerDNS.HTTPRequestTimeout).isHigherThanZero().err
+		if err != nil {
+			return err
+		}
+	}
+	if isNotEmpty(config.ProviderPlugin.Socket) {
+		err = absolutePath(ProviderPluginSocketKey, config.ProviderPlugin.Socket)
+		if err != nil {
+			return err
+		}
+		err = field(ProviderPluginRequestTimeoutKey, config.ProviderPlugin.RequestTimeout).isHigherThanZero().err
+		if err != nil {
+			return err
+		}
+	}
+	err = field(ZoneDelegationTTLKey, config.ZoneDelegation.TTL).isHigherThanZero().err
+	if err != nil {
+		return err
+	}
+	for i, ip := range config.ZoneDelegation.GlueIPs {
+		err = field(fmt.Sprintf("%s[%v]", ZoneDelegationGlueIPsKey, i), ip).isNotEmpty().matchRegexp(ipAddressRegex).err
+		if err != nil {
+			return err
+		}
+	}
+	err = field(DrainTTLKey, config.Drain.TTL).isHigherThanZero().err
+	if err != nil {
+		return err
+	}
+	err = field(DrainPeriodKey, config.Drain.PeriodSeconds).isHigherOrEqualToZero().err
+	if err != nil {
+		return err
+	}
+	err = field(AuditHistorySizeKey, config.Audit.HistorySize).isHigherOrEqualToZero().err
+	if err != nil {
+		return err
+	}
+	if config.PeerStatus.Enabled {
+		err = validatePeerStatus(config)
+		if err != nil {
+			return err
+		}
+	}
+	err = validateDNSSecurity(config)
+	if err != nil {
+		return err
+	}
+	if config.SplitBrainQuorum {
+		err = validateSplitBrainQuorum(config)
+		if err != nil {
+			return err
+		}
+	}
+	err = validateHeartbeat(config)
+	if err != nil {
+		return err
+	}
 	validateLabels := func(label string) error {
 		labels := strings.Split(label, ".")
 		for _, l := range labels {
@@ -209,6 +373,148 @@ func (dr *DependencyResolver) validateConfig(config *Config, recognizedDNSTypes
 	if err != nil {
 		return err
 	}
+	if config.Tracing.Enabled {
+		tHost, tPort, err := parseMetricsAddr(config.Tracing.Endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid %s: expecting OTLP endpoint in form {host}:port (%s)", TracingEndpointKey, err)
+		}
+		err = field(TracingEndpointKey, tHost).matchRegexps(hostNameRegex, ipAddressRegex).err
+		if err != nil {
+			return err
+		}
+		err = field(TracingEndpointKey, tPort).isLessOrEqualTo(65535).isHigherThan(0).err
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePeerStatus(config *Config) (err error) {
+	err = field(PeerStatusPortKey, config.PeerStatus.Port).isHigherThanZero().isLessOrEqualTo(65535).err
+	if err != nil {
+		return err
+	}
+	err = absolutePath(PeerStatusCertDirKey, config.PeerStatus.CertDir)
+	if err != nil {
+		return err
+	}
+	for geoTag, endpoint := range config.PeerStatus.Endpoints {
+		if !contains(config.ExtClustersGeoTags, geoTag) {
+			return fmt.Errorf("'%s' contains endpoint of unknown cluster '%s'", PeerStatusEndpointsKey, geoTag)
+		}
+		host, port, err := parseMetricsAddr(endpoint)
+		if err != nil {
+			return fmt.Errorf("invalid %s: expecting endpoint of %s in form {host}:port (%s)", PeerStatusEndpointsKey, geoTag, err)
+		}
+		err = field(PeerStatusEndpointsKey, host).isNotEmpty().matchRegexps(hostNameRegex, ipAddressRegex).err
+		if err != nil {
+			return err
+		}
+		err = field(PeerStatusEndpointsKey, port).isHigherThanZero().isLessOrEqualTo(65535).err
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateDNSSecurity(config *Config) (err error) {
+	if isNotEmpty(config.DNSSecurity.TSIGKeyName) {
+		err = field(TSIGKeyNameKey, strings.TrimSuffix(config.DNSSecurity.TSIGKeyName, ".")).matchRegexp(hostNameRegex).err
+		if err != nil {
+			return err
+		}
+		if !contains(tsigAlgorithms, strings.TrimSuffix(strings.ToLower(config.DNSSecurity.TSIGAlgorithm), ".")) {
+			return fmt.Errorf("invalid '%s', allowed values %v", TSIGAlgorithmKey, tsigAlgorithms)
+		}
+		err = field(TSIGSecretKey, config.DNSSecurity.TSIGSecret).isNotEmpty().err
+		if err != nil {
+			return err
+		}
+		if _, err = base64.StdEncoding.DecodeString(config.DNSSecurity.TSIGSecret); err != nil {
+			return fmt.Errorf("'%s' must be base64 encoded", TSIGSecretKey)
+		}
+	}
+	if isNotEmpty(config.DNSSecurity.HeartbeatKey) {
+		// heartbeats written by plugins can't be signed by k8gb
+		for _, t := range config.GetEdgeDNSTypes() {
+			if t == DNSTypePlugin {
+				return fmt.Errorf("'%s' isn't supported with '%s'", HeartbeatKeyKey, ProviderPluginSocketKey)
+			}
+		}
+	}
+	return nil
+}
+
+func validateSplitBrainQuorum(config *Config) error {
+	if !config.SplitBrainCheck {
+		return fmt.Errorf("'%s' requires '%s'", SplitBrainQuorumKey, SplitBrainCheckKey)
+	}
+	// with two clusters, majority means both, so the other cluster would never be removed
+	if len(config.ExtClustersGeoTags) < 2 {
+		return fmt.Errorf("'%s' requires at least three clusters, '%s' must have at least two geo tags",
+			SplitBrainQuorumKey, ExtClustersGeoTagsKey)
+	}
+	// only Infoblox and PowerDNS remove stale clusters from delegation; external-dns providers don't evaluate
+	// heartbeats and plugins decide about stale clusters themselves
+	edgeDNSTypes := config.GetEdgeDNSTypes()
+	if len(edgeDNSTypes) == 0 {
+		return fmt.Errorf("'%s' requires %s or %s edge DNS", SplitBrainQuorumKey, DNSTypeInfoblox, DNSTypePowerDNS)
+	}
+	for _, t := range edgeDNSTypes {
+		if t != DNSTypeInfoblox && t != DNSTypePowerDNS {
+			return fmt.Errorf("'%s' isn't supported with %s edge DNS", SplitBrainQuorumKey, t)
+		}
+	}
+	return nil
+}
+
+func validateHeartbeat(config *Config) (err error) {
+	switch config.Heartbeat.Backend {
+	case HeartbeatBackendDNS:
+		return nil
+	case HeartbeatBackendLease:
+	default:
+		return fmt.Errorf("invalid '%s', allowed values %v", HeartbeatBackendKey,
+			[]string{HeartbeatBackendDNS, HeartbeatBackendLease})
+	}
+	err = absolutePath(HeartbeatLeaseKubeconfigKey, config.Heartbeat.LeaseKubeconfig)
+	if err != nil {
+		return err
+	}
+	err = field(HeartbeatLeaseNamespaceKey, config.Heartbeat.LeaseNamespace).isNotEmpty().matchRegexp(k8sNamespaceRegex).err
+	if err != nil {
+		return err
+	}
+	// plugins write heartbeats themselves
+	for _, t := range config.GetEdgeDNSTypes() {
+		if t == DNSTypePlugin {
+			return fmt.Errorf("'%s' isn't supported with '%s'", HeartbeatBackendKey, ProviderPluginSocketKey)
+		}
+	}
+	return nil
+}
+
+// parseEndpoints parses items in form geotag=host:port; malformed items are kept with empty endpoint,
+// so the validation fails
+func parseEndpoints(items []string) map[string]string {
+	endpoints := make(map[string]string, len(items))
+	for _, item := range items {
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			endpoints[item] = ""
+			continue
+		}
+		endpoints[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+	}
+	return endpoints
+}
+
+func absolutePath(name, path string) error {
+	if !filepath.IsAbs(path) {
+		return fmt.Errorf("'%s' must be absolute path (%s)", name, path)
+	}
 	return nil
 }
 
@@ -235,6 +541,12 @@ func getEdgeDNSType(config *Config) (EdgeDNSType, []EdgeDNSType) {
 	if isNotEmpty(config.Infoblox.Host) {
 		recognized = append(recognized, DNSTypeInfoblox)
 	}
+	if isNotEmpty(config.PowerDNS.APIURL) {
+		recognized = append(recognized, DNSTypePowerDNS)
+	}
+	if isNotEmpty(config.ProviderPlugin.Socket) {
+		recognized = append(recognized, DNSTypePlugin)
+	}
 	switch len(recognized) {
 	case 0:
 		return DNSTypeNoEdgeDNS, recognized
@@ -254,6 +566,30 @@ func parseLogOutputFormat(value string) LogFormat {
 	return NoFormat
 }
 
+// redacted replaces non-empty secret
+const redacted = "<redacted>"
+
+// Redacted returns copy of the config with credentials replaced, so the config can be exposed
+func (c Config) Redacted() Config {
+	redact := func(secret *string) {
+		if *secret != "" {
+			*secret = redacted
+		}
+	}
+	redact(&c.Infoblox.Username)
+	redact(&c.Infoblox.Password)
+	redact(&c.PowerDNS.APIKey)
+	redact(&c.DNSSecurity.TSIGSecret)
+	redact(&c.DNSSecurity.HeartbeatKey)
+	return c
+}
+
+// GetEdgeDNSTypes returns all recognized edge DNS types. It contains more than one item if EdgeDNSType is DNSTypeMultipleProviders
+func (c *Config) GetEdgeDNSTypes() []EdgeDNSType {
+	_, recognized := getEdgeDNSType(c)
+	return recognized
+}
+
 func (c *Config) GetExternalClusterNSNames() (m map[string]string) {
 	m = make(map[string]string, len(c.ExtClustersGeoTags))
 	for _, tag := range c.ExtClustersGeoTags {
@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package depresolver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// gslbPolicy returns GslbPolicy selecting the namespace, or nil if there is none. When more policies select
+// the namespace, the first one by name applies.
+func gslbPolicy(ctx context.Context, c client.Reader, namespace string) (*k8gbv1beta1.GslbPolicy, error) {
+	policies := &k8gbv1beta1.GslbPolicyList{}
+	err := c.List(ctx, policies)
+	if err != nil {
+		if meta.IsNoMatchError(err) {
+			// GslbPolicy CRD isn't installed
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(policies.Items) == 0 {
+		return nil, nil
+	}
+	ns := &corev1.Namespace{}
+	err = c.Get(ctx, client.ObjectKey{Name: namespace}, ns)
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, err
+	}
+	sort.Slice(policies.Items, func(i, j int) bool {
+		return policies.Items[i].Name < policies.Items[j].Name
+	})
+	for i := range policies.Items {
+		policy := &policies.Items[i]
+		selector := labels.Everything()
+		if policy.Spec.NamespaceSelector != nil {
+			selector, err = metav1.LabelSelectorAsSelector(policy.Spec.NamespaceSelector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid namespaceSelector of GslbPolicy %s: %s", policy.Name, err)
+			}
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			return policy, nil
+		}
+	}
+	return nil, nil
+}
+
+// applyPolicyDefaults returns predefined strategy with defaults of the policy applied
+func applyPolicyDefaults(policy *k8gbv1beta1.GslbPolicy) k8gbv1beta1.Strategy {
+	defaults := predefinedStrategy
+	if policy == nil {
+		return defaults
+	}
+	if policy.Spec.Defaults.DNSTtlSeconds != 0 {
+		defaults.DNSTtlSeconds = policy.Spec.Defaults.DNSTtlSeconds
+	}
+	if policy.Spec.Defaults.SplitBrainThresholdSeconds != 0 {
+		defaults.SplitBrainThresholdSeconds = policy.Spec.Defaults.SplitBrainThresholdSeconds
+	}
+	return defaults
+}
+
+// validatePolicy returns error if the Gslb spec breaks guardrails of the policy
+func validatePolicy(policy *k8gbv1beta1.GslbPolicy, spec k8gbv1beta1.GslbSpec) (err error) {
+	defer func() {
+		if err != nil {
+			err = fmt.Errorf("GslbPolicy %s: %s", policy.Name, err)
+		}
+	}()
+	err = validateStrategy(policy, "strategy", spec.Strategy.Type, spec.Strategy.DNSTtlSeconds)
+	if err != nil {
+		return
+	}
+	if r := policy.Spec.SplitBrainThresholdSeconds; r != nil {
+		err = validateRange("SplitBrainThresholdSeconds", spec.Strategy.SplitBrainThresholdSeconds, r)
+		if err != nil {
+			return
+		}
+	}
+	for _, rule := range spec.Ingress.Rules {
+		if !hostAllowed(policy, rule.Host) {
+			return fmt.Errorf("host '%s' is not allowed", rule.Host)
+		}
+		if rule.Strategy == nil {
+			continue
+		}
+		err = validateStrategy(policy, rule.Host+" strategy", rule.Strategy.Type, rule.Strategy.DNSTtlSeconds)
+		if err != nil {
+			return
+		}
+	}
+	return
+}
+
+// validateStrategy checks strategy type and TTL; empty type and zero TTL of per-host overrides are inherited
+func validateStrategy(policy *k8gbv1beta1.GslbPolicy, name, strategyType string, ttl int) error {
+	if strategyType != "" && len(policy.Spec.AllowedStrategies) > 0 && !contains(policy.Spec.AllowedStrategies, strategyType) {
+		return fmt.Errorf("%s '%s' is not allowed", name, strategyType)
+	}
+	if ttl != 0 && policy.Spec.DNSTtlSeconds != nil {
+		return validateRange(name+" DNSTtlSeconds", ttl, policy.Spec.DNSTtlSeconds)
+	}
+	return nil
+}
+
+func validateRange(name string, value int, r *k8gbv1beta1.IntRange) error {
+	v := field(name, value)
+	if r.Min != 0 {
+		v = v.isHigherOrEqualTo(r.Min)
+	}
+	if r.Max != 0 {
+		v = v.isLessOrEqualTo(r.Max)
+	}
+	return v.err
+}
+
+// hostAllowed returns true if the host matches allowed hosts or zones of the policy, or if the policy
+// doesn't restrict hosts
+func hostAllowed(policy *k8gbv1beta1.GslbPolicy, host string) bool {
+	if len(policy.Spec.AllowedHosts) == 0 && len(policy.Spec.AllowedZones) == 0 {
+		return true
+	}
+	for _, pattern := range policy.Spec.AllowedHosts {
+		if pattern == host {
+			return true
+		}
+		// wildcard matches exactly one label
+		if strings.HasPrefix(pattern, "*.") {
+			if i := strings.Index(host, "."); i > 0 && host[i+1:] == pattern[2:] {
+				return true
+			}
+		}
+	}
+	for _, zone := range policy.Spec.AllowedZones {
+		zone = strings.TrimSuffix(zone, ".")
+		if host == zone || strings.HasSuffix(host, "."+zone) {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -20,49 +20,99 @@ package depresolver
 import (
 	"context"
 	"fmt"
-	"reflect"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
 )
 
+// strategy types which can be set for particular host
+const (
+	roundRobinStrategy = "roundRobin"
+	failoverStrategy   = "failover"
+	geoStrategy        = "geoip"
+)
+
 var predefinedStrategy = k8gbv1beta1.Strategy{
 	DNSTtlSeconds:              30,
 	SplitBrainThresholdSeconds: 300,
 }
 
-// ResolveGslbSpec fills Gslb by spec values. It executes always, when gslb is initialised.
-// If spec value is not defined, it will use the default value. Function returns error if input is invalid.
-func (dr *DependencyResolver) ResolveGslbSpec(ctx context.Context, gslb *k8gbv1beta1.Gslb, client client.Client) error {
+// InvalidSpecError is returned by ResolveGslbSpec when the Gslb spec is invalid or breaks GslbPolicy
+type InvalidSpecError struct {
+	Err error
+}
+
+func (e *InvalidSpecError) Error() string {
+	return e.Err.Error()
+}
+
+// ResolveGslbSpec fills defaults of Gslb spec in memory; resolved spec isn't written back. If spec value is not
+// defined, it will use the default of GslbPolicy selecting namespace of the Gslb, or the predefined value.
+// Function returns InvalidSpecError if input is invalid or breaks the GslbPolicy, the defaults are filled even then.
+// Resolution is stateless, so it's safe for any number of Gslbs.
+func (dr *DependencyResolver) ResolveGslbSpec(ctx context.Context, gslb *k8gbv1beta1.Gslb, client client.Reader) error {
 	if client == nil {
 		return fmt.Errorf("nil client")
 	}
-	if !reflect.DeepEqual(gslb.Spec, dr.spec) {
-		// set predefined values if missing in the yaml
-		if gslb.Spec.Strategy.DNSTtlSeconds == 0 {
-			gslb.Spec.Strategy.DNSTtlSeconds = predefinedStrategy.DNSTtlSeconds
-		}
-		if gslb.Spec.Strategy.SplitBrainThresholdSeconds == 0 {
-			gslb.Spec.Strategy.SplitBrainThresholdSeconds = predefinedStrategy.SplitBrainThresholdSeconds
-		}
-		dr.errorSpec = dr.validateSpec(gslb.Spec.Strategy)
-		if dr.errorSpec == nil {
-			dr.errorSpec = client.Update(ctx, gslb)
-		}
-		dr.spec = gslb.Spec
+	policy, err := gslbPolicy(ctx, client, gslb.Namespace)
+	if err != nil {
+		return err
+	}
+	// set predefined values if missing in the yaml
+	defaults := applyPolicyDefaults(policy)
+	if gslb.Spec.Strategy.DNSTtlSeconds == 0 {
+		gslb.Spec.Strategy.DNSTtlSeconds = defaults.DNSTtlSeconds
+	}
+	if gslb.Spec.Strategy.SplitBrainThresholdSeconds == 0 {
+		gslb.Spec.Strategy.SplitBrainThresholdSeconds = defaults.SplitBrainThresholdSeconds
 	}
-	return dr.errorSpec
+	err = dr.validateSpec(gslb.Spec)
+	if err == nil && policy != nil {
+		err = validatePolicy(policy, gslb.Spec)
+	}
+	if err != nil {
+		return &InvalidSpecError{Err: err}
+	}
+	return nil
 }
 
-func (dr *DependencyResolver) validateSpec(strategy k8gbv1beta1.Strategy) (err error) {
-	err = field("DNSTtlSeconds", strategy.DNSTtlSeconds).isHigherOrEqualToZero().err
+func (dr *DependencyResolver) validateSpec(spec k8gbv1beta1.GslbSpec) (err error) {
+	err = field("DNSTtlSeconds", spec.Strategy.DNSTtlSeconds).isHigherOrEqualToZero().err
 	if err != nil {
 		return
 	}
-	err = field("SplitBrainThresholdSeconds", strategy.SplitBrainThresholdSeconds).isHigherOrEqualToZero().err
+	err = field("SplitBrainThresholdSeconds", spec.Strategy.SplitBrainThresholdSeconds).isHigherOrEqualToZero().err
 	if err != nil {
 		return
 	}
+	for _, rule := range spec.Ingress.Rules {
+		if rule.Strategy == nil {
+			continue
+		}
+		err = field(fmt.Sprintf("%s DNSTtlSeconds", rule.Host), rule.Strategy.DNSTtlSeconds).isHigherOrEqualToZero().err
+		if err != nil {
+			return
+		}
+		err = validateStrategyOverride(rule.Host, spec.Strategy, rule.Strategy)
+		if err != nil {
+			return
+		}
+	}
 	return
 }
+
+// validateStrategyOverride checks type of the host strategy and that failover of the host has primary geo tag,
+// either its own or inherited from Gslb strategy
+func validateStrategyOverride(host string, strategy k8gbv1beta1.Strategy, override *k8gbv1beta1.StrategyOverride) error {
+	switch override.Type {
+	case "", roundRobinStrategy, failoverStrategy, geoStrategy:
+	default:
+		return fmt.Errorf("invalid '%s Type' %s, allowed values %v", host, override.Type,
+			[]string{roundRobinStrategy, failoverStrategy, geoStrategy})
+	}
+	if override.Type == failoverStrategy && override.PrimaryGeoTag == "" && strategy.PrimaryGeoTag == "" {
+		return fmt.Errorf("'%s PrimaryGeoTag' is empty, failover strategy requires primary geo tag", host)
+	}
+	return nil
+}
@@ -32,6 +32,8 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -53,13 +55,48 @@ var predefinedConfig = Config{
 	SplitBrainCheck:         true,
 	MetricsAddress:          "0.0.0.0:8080",
 	Infoblox: Infoblox{
-		"Infoblox.host.com",
-		"0.0.3",
-		443,
-		"Infoblox",
-		"secret",
-		21,
-		11,
+		Host:                "Infoblox.host.com",
+		Version:             "0.0.3",
+		Port:                443,
+		Username:            "Infoblox",
+		Password:            "secret",
+		HTTPRequestTimeout:  21,
+		HTTPPoolConnections: 11,
+		SSLVerify:           true,
+	},
+	PowerDNS: PowerDNS{
+		ServerID:           "localhost",
+		HTTPRequestTimeout: 20,
+	},
+	ProviderPlugin: ProviderPlugin{
+		RequestTimeout: 20,
+	},
+	ZoneDelegation: ZoneDelegation{
+		TTL:     30,
+		GlueIPs: []string{},
+	},
+	Drain: Drain{
+		TTL:           5,
+		PeriodSeconds: 60,
+	},
+	Tracing: Tracing{
+		Endpoint: "localhost:4317",
+	},
+	Audit: Audit{
+		HistorySize: 10,
+	},
+	PeerStatus: PeerStatus{
+		Port:      8443,
+		CertDir:   "/etc/k8gb/peer-status",
+		Endpoints: map[string]string{},
+	},
+	DNSSecurity: DNSSecurity{
+		TSIGAlgorithm: "hmac-sha256",
+	},
+	Heartbeat: Heartbeat{
+		Backend:         HeartbeatBackendDNS,
+		LeaseKubeconfig: "/etc/k8gb/heartbeat-lease/kubeconfig",
+		LeaseNamespace:  "k8gb",
 	},
 	Override: Override{
 		false,
@@ -125,6 +162,165 @@ func TestResolveSpecWithNegativeFields(t *testing.T) {
 	err := resolver.ResolveGslbSpec(context.TODO(), gslb, cl)
 	// assert
 	assert.Error(t, err)
+	assert.IsType(t, &InvalidSpecError{}, err)
+}
+
+func TestResolveSpecWithNegativeHostStrategyFields(t *testing.T) {
+	// arrange
+	cl, gslb := getTestContext("./testdata/invalid_host_strategy_negative.yaml")
+	resolver := NewDependencyResolver()
+	// act
+	err := resolver.ResolveGslbSpec(context.TODO(), gslb, cl)
+	// assert
+	assert.EqualError(t, err, "'failover.cloud.example.com DNSTtlSeconds' is less than zero")
+}
+
+func TestResolveSpecWithInvalidHostStrategyType(t *testing.T) {
+	// arrange
+	cl, gslb := getTestContext("./testdata/invalid_host_strategy_type.yaml")
+	resolver := NewDependencyResolver()
+	// act
+	err := resolver.ResolveGslbSpec(context.TODO(), gslb, cl)
+	// assert
+	assert.EqualError(t, err, "invalid 'failover.cloud.example.com Type' roundrobin, allowed values [roundRobin failover geoip]")
+	assert.IsType(t, &InvalidSpecError{}, err)
+}
+
+func TestResolveSpecWithHostFailoverWithoutPrimaryGeoTag(t *testing.T) {
+	// arrange
+	cl, gslb := getTestContext("./testdata/invalid_host_strategy_without_primary.yaml")
+	resolver := NewDependencyResolver()
+	// act
+	err := resolver.ResolveGslbSpec(context.TODO(), gslb, cl)
+	// assert
+	assert.EqualError(t, err, "'failover.cloud.example.com PrimaryGeoTag' is empty, failover strategy requires primary geo tag")
+}
+
+func TestResolveSpecWithHostFailoverInheritingPrimaryGeoTag(t *testing.T) {
+	// arrange
+	cl, gslb := getTestContext("./testdata/invalid_host_strategy_without_primary.yaml")
+	gslb.Spec.Strategy.PrimaryGeoTag = "eu"
+	resolver := NewDependencyResolver()
+	// act
+	err := resolver.ResolveGslbSpec(context.TODO(), gslb, cl)
+	// assert
+	assert.NoError(t, err)
+}
+
+func TestResolveSpecWithPolicyDefaults(t *testing.T) {
+	// arrange
+	cl, gslb := getTestContext("./testdata/free_omitempty.yaml")
+	addPolicy(t, cl, map[string]string{"team": "a"}, k8gbv1beta1.GslbPolicySpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+		Defaults:          k8gbv1beta1.PolicyDefaults{DNSTtlSeconds: 60, SplitBrainThresholdSeconds: 600},
+	})
+	resolver := NewDependencyResolver()
+	// act
+	err := resolver.ResolveGslbSpec(context.TODO(), gslb, cl)
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 60, gslb.Spec.Strategy.DNSTtlSeconds)
+	assert.Equal(t, 600, gslb.Spec.Strategy.SplitBrainThresholdSeconds)
+}
+
+func TestResolveSpecIgnoresPolicyNotSelectingNamespace(t *testing.T) {
+	// arrange
+	cl, gslb := getTestContext("./testdata/free_omitempty.yaml")
+	addPolicy(t, cl, map[string]string{"team": "b"}, k8gbv1beta1.GslbPolicySpec{
+		NamespaceSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+		Defaults:          k8gbv1beta1.PolicyDefaults{DNSTtlSeconds: 60},
+		AllowedStrategies: []string{"failover"},
+	})
+	resolver := NewDependencyResolver()
+	// act
+	err := resolver.ResolveGslbSpec(context.TODO(), gslb, cl)
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, predefinedStrategy.DNSTtlSeconds, gslb.Spec.Strategy.DNSTtlSeconds)
+}
+
+func TestResolveSpecRejectsGslbBreakingPolicy(t *testing.T) {
+	var tests = []struct {
+		name   string
+		policy k8gbv1beta1.GslbPolicySpec
+		err    string
+	}{
+		{name: "strategy", policy: k8gbv1beta1.GslbPolicySpec{AllowedStrategies: []string{"failover"}},
+			err: "GslbPolicy guardrails: strategy 'roundRobin' is not allowed"},
+		{name: "ttl", policy: k8gbv1beta1.GslbPolicySpec{DNSTtlSeconds: &k8gbv1beta1.IntRange{Min: 10, Max: 30}},
+			err: "GslbPolicy guardrails: 'strategy DNSTtlSeconds' is higher than '30'"},
+		{name: "split brain", policy: k8gbv1beta1.GslbPolicySpec{SplitBrainThresholdSeconds: &k8gbv1beta1.IntRange{Min: 600}},
+			err: "GslbPolicy guardrails: 'SplitBrainThresholdSeconds' is less than '600'"},
+		{name: "zone", policy: k8gbv1beta1.GslbPolicySpec{AllowedZones: []string{"other.example.com"}},
+			err: "GslbPolicy guardrails: host 'notfound.cloud.example.com' is not allowed"},
+		{name: "host", policy: k8gbv1beta1.GslbPolicySpec{AllowedHosts: []string{"*.example.com"}},
+			err: "GslbPolicy guardrails: host 'notfound.cloud.example.com' is not allowed"},
+		{name: "allowed", policy: k8gbv1beta1.GslbPolicySpec{
+			AllowedStrategies: []string{"roundRobin"},
+			DNSTtlSeconds:     &k8gbv1beta1.IntRange{Min: 10, Max: 60},
+			AllowedHosts:      []string{"*.cloud.example.com"},
+		}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// arrange
+			cl, gslb := getTestContext("./testdata/filled_omitempty.yaml")
+			addPolicy(t, cl, nil, test.policy)
+			resolver := NewDependencyResolver()
+			// act
+			err := resolver.ResolveGslbSpec(context.TODO(), gslb, cl)
+			// assert
+			if test.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, test.err)
+		})
+	}
+}
+
+func TestResolveSpecAppliesFirstMatchingPolicy(t *testing.T) {
+	// arrange
+	cl, gslb := getTestContext("./testdata/free_omitempty.yaml")
+	addPolicy(t, cl, nil, k8gbv1beta1.GslbPolicySpec{Defaults: k8gbv1beta1.PolicyDefaults{DNSTtlSeconds: 60}})
+	policy := &k8gbv1beta1.GslbPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "a-first"},
+		Spec:       k8gbv1beta1.GslbPolicySpec{Defaults: k8gbv1beta1.PolicyDefaults{DNSTtlSeconds: 90}},
+	}
+	assert.NoError(t, cl.Create(context.TODO(), policy))
+	resolver := NewDependencyResolver()
+	// act
+	err := resolver.ResolveGslbSpec(context.TODO(), gslb, cl)
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 90, gslb.Spec.Strategy.DNSTtlSeconds)
+}
+
+func TestResolveSpecDoesNotPersistDefaults(t *testing.T) {
+	// arrange
+	cl, gslb := getTestContext("./testdata/free_omitempty.yaml")
+	stored := &k8gbv1beta1.Gslb{}
+	resolver := NewDependencyResolver()
+	// act
+	err := resolver.ResolveGslbSpec(context.TODO(), gslb, cl)
+	assert.NoError(t, cl.Get(context.TODO(), client.ObjectKey{Namespace: gslb.Namespace, Name: gslb.Name}, stored))
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, predefinedStrategy.DNSTtlSeconds, gslb.Spec.Strategy.DNSTtlSeconds)
+	assert.Equal(t, 0, stored.Spec.Strategy.DNSTtlSeconds)
+}
+
+func TestResolveSpecErrorDoesNotLeakToOtherGslb(t *testing.T) {
+	// arrange
+	invalidClient, invalid := getTestContext("./testdata/invalid_omitempty_negative.yaml")
+	validClient, valid := getTestContext("./testdata/filled_omitempty.yaml")
+	resolver := NewDependencyResolver()
+	// act
+	err1 := resolver.ResolveGslbSpec(context.TODO(), invalid, invalidClient)
+	err2 := resolver.ResolveGslbSpec(context.TODO(), valid, validClient)
+	// assert
+	assert.IsType(t, &InvalidSpecError{}, err1)
+	assert.NoError(t, err2)
 }
 
 func TestSpecRunWhenChanged(t *testing.T) {
@@ -171,6 +367,23 @@ func TestResolveConfigWithoutEnvVarsSet(t *testing.T) {
 	defaultConfig.ReconcileRequeueSeconds = 30
 	defaultConfig.Infoblox.HTTPRequestTimeout = 20
 	defaultConfig.Infoblox.HTTPPoolConnections = 10
+	defaultConfig.Infoblox.SSLVerify = true
+	defaultConfig.PowerDNS.ServerID = "localhost"
+	defaultConfig.PowerDNS.HTTPRequestTimeout = 20
+	defaultConfig.ProviderPlugin.RequestTimeout = 20
+	defaultConfig.ZoneDelegation.TTL = 30
+	defaultConfig.ZoneDelegation.GlueIPs = []string{}
+	defaultConfig.Drain.TTL = 5
+	defaultConfig.Drain.PeriodSeconds = 60
+	defaultConfig.Tracing.Endpoint = "localhost:4317"
+	defaultConfig.Audit.HistorySize = 10
+	defaultConfig.PeerStatus.Port = 8443
+	defaultConfig.PeerStatus.CertDir = "/etc/k8gb/peer-status"
+	defaultConfig.PeerStatus.Endpoints = map[string]string{}
+	defaultConfig.DNSSecurity.TSIGAlgorithm = "hmac-sha256"
+	defaultConfig.Heartbeat.Backend = HeartbeatBackendDNS
+	defaultConfig.Heartbeat.LeaseKubeconfig = "/etc/k8gb/heartbeat-lease/kubeconfig"
+	defaultConfig.Heartbeat.LeaseNamespace = "k8gb"
 	defaultConfig.EdgeDNSServerPort = 53
 	defaultConfig.EdgeDNSType = DNSTypeNoEdgeDNS
 	defaultConfig.ExtClustersGeoTags = []string{}
@@ -693,8 +906,9 @@ func TestBothRoute53AndInfobloxAreEnabled(t *testing.T) {
 	// act
 	config, err := resolver.ResolveOperatorConfig()
 	// assert
-	assert.Error(t, err)
+	assert.NoError(t, err)
 	assert.Equal(t, DNSTypeMultipleProviders, config.EdgeDNSType)
+	assert.Equal(t, []EdgeDNSType{DNSTypeRoute53, DNSTypeInfoblox}, config.GetEdgeDNSTypes())
 }
 
 func TestRoute53NS1AndInfobloxAreConfigured(t *testing.T) {
@@ -709,7 +923,7 @@ func TestRoute53NS1AndInfobloxAreConfigured(t *testing.T) {
 	config, err := resolver.ResolveOperatorConfig()
 	recognizedEdgeDNSType, recognizedEdgeDNSTypes := getEdgeDNSType(config)
 	// assert
-	assert.Error(t, err)
+	assert.NoError(t, err)
 	assert.Equal(t, DNSTypeMultipleProviders, config.EdgeDNSType)
 	assert.Equal(t, recognizedEdgeDNSType, config.EdgeDNSType)
 	assert.Equal(t, recognizedEdgeDNSTypes, []EdgeDNSType{DNSTypeNS1, DNSTypeRoute53, DNSTypeInfoblox})
@@ -1108,6 +1322,466 @@ func TestNegativeInfobloxHTTPRequestTimeout(t *testing.T) {
 
 }
 
+func TestInfobloxCredentialsFromFiles(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.Infoblox.Username = ""
+	expected.Infoblox.Password = ""
+	expected.Infoblox.UsernameFile = "/var/run/secrets/infoblox/EXTERNAL_DNS_INFOBLOX_WAPI_USERNAME"
+	expected.Infoblox.PasswordFile = "/var/run/secrets/infoblox/EXTERNAL_DNS_INFOBLOX_WAPI_PASSWORD"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestInfobloxCredentialsFileIsRelative(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.Infoblox.Password = ""
+	expected.Infoblox.PasswordFile = "secrets/password"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.Error)
+}
+
+func TestInfobloxSSLVerifyIsUnset(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.Infoblox.SSLVerify = true
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError, InfobloxSSLVerifyKey)
+}
+
+func TestInfobloxCABundle(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.Infoblox.CABundle = "/etc/k8gb/infoblox/ca.crt"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestInfobloxCABundleWithDisabledSSLVerify(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.Infoblox.SSLVerify = false
+	expected.Infoblox.CABundle = "/etc/k8gb/infoblox/ca.crt"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.Error)
+}
+
+func TestPowerDNSIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, url := range []string{"http://pdns.example.com:8081", "https://pdns.example.com", "http://10.0.0.1:8081/"} {
+		expected := predefinedConfig
+		expected.EdgeDNSType = DNSTypePowerDNS
+		expected.Infoblox.Host = ""
+		expected.PowerDNS.APIURL = url
+		expected.PowerDNS.APIKey = "secret"
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.NoError)
+	}
+}
+
+func TestPowerDNSInvalidAPIURL(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, url := range []string{"pdns.example.com", "ftp://pdns.example.com", "http://pdns example.com"} {
+		expected := predefinedConfig
+		expected.EdgeDNSType = DNSTypePowerDNS
+		expected.Infoblox.Host = ""
+		expected.PowerDNS.APIURL = url
+		expected.PowerDNS.APIKey = "secret"
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestPowerDNSEmptyAPIKey(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.EdgeDNSType = DNSTypePowerDNS
+	expected.Infoblox.Host = ""
+	expected.PowerDNS.APIURL = "http://pdns.example.com:8081"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.Error, PowerDNSAPIKeyKey)
+}
+
+func TestPowerDNSUnsetServerIDAndTimeout(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.EdgeDNSType = DNSTypePowerDNS
+	expected.Infoblox.Host = ""
+	expected.PowerDNS.APIURL = "http://pdns.example.com:8081"
+	expected.PowerDNS.APIKey = "secret"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError, PowerDNSServerIDKey, PowerDNSHTTPRequestTimeoutKey)
+}
+
+func TestPowerDNSInvalidHTTPRequestTimeout(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, timeout := range []int{-1, 0} {
+		expected := predefinedConfig
+		expected.EdgeDNSType = DNSTypePowerDNS
+		expected.Infoblox.Host = ""
+		expected.PowerDNS.APIURL = "http://pdns.example.com:8081"
+		expected.PowerDNS.APIKey = "secret"
+		expected.PowerDNS.HTTPRequestTimeout = timeout
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestProviderPluginIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.EdgeDNSType = DNSTypePlugin
+	expected.Infoblox.Host = ""
+	expected.ProviderPlugin.Socket = "/var/run/k8gb/provider.sock"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError, ProviderPluginRequestTimeoutKey)
+}
+
+func TestProviderPluginSocketIsRelative(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.EdgeDNSType = DNSTypePlugin
+	expected.Infoblox.Host = ""
+	expected.ProviderPlugin.Socket = "provider.sock"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.Error)
+}
+
+func TestProviderPluginInvalidRequestTimeout(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, timeout := range []int{-1, 0} {
+		expected := predefinedConfig
+		expected.EdgeDNSType = DNSTypePlugin
+		expected.Infoblox.Host = ""
+		expected.ProviderPlugin.Socket = "/var/run/k8gb/provider.sock"
+		expected.ProviderPlugin.RequestTimeout = timeout
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestZoneDelegationIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.ZoneDelegation.TTL = 300
+	expected.ZoneDelegation.GlueIPs = []string{"10.0.0.1", "10.0.0.2"}
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestZoneDelegationInvalidTTL(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, ttl := range []int{-1, 0} {
+		expected := predefinedConfig
+		expected.ZoneDelegation.TTL = ttl
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestDrainIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.Drain = Drain{Enabled: true, TTL: 10, PeriodSeconds: 0}
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestDrainInvalidValues(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, drain := range []Drain{{TTL: 0, PeriodSeconds: 60}, {TTL: -1, PeriodSeconds: 60}, {TTL: 5, PeriodSeconds: -1}} {
+		expected := predefinedConfig
+		expected.Drain = drain
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestTracingIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.Tracing = Tracing{Enabled: true, Endpoint: "otel-collector.monitoring:4317", Insecure: true}
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestTracingInvalidEndpoint(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, endpoint := range []string{"otel-collector", "otel-collector:otlp", "http://otel-collector:4317"} {
+		expected := predefinedConfig
+		expected.Tracing = Tracing{Enabled: true, Endpoint: endpoint}
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestAuditIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.Audit = Audit{HistorySize: 0, LogEnabled: true}
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestDebugAPIIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.DebugAPI = true
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestK8gbVersionIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.K8gbVersion = "v0.8.0"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestPeerStatusIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.PeerStatus = PeerStatus{Enabled: true, Port: 9443, CertDir: "/etc/k8gb/peer-status",
+		Endpoints: map[string]string{"za": "k8gb-status.za.example.com:9443", "eu": "10.0.0.2:8443"}}
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestPeerStatusInvalidValues(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, peerStatus := range []PeerStatus{
+		{Enabled: true, Port: 0, CertDir: "/etc/k8gb/peer-status", Endpoints: map[string]string{}},
+		{Enabled: true, Port: 8443, CertDir: "peer-status", Endpoints: map[string]string{}},
+		{Enabled: true, Port: 8443, CertDir: "/etc/k8gb/peer-status", Endpoints: map[string]string{"us": "10.0.0.1:8443"}},
+		{Enabled: true, Port: 8443, CertDir: "/etc/k8gb/peer-status", Endpoints: map[string]string{"eu": "10.0.0.2"}},
+		{Enabled: true, Port: 8443, CertDir: "/etc/k8gb/peer-status", Endpoints: map[string]string{"eu": "https://10.0.0.2:8443"}},
+	} {
+		expected := predefinedConfig
+		expected.PeerStatus = peerStatus
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestPeerStatusMalformedEndpoint(t *testing.T) {
+	// arrange
+	defer cleanup()
+	configureEnvVar(predefinedConfig)
+	_ = os.Setenv(PeerStatusEnabledKey, "true")
+	_ = os.Setenv(PeerStatusEndpointsKey, "eu:10.0.0.2:8443")
+	resolver := NewDependencyResolver()
+	// act
+	_, err := resolver.ResolveOperatorConfig()
+	// assert
+	assert.Error(t, err)
+}
+
+func TestDNSSecurityIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.DNSSecurity = DNSSecurity{TSIGKeyName: "k8gb-peer.", TSIGAlgorithm: "hmac-sha512", TSIGSecret: "c2VjcmV0",
+		HeartbeatKey: "heartbeat-secret"}
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestDNSSecurityInvalidValues(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, security := range []DNSSecurity{
+		{TSIGKeyName: "k8gb peer", TSIGAlgorithm: "hmac-sha256", TSIGSecret: "c2VjcmV0"},
+		{TSIGKeyName: "k8gb-peer", TSIGAlgorithm: "hmac-md5", TSIGSecret: "c2VjcmV0"},
+		{TSIGKeyName: "k8gb-peer", TSIGAlgorithm: "hmac-sha256", TSIGSecret: ""},
+		{TSIGKeyName: "k8gb-peer", TSIGAlgorithm: "hmac-sha256", TSIGSecret: "not base64!"},
+	} {
+		expected := predefinedConfig
+		expected.DNSSecurity = security
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestHeartbeatKeyIsNotSupportedWithPlugin(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.EdgeDNSType = DNSTypeMultipleProviders
+	expected.ProviderPlugin.Socket = "/var/run/k8gb/provider.sock"
+	expected.DNSSecurity.HeartbeatKey = "heartbeat-secret"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.Error)
+}
+
+func TestSplitBrainQuorumIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.SplitBrainQuorum = true
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestSplitBrainQuorumIsConfiguredWithPowerDNS(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.SplitBrainQuorum = true
+	expected.EdgeDNSType = DNSTypeMultipleProviders
+	expected.PowerDNS.APIURL = "http://pdns.example.com:8081"
+	expected.PowerDNS.APIKey = "secret"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestSplitBrainQuorumInvalidValues(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, modify := range []func(*Config){
+		func(c *Config) { c.SplitBrainCheck = false },
+		func(c *Config) { c.ExtClustersGeoTags = []string{"eu"} },
+		func(c *Config) {
+			c.EdgeDNSType = DNSTypeMultipleProviders
+			c.ProviderPlugin.Socket = "/var/run/k8gb/provider.sock"
+		},
+		func(c *Config) {
+			c.EdgeDNSType = DNSTypeRoute53
+			c.route53Enabled = true
+			c.Infoblox.Host = ""
+		},
+		func(c *Config) {
+			c.EdgeDNSType = DNSTypeNS1
+			c.ns1Enabled = true
+			c.Infoblox.Host = ""
+		},
+		func(c *Config) {
+			c.EdgeDNSType = DNSTypeMultipleProviders
+			c.route53Enabled = true
+		},
+		func(c *Config) {
+			c.EdgeDNSType = DNSTypeNoEdgeDNS
+			c.Infoblox.Host = ""
+		},
+	} {
+		expected := predefinedConfig
+		expected.SplitBrainQuorum = true
+		modify(&expected)
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestLeaseHeartbeatIsConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.Heartbeat = Heartbeat{Backend: HeartbeatBackendLease, LeaseKubeconfig: "/etc/hub/kubeconfig", LeaseNamespace: "k8gb-heartbeats"}
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestHeartbeatInvalidValues(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, heartbeat := range []Heartbeat{
+		{Backend: "etcd", LeaseKubeconfig: "/etc/hub/kubeconfig", LeaseNamespace: "k8gb"},
+		{Backend: HeartbeatBackendLease, LeaseKubeconfig: "kubeconfig", LeaseNamespace: "k8gb"},
+		{Backend: HeartbeatBackendLease, LeaseKubeconfig: "/etc/hub/kubeconfig", LeaseNamespace: "K8GB_heartbeats"},
+	} {
+		expected := predefinedConfig
+		expected.Heartbeat = heartbeat
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestLeaseHeartbeatIsNotSupportedWithPlugin(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.EdgeDNSType = DNSTypeMultipleProviders
+	expected.ProviderPlugin.Socket = "/var/run/k8gb/provider.sock"
+	expected.Heartbeat.Backend = HeartbeatBackendLease
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.Error)
+}
+
+func TestRedactedConfigHidesCredentials(t *testing.T) {
+	// arrange
+	config := predefinedConfig
+	config.Infoblox.Password = "secret"
+	config.PowerDNS.APIKey = ""
+	config.DNSSecurity.TSIGSecret = "c2VjcmV0"
+	config.DNSSecurity.HeartbeatKey = "heartbeat-secret"
+	// act
+	redactedConfig := config.Redacted()
+	// assert
+	assert.Equal(t, redacted, redactedConfig.Infoblox.Username)
+	assert.Equal(t, redacted, redactedConfig.Infoblox.Password)
+	assert.Empty(t, redactedConfig.PowerDNS.APIKey, "empty secret is left empty")
+	assert.Equal(t, redacted, redactedConfig.DNSSecurity.TSIGSecret)
+	assert.Equal(t, redacted, redactedConfig.DNSSecurity.HeartbeatKey)
+	assert.Equal(t, "secret", config.Infoblox.Password, "original config isn't changed")
+	assert.Equal(t, config.Infoblox.Host, redactedConfig.Infoblox.Host)
+}
+
+func TestAuditNegativeHistorySize(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.Audit.HistorySize = -1
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.Error)
+}
+
+func TestZoneDelegationInvalidGlueIPs(t *testing.T) {
+	// arrange
+	defer cleanup()
+	for _, ips := range [][]string{{"10.0.0.1", "ns.example.com"}, {"10.0.0.300"}} {
+		expected := predefinedConfig
+		expected.ZoneDelegation.GlueIPs = ips
+		// act,assert
+		arrangeVariablesAndAssert(t, expected, assert.Error)
+	}
+}
+
+func TestPowerDNSAndInfobloxAreConfigured(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.EdgeDNSType = DNSTypeMultipleProviders
+	expected.PowerDNS.APIURL = "http://pdns.example.com:8081"
+	expected.PowerDNS.APIKey = "secret"
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
 func TestResolveConfigEnableFakeDNSAsTrue(t *testing.T) {
 	// arrange
 	defer cleanup()
@@ -1332,6 +2006,24 @@ func TestResolveConfigSplitBrainCheckNotSet(t *testing.T) {
 	arrangeVariablesAndAssert(t, expected, assert.NoError, SplitBrainCheckKey)
 }
 
+func TestResolveConfigDryRunEnabled(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.DryRun = true
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError)
+}
+
+func TestResolveConfigDryRunNotSet(t *testing.T) {
+	// arrange
+	defer cleanup()
+	expected := predefinedConfig
+	expected.DryRun = false
+	// act,assert
+	arrangeVariablesAndAssert(t, expected, assert.NoError, DryRunKey)
+}
+
 func TestResolveConfigSplitBrainCheckDisabledInvalid(t *testing.T) {
 	// arrange
 	defer cleanup()
@@ -1675,7 +2367,14 @@ func cleanup() {
 	for _, s := range []string{ReconcileRequeueSecondsKey, ClusterGeoTagKey, ExtClustersGeoTagsKey, EdgeDNSZoneKey, DNSZoneKey, EdgeDNSServerKey,
 		EdgeDNSServerPortKey, Route53EnabledKey, NS1EnabledKey, InfobloxGridHostKey, InfobloxVersionKey, InfobloxPortKey, InfobloxUsernameKey,
 		InfobloxPasswordKey, OverrideFakeInfobloxKey, K8gbNamespaceKey, CoreDNSExposedKey, InfobloxHTTPRequestTimeoutKey,
-		InfobloxHTTPPoolConnectionsKey, LogLevelKey, LogFormatKey, LogNoColorKey, MetricsAddressKey, SplitBrainCheckKey} {
+		InfobloxHTTPPoolConnectionsKey, LogLevelKey, LogFormatKey, LogNoColorKey, MetricsAddressKey, SplitBrainCheckKey,
+		PowerDNSAPIURLKey, PowerDNSAPIKeyKey, PowerDNSServerIDKey, PowerDNSHTTPRequestTimeoutKey,
+		ProviderPluginSocketKey, ProviderPluginRequestTimeoutKey, InfobloxSSLVerifyKey, InfobloxCABundleKey,
+		InfobloxUsernameFileKey, InfobloxPasswordFileKey, ZoneDelegationTTLKey, ZoneDelegationGlueIPsKey, DryRunKey,
+		DrainKey, DrainTTLKey, DrainPeriodKey, TracingEnabledKey, TracingEndpointKey, TracingInsecureKey,
+		AuditHistorySizeKey, AuditLogEnabledKey, DebugAPIKey, PeerStatusEnabledKey, PeerStatusPortKey, PeerStatusCertDirKey,
+		PeerStatusEndpointsKey, TSIGKeyNameKey, TSIGAlgorithmKey, TSIGSecretKey, HeartbeatKeyKey, K8gbVersionKey,
+		SplitBrainQuorumKey, HeartbeatBackendKey, HeartbeatLeaseKubeconfigKey, HeartbeatLeaseNamespaceKey} {
 		if os.Unsetenv(s) != nil {
 			panic(fmt.Errorf("cleanup %s", s))
 		}
@@ -1691,6 +2390,7 @@ func configureEnvVar(config Config) {
 	_ = os.Setenv(EdgeDNSZoneKey, config.EdgeDNSZone)
 	_ = os.Setenv(DNSZoneKey, config.DNSZone)
 	_ = os.Setenv(K8gbNamespaceKey, config.K8gbNamespace)
+	_ = os.Setenv(K8gbVersionKey, config.K8gbVersion)
 	_ = os.Setenv(Route53EnabledKey, strconv.FormatBool(config.route53Enabled))
 	_ = os.Setenv(NS1EnabledKey, strconv.FormatBool(config.ns1Enabled))
 	_ = os.Setenv(CoreDNSExposedKey, strconv.FormatBool(config.CoreDNSExposed))
@@ -1701,12 +2401,59 @@ func configureEnvVar(config Config) {
 	_ = os.Setenv(InfobloxPasswordKey, config.Infoblox.Password)
 	_ = os.Setenv(InfobloxHTTPRequestTimeoutKey, strconv.Itoa(config.Infoblox.HTTPRequestTimeout))
 	_ = os.Setenv(InfobloxHTTPPoolConnectionsKey, strconv.Itoa(config.Infoblox.HTTPPoolConnections))
+	_ = os.Setenv(InfobloxSSLVerifyKey, strconv.FormatBool(config.Infoblox.SSLVerify))
+	_ = os.Setenv(InfobloxCABundleKey, config.Infoblox.CABundle)
+	_ = os.Setenv(InfobloxUsernameFileKey, config.Infoblox.UsernameFile)
+	_ = os.Setenv(InfobloxPasswordFileKey, config.Infoblox.PasswordFile)
+	_ = os.Setenv(PowerDNSAPIURLKey, config.PowerDNS.APIURL)
+	_ = os.Setenv(PowerDNSAPIKeyKey, config.PowerDNS.APIKey)
+	_ = os.Setenv(PowerDNSServerIDKey, config.PowerDNS.ServerID)
+	_ = os.Setenv(PowerDNSHTTPRequestTimeoutKey, strconv.Itoa(config.PowerDNS.HTTPRequestTimeout))
+	_ = os.Setenv(ProviderPluginSocketKey, config.ProviderPlugin.Socket)
+	_ = os.Setenv(ProviderPluginRequestTimeoutKey, strconv.Itoa(config.ProviderPlugin.RequestTimeout))
+	_ = os.Setenv(ZoneDelegationTTLKey, strconv.Itoa(config.ZoneDelegation.TTL))
+	_ = os.Setenv(ZoneDelegationGlueIPsKey, strings.Join(config.ZoneDelegation.GlueIPs, ","))
 	_ = os.Setenv(OverrideFakeInfobloxKey, strconv.FormatBool(config.Override.FakeInfobloxEnabled))
 	_ = os.Setenv(LogLevelKey, config.Log.Level.String())
 	_ = os.Setenv(LogFormatKey, config.Log.Format.String())
 	_ = os.Setenv(LogNoColorKey, strconv.FormatBool(config.Log.NoColor))
 	_ = os.Setenv(MetricsAddressKey, config.MetricsAddress)
 	_ = os.Setenv(SplitBrainCheckKey, strconv.FormatBool(config.SplitBrainCheck))
+	_ = os.Setenv(SplitBrainQuorumKey, strconv.FormatBool(config.SplitBrainQuorum))
+	_ = os.Setenv(HeartbeatBackendKey, config.Heartbeat.Backend)
+	_ = os.Setenv(HeartbeatLeaseKubeconfigKey, config.Heartbeat.LeaseKubeconfig)
+	_ = os.Setenv(HeartbeatLeaseNamespaceKey, config.Heartbeat.LeaseNamespace)
+	_ = os.Setenv(DryRunKey, strconv.FormatBool(config.DryRun))
+	_ = os.Setenv(DrainKey, strconv.FormatBool(config.Drain.Enabled))
+	_ = os.Setenv(DrainTTLKey, strconv.Itoa(config.Drain.TTL))
+	_ = os.Setenv(DrainPeriodKey, strconv.Itoa(config.Drain.PeriodSeconds))
+	_ = os.Setenv(TracingEnabledKey, strconv.FormatBool(config.Tracing.Enabled))
+	_ = os.Setenv(TracingEndpointKey, config.Tracing.Endpoint)
+	_ = os.Setenv(TracingInsecureKey, strconv.FormatBool(config.Tracing.Insecure))
+	_ = os.Setenv(AuditHistorySizeKey, strconv.Itoa(config.Audit.HistorySize))
+	_ = os.Setenv(AuditLogEnabledKey, strconv.FormatBool(config.Audit.LogEnabled))
+	_ = os.Setenv(DebugAPIKey, strconv.FormatBool(config.DebugAPI))
+	_ = os.Setenv(PeerStatusEnabledKey, strconv.FormatBool(config.PeerStatus.Enabled))
+	_ = os.Setenv(PeerStatusPortKey, strconv.Itoa(config.PeerStatus.Port))
+	_ = os.Setenv(PeerStatusCertDirKey, config.PeerStatus.CertDir)
+	var endpoints []string
+	for geoTag, endpoint := range config.PeerStatus.Endpoints {
+		endpoints = append(endpoints, geoTag+"="+endpoint)
+	}
+	_ = os.Setenv(PeerStatusEndpointsKey, strings.Join(endpoints, ","))
+	_ = os.Setenv(TSIGKeyNameKey, config.DNSSecurity.TSIGKeyName)
+	_ = os.Setenv(TSIGAlgorithmKey, config.DNSSecurity.TSIGAlgorithm)
+	_ = os.Setenv(TSIGSecretKey, config.DNSSecurity.TSIGSecret)
+	_ = os.Setenv(HeartbeatKeyKey, config.DNSSecurity.HeartbeatKey)
+}
+
+// addPolicy creates GslbPolicy named guardrails and namespace of test Gslb with labels
+func addPolicy(t *testing.T, cl client.Client, namespaceLabels map[string]string, spec k8gbv1beta1.GslbPolicySpec) {
+	t.Helper()
+	ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "test-gslb", Labels: namespaceLabels}}
+	policy := &k8gbv1beta1.GslbPolicy{ObjectMeta: metav1.ObjectMeta{Name: "guardrails"}, Spec: spec}
+	assert.NoError(t, cl.Create(context.TODO(), ns))
+	assert.NoError(t, cl.Create(context.TODO(), policy))
 }
 
 func getTestContext(testData string) (client.Client, *k8gbv1beta1.Gslb) {
@@ -1724,7 +2471,7 @@ func getTestContext(testData string) (client.Client, *k8gbv1beta1.Gslb) {
 	}
 	// Register operator types with the runtime scheme.
 	s := scheme.Scheme
-	s.AddKnownTypes(k8gbv1beta1.GroupVersion, gslb)
+	s.AddKnownTypes(k8gbv1beta1.GroupVersion, gslb, &k8gbv1beta1.GslbPolicy{}, &k8gbv1beta1.GslbPolicyList{})
 	// Register external-dns DNSEndpoint CRD
 	s.AddKnownTypes(schema.GroupVersion{Group: "externaldns.k8s.io", Version: "v1alpha1"}, &externaldns.DNSEndpoint{})
 	cl := fake.NewFakeClientWithScheme(s, objs...)
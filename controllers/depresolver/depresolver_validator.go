@@ -32,6 +32,8 @@ const (
 	ipAddressRegex = "^(([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])\\.){3}([0-9]|[1-9][0-9]|1[0-9]{2}|2[0-4][0-9]|25[0-5])$"
 	// versionNumberRegex matches version in formats 0.1.2, v0.1.2, v0.1.2-alpha
 	versionNumberRegex = "^(v){0,1}(0|(?:[1-9]\\d*))(?:\\.(0|(?:[1-9]\\d*))(?:\\.(0|(?:[1-9]\\d*)))?(?:\\-([\\w][\\w\\.\\-_]*))?)?$"
+	// httpURLRegex matches http(s) URLs with optional port and path; e.g. http://pdns.example.com:8081
+	httpURLRegex = "^https?://[a-zA-Z0-9\\-\\.]+(:[0-9]{1,5})?(/.*)?$"
 	// k8sNamespaceRegex matches valid kubernetes namespace
 	k8sNamespaceRegex = "^[a-z0-9]([-a-z0-9]*[a-z0-9])?$"
 )
@@ -139,6 +141,16 @@ func (v *validator) isLessOrEqualTo(num int) *validator {
 	return v
 }
 
+func (v *validator) isHigherOrEqualTo(num int) *validator {
+	if v.err != nil {
+		return v
+	}
+	if v.intValue < num {
+		v.err = fmt.Errorf(`'%s' is less than '%v'`, v.name, num)
+	}
+	return v
+}
+
 func (v *validator) isHigherThan(num int) *validator {
 	if v.err != nil {
 		return v
@@ -18,11 +18,15 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package controllers
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
 
 	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/logging"
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+	"github.com/AbsaOSS/k8gb/controllers/tracing"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	externaldns "sigs.k8s.io/external-dns/endpoint"
@@ -34,30 +38,97 @@ func sortTargets(targets []string) []string {
 	})
 	return targets
 }
-func (r *GslbReconciler) gslbDNSEndpoint(gslb *k8gbv1beta1.Gslb) (*externaldns.DNSEndpoint, error) {
+
+// hostStrategy returns strategy of the host, which is the Gslb strategy with override of the host rule applied
+func hostStrategy(gslb *k8gbv1beta1.Gslb, host string) k8gbv1beta1.Strategy {
+	strategy := gslb.Spec.Strategy
+	for _, rule := range gslb.Spec.Ingress.Rules {
+		if rule.Host != host || rule.Strategy == nil {
+			continue
+		}
+		if rule.Strategy.Type != "" {
+			strategy.Type = rule.Strategy.Type
+		}
+		if rule.Strategy.PrimaryGeoTag != "" {
+			strategy.PrimaryGeoTag = rule.Strategy.PrimaryGeoTag
+		}
+		if rule.Strategy.DNSTtlSeconds != 0 {
+			strategy.DNSTtlSeconds = rule.Strategy.DNSTtlSeconds
+		}
+	}
+	return strategy
+}
+
+// failoverActiveCluster returns geo tag of the cluster serving failover host. External targets of primary cluster
+// come from secondary clusters, which are listed all.
+func (r *GslbReconciler) failoverActiveCluster(strategy k8gbv1beta1.Strategy, localServed, externalServed bool) string {
+	if strategy.PrimaryGeoTag == r.Config.ClusterGeoTag {
+		switch {
+		case localServed:
+			return r.Config.ClusterGeoTag
+		case externalServed:
+			secondary := append([]string{}, r.Config.ExtClustersGeoTags...)
+			sort.Strings(secondary)
+			return strings.Join(secondary, ",")
+		}
+		return metrics.NoActiveCluster
+	}
+	switch {
+	case externalServed:
+		return strategy.PrimaryGeoTag
+	case localServed:
+		return r.Config.ClusterGeoTag
+	}
+	return metrics.NoActiveCluster
+}
+
+// gslbDNSEndpoint returns DNSEndpoint of the Gslb together with changes of host targets against the targets
+// observed in status
+func (r *GslbReconciler) gslbDNSEndpoint(ctx context.Context, gslb *k8gbv1beta1.Gslb) (
+	*externaldns.DNSEndpoint, map[string]k8gbv1beta1.TargetChange, error) {
 	var gslbHosts []*externaldns.Endpoint
-	var ttl = externaldns.TTL(gslb.Spec.Strategy.DNSTtlSeconds)
+	changes := make(map[string]k8gbv1beta1.TargetChange)
+	draining, drained := r.drainPhase(gslb)
 
+	_, healthSpan := tracing.Start(ctx, "health.evaluate")
 	serviceHealth, err := r.getServiceHealthStatus(gslb)
+	tracing.End(healthSpan, err)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	localTargets, err := r.DNSProvider.GslbIngressExposedIPs(gslb)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	for host, health := range serviceHealth {
+	// endpoints are kept in stable order, so unchanged DNSEndpoint isn't rewritten
+	hosts := make([]string, 0, len(serviceHealth))
+	for host := range serviceHealth {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	var failoverHosts []string
+	for _, host := range hosts {
+		health := serviceHealth[host]
+		strategy := hostStrategy(gslb, host)
 		var finalTargets []string
+		var ttl = externaldns.TTL(strategy.DNSTtlSeconds)
+		if draining && (ttl == 0 || ttl > externaldns.TTL(r.Config.Drain.TTL)) {
+			ttl = externaldns.TTL(r.Config.Drain.TTL)
+		}
 
 		if !strings.Contains(host, r.Config.EdgeDNSZone) {
-			return nil, fmt.Errorf("ingress host %s does not match delegated zone %s", host, r.Config.EdgeDNSZone)
+			return nil, nil, fmt.Errorf("ingress host %s does not match delegated zone %s", host, r.Config.EdgeDNSZone)
 		}
 
-		if health == "Healthy" {
+		hostCtx, span := tracing.Start(ctx, "strategy.decide", tracing.HostKey.String(host),
+			tracing.StrategyKey.String(strategy.Type), tracing.HealthKey.String(health))
+		log := logging.FromContext(hostCtx)
+
+		if health == "Healthy" && !drained {
 			finalTargets = append(finalTargets, localTargets...)
-			localTargetsHost := fmt.Sprintf("localtargets-%s", host)
+			localTargetsHost := localTargetsPrefix + host
 			dnsRecord := &externaldns.Endpoint{
 				DNSName:    localTargetsHost,
 				RecordTTL:  ttl,
@@ -68,23 +139,23 @@ func (r *GslbReconciler) gslbDNSEndpoint(gslb *k8gbv1beta1.Gslb) (*externaldns.D
 		}
 
 		// Check if host is alive on external Gslb
-		externalTargets := r.DNSProvider.GetExternalTargets(host)
+		externalTargets := r.DNSProvider.GetExternalTargets(hostCtx, host)
 
 		sortTargets(externalTargets)
 
 		if len(externalTargets) > 0 {
-			switch gslb.Spec.Strategy.Type {
+			switch strategy.Type {
 			case roundRobinStrategy, geoStrategy:
 				finalTargets = append(finalTargets, externalTargets...)
 			case failoverStrategy:
 				// If cluster is Primary
-				if gslb.Spec.Strategy.PrimaryGeoTag == r.Config.ClusterGeoTag {
+				if strategy.PrimaryGeoTag == r.Config.ClusterGeoTag {
 					// If cluster is Primary and Healthy return only own targets
 					// If cluster is Primary and Unhealthy return Secondary external targets
-					if health != "Healthy" {
+					if health != "Healthy" || drained {
 						finalTargets = externalTargets
 						log.Info().Msgf("Executing failover strategy for %s Gslb on Primary. Workload on primary %s cluster is unhealthy, targets are %v",
-							gslb.Name, gslb.Spec.Strategy.PrimaryGeoTag, finalTargets)
+							gslb.Name, strategy.PrimaryGeoTag, finalTargets)
 					}
 				} else {
 					// If cluster is Secondary and Primary external cluster is Healthy
@@ -92,14 +163,27 @@ func (r *GslbReconciler) gslbDNSEndpoint(gslb *k8gbv1beta1.Gslb) (*externaldns.D
 					// Return own targets by default.
 					finalTargets = externalTargets
 					log.Info().Msgf("Executing failover strategy for %s Gslb on Secondary. Workload on primary %s cluster is healthy, targets are %v",
-						gslb.Name, gslb.Spec.Strategy.PrimaryGeoTag, finalTargets)
+						gslb.Name, strategy.PrimaryGeoTag, finalTargets)
 				}
 			}
 		} else {
 			log.Info().Msgf("No external targets have been found for host %s", host)
 		}
+		if strategy.Type == failoverStrategy {
+			failoverHosts = append(failoverHosts, host)
+			r.Metrics.UpdateFailoverActiveCluster(gslb, host,
+				r.failoverActiveCluster(strategy, health == "Healthy" && !drained, len(externalTargets) > 0))
+		}
 
-		log.Info().Msgf("Final target list for %s Gslb: %v", gslb.Name, finalTargets)
+		log.Debug().Msgf("Final target list for %s Gslb: %v", gslb.Name, finalTargets)
+		change, changed := r.targetChange(gslb, host, hostTargets{strategy: strategy, health: health, drained: drained,
+			local: localTargets, external: externalTargets, final: finalTargets})
+		if changed {
+			changes[host] = change
+		}
+		span.SetAttributes(tracing.LocalTargetsKey.StringSlice(localTargets),
+			tracing.ExternalTargetsKey.StringSlice(externalTargets), tracing.TargetsKey.StringSlice(finalTargets))
+		span.End()
 
 		if len(finalTargets) > 0 {
 			dnsRecord := &externaldns.Endpoint{
@@ -108,12 +192,13 @@ func (r *GslbReconciler) gslbDNSEndpoint(gslb *k8gbv1beta1.Gslb) (*externaldns.D
 				RecordType: "A",
 				Targets:    finalTargets,
 				Labels: externaldns.Labels{
-					"strategy": gslb.Spec.Strategy.Type,
+					"strategy": strategy.Type,
 				},
 			}
 			gslbHosts = append(gslbHosts, dnsRecord)
 		}
 	}
+	r.Metrics.RetainFailoverHosts(gslb, failoverHosts)
 	dnsEndpointSpec := externaldns.DNSEndpointSpec{
 		Endpoints: gslbHosts,
 	}
@@ -130,7 +215,7 @@ func (r *GslbReconciler) gslbDNSEndpoint(gslb *k8gbv1beta1.Gslb) (*externaldns.D
 
 	err = controllerutil.SetControllerReference(gslb, dnsEndpoint, r.Scheme)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	return dnsEndpoint, err
+	return dnsEndpoint, changes, err
 }
@@ -0,0 +1,68 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// isDrainRequested returns true if the Gslb should be taken out of global rotation, either because the whole
+// cluster is drained or the Gslb is annotated
+func (r *GslbReconciler) isDrainRequested(gslb *k8gbv1beta1.Gslb) bool {
+	return r.Config.Drain.Enabled || gslb.GetAnnotations()[DrainAnnotation] == "true"
+}
+
+// updateDrainStatus records in status when the drain of the Gslb started; the record is cleared once the drain
+// is called off and the Gslb returns to rotation
+func (r *GslbReconciler) updateDrainStatus(gslb *k8gbv1beta1.Gslb) {
+	switch requested := r.isDrainRequested(gslb); {
+	case requested && gslb.Status.DrainingSince == nil:
+		now := metav1.Now()
+		gslb.Status.DrainingSince = &now
+		log.Info().Msgf("Draining Gslb %s, targets of the cluster are removed in %vs", gslb.Name, r.Config.Drain.PeriodSeconds)
+	case !requested && gslb.Status.DrainingSince != nil:
+		gslb.Status.DrainingSince = nil
+		log.Info().Msgf("Gslb %s returns to global rotation", gslb.Name)
+	}
+}
+
+// drainPhase returns draining=true while the Gslb is taken out of rotation. Targets of the cluster are kept with
+// lowered TTL during the draining period, so resolvers drop cached records; drained=true once the period is over
+// and the targets are removed.
+func (r *GslbReconciler) drainPhase(gslb *k8gbv1beta1.Gslb) (draining, drained bool) {
+	if gslb.Status.DrainingSince == nil {
+		return false, false
+	}
+	return true, r.drainRemaining(gslb) == 0
+}
+
+// drainRemaining returns time left until targets of the draining Gslb are removed
+func (r *GslbReconciler) drainRemaining(gslb *k8gbv1beta1.Gslb) time.Duration {
+	if gslb.Status.DrainingSince == nil {
+		return 0
+	}
+	period := time.Duration(r.Config.Drain.PeriodSeconds) * time.Second
+	if remaining := period - time.Since(gslb.Status.DrainingSince.Time); remaining > 0 {
+		return remaining
+	}
+	return 0
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/providers/dns"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+)
+
+// externalTargetsProvider returns static targets of external clusters, the rest of calls is handled by embedded provider
+type externalTargetsProvider struct {
+	dns.Provider
+	targets []string
+}
+
+func (p externalTargetsProvider) GetExternalTargets(context.Context, string) []string {
+	return append([]string{}, p.targets...)
+}
+
+func provideDrainSettings(t *testing.T, drainEnabled bool, period int) testSettings {
+	t.Helper()
+	config := predefinedConfig
+	config.ClusterGeoTag = "eu"
+	config.Drain.Enabled = drainEnabled
+	config.Drain.TTL = 5
+	config.Drain.PeriodSeconds = period
+	settings := provideSettings(t, config)
+	err := settings.client.Get(context.TODO(), settings.request.NamespacedName, settings.ingress)
+	require.NoError(t, err, "Failed to get expected ingress")
+	settings.ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+	err = settings.client.Status().Update(context.TODO(), settings.ingress)
+	require.NoError(t, err, "Failed to update gslb Ingress Address")
+	return settings
+}
+
+func endpointsOf(t *testing.T, s testSettings) map[string]*externaldns.Endpoint {
+	t.Helper()
+	dnsEndpoint := &externaldns.DNSEndpoint{}
+	require.NoError(t, s.client.Get(context.TODO(), s.request.NamespacedName, dnsEndpoint))
+	endpoints := make(map[string]*externaldns.Endpoint)
+	for _, ep := range dnsEndpoint.Spec.Endpoints {
+		endpoints[ep.DNSName] = ep
+	}
+	return endpoints
+}
+
+func TestDrainingClusterKeepsTargetsWithLoweredTTL(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	settings := provideDrainSettings(t, true, 3600)
+	createHealthyService(t, &settings, serviceName)
+	defer deleteHealthyService(t, &settings, serviceName)
+	// act
+	res, err := settings.reconciler.Reconcile(context.TODO(), settings.request)
+	require.NoError(t, err)
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, settings.gslb))
+	// assert
+	assert.True(t, res.RequeueAfter > 0 && res.RequeueAfter <= time.Hour, "draining Gslb must be requeued when the period ends")
+	endpoints := endpointsOf(t, settings)
+	require.Contains(t, endpoints, "localtargets-roundrobin.cloud.example.com")
+	assert.Equal(t, externaldns.Targets{"10.0.0.1"}, endpoints["localtargets-roundrobin.cloud.example.com"].Targets)
+	assert.Equal(t, externaldns.TTL(5), endpoints["localtargets-roundrobin.cloud.example.com"].RecordTTL)
+	assert.Equal(t, externaldns.TTL(5), endpoints["roundrobin.cloud.example.com"].RecordTTL)
+	assert.NotNil(t, settings.gslb.Status.DrainingSince)
+}
+
+func TestDrainedGslbPublishesNoLocalTargets(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	settings := provideDrainSettings(t, false, 0)
+	settings.reconciler.DNSProvider = externalTargetsProvider{Provider: settings.reconciler.DNSProvider, targets: []string{"10.1.0.1"}}
+	createHealthyService(t, &settings, serviceName)
+	defer deleteHealthyService(t, &settings, serviceName)
+	settings.gslb.Annotations = map[string]string{DrainAnnotation: "true"}
+	require.NoError(t, settings.client.Update(context.TODO(), settings.gslb))
+	// act
+	reconcileAndUpdateGslb(t, settings)
+	// assert
+	endpoints := endpointsOf(t, settings)
+	assert.NotContains(t, endpoints, "localtargets-roundrobin.cloud.example.com")
+	require.Contains(t, endpoints, "roundrobin.cloud.example.com")
+	assert.Equal(t, externaldns.Targets{"10.1.0.1"}, endpoints["roundrobin.cloud.example.com"].Targets)
+	assert.Equal(t, "Healthy", settings.gslb.Status.ServiceHealth["roundrobin.cloud.example.com"])
+}
+
+func TestDrainedPrimaryFailsOver(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	settings := provideDrainSettings(t, true, 0)
+	settings.reconciler.DNSProvider = externalTargetsProvider{Provider: settings.reconciler.DNSProvider, targets: []string{"10.1.0.1"}}
+	createHealthyService(t, &settings, serviceName)
+	defer deleteHealthyService(t, &settings, serviceName)
+	settings.gslb.Spec.Strategy.Type = failoverStrategy
+	settings.gslb.Spec.Strategy.PrimaryGeoTag = "eu"
+	require.NoError(t, settings.client.Update(context.TODO(), settings.gslb))
+	// act
+	reconcileAndUpdateGslb(t, settings)
+	// assert
+	endpoints := endpointsOf(t, settings)
+	assert.NotContains(t, endpoints, "localtargets-roundrobin.cloud.example.com")
+	require.Contains(t, endpoints, "roundrobin.cloud.example.com")
+	assert.Equal(t, externaldns.Targets{"10.1.0.1"}, endpoints["roundrobin.cloud.example.com"].Targets)
+}
+
+func TestGslbReturnsToRotationWhenDrainIsCalledOff(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	settings := provideDrainSettings(t, false, 60)
+	createHealthyService(t, &settings, serviceName)
+	defer deleteHealthyService(t, &settings, serviceName)
+	since := metav1.NewTime(time.Now().Add(-time.Hour))
+	settings.gslb.Status.DrainingSince = &since
+	require.NoError(t, settings.client.Status().Update(context.TODO(), settings.gslb))
+	settings.gslb = &k8gbv1beta1.Gslb{}
+	// act
+	reconcileAndUpdateGslb(t, settings)
+	// assert
+	endpoints := endpointsOf(t, settings)
+	require.Contains(t, endpoints, "localtargets-roundrobin.cloud.example.com")
+	assert.Equal(t, externaldns.TTL(30), endpoints["localtargets-roundrobin.cloud.example.com"].RecordTTL)
+	assert.Nil(t, settings.gslb.Status.DrainingSince)
+}
@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/providers/dns"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+)
+
+// isDryRun returns true if DNS changes of the Gslb must not be written, either because the whole operator
+// runs in dry-run mode or the Gslb is annotated
+func isDryRun(config *depresolver.Config, gslb *k8gbv1beta1.Gslb) bool {
+	return config.DryRun || gslb.GetAnnotations()[dryRunAnnotation] == "true"
+}
+
+// planDNSEndpoint stores changes between existing and computed DNSEndpoint into status of the Gslb instead
+// of saving the DNSEndpoint
+func (r *GslbReconciler) planDNSEndpoint(ctx context.Context, gslb *k8gbv1beta1.Gslb, desired *externaldns.DNSEndpoint) error {
+	current := &externaldns.DNSEndpoint{}
+	err := r.Get(ctx, client.ObjectKey{Namespace: desired.Namespace, Name: desired.Name}, current)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	changes := diffEndpoints(current.Spec.Endpoints, desired.Spec.Endpoints)
+	if gslb.Status.DryRun == nil {
+		gslb.Status.DryRun = &k8gbv1beta1.DryRunStatus{}
+	}
+	gslb.Status.DryRun.DNSEndpoint = changes
+	if len(changes) > 0 {
+		log.Info().
+			Str("gslb", gslb.Name).
+			Interface("changes", changes).
+			Msg("Dry-run: DNSEndpoint changes not written")
+	}
+	return nil
+}
+
+// diffEndpoints returns changes turning current endpoints into desired ones; records are matched by name and type
+func diffEndpoints(current, desired []*externaldns.Endpoint) []k8gbv1beta1.DNSChange {
+	key := func(ep *externaldns.Endpoint) string {
+		return ep.DNSName + "/" + ep.RecordType
+	}
+	existing := make(map[string]*externaldns.Endpoint, len(current))
+	for _, ep := range current {
+		existing[key(ep)] = ep
+	}
+	var changes []k8gbv1beta1.DNSChange
+	for _, ep := range desired {
+		change := k8gbv1beta1.DNSChange{Name: ep.DNSName, Type: ep.RecordType, Targets: sortedTargets(ep.Targets), TTL: int64(ep.RecordTTL)}
+		old, found := existing[key(ep)]
+		delete(existing, key(ep))
+		switch {
+		case !found:
+			change.Action = dns.ChangeCreate
+		case old.RecordTTL != ep.RecordTTL || !reflect.DeepEqual(sortedTargets(old.Targets), change.Targets):
+			change.Action = dns.ChangeUpdate
+		default:
+			continue
+		}
+		changes = append(changes, change)
+	}
+	for _, ep := range current {
+		if _, removed := existing[key(ep)]; removed {
+			changes = append(changes, k8gbv1beta1.DNSChange{Action: dns.ChangeDelete, Name: ep.DNSName, Type: ep.RecordType})
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Name < changes[j].Name
+	})
+	return changes
+}
+
+func sortedTargets(targets externaldns.Targets) []string {
+	return sortTargets(append([]string{}, targets...))
+}
+
+// DryRunHandler serves DNS changes planned for Gslbs in dry-run mode as JSON
+type DryRunHandler struct {
+	client client.Reader
+	config *depresolver.Config
+}
+
+// dryRunReport is the body served by DryRunHandler
+type dryRunReport struct {
+	// DryRun is true when the whole operator runs in dry-run mode
+	DryRun bool `json:"dryRun"`
+	// Gslbs holds planned changes keyed by namespace/name of the Gslb
+	Gslbs map[string]*k8gbv1beta1.DryRunStatus `json:"gslbs"`
+}
+
+// NewDryRunHandler creates handler reading planned changes from status of Gslbs
+func NewDryRunHandler(c client.Reader, config *depresolver.Config) *DryRunHandler {
+	return &DryRunHandler{client: c, config: config}
+}
+
+func (h *DryRunHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	gslbList := &k8gbv1beta1.GslbList{}
+	if err := h.client.List(req.Context(), gslbList); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	report := dryRunReport{DryRun: h.config.DryRun, Gslbs: map[string]*k8gbv1beta1.DryRunStatus{}}
+	for _, gslb := range gslbList.Items {
+		if gslb.Status.DryRun != nil {
+			report.Gslbs[fmt.Sprintf("%s/%s", gslb.Namespace, gslb.Name)] = gslb.Status.DryRun
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		log.Err(err).Msg("Can't write dry-run report")
+	}
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestDryRunDoesNotWriteDNSEndpoint(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	config := predefinedConfig
+	config.DryRun = true
+	settings := provideSettings(t, config)
+	err := settings.client.Get(context.TODO(), settings.request.NamespacedName, settings.ingress)
+	require.NoError(t, err, "Failed to get expected ingress")
+	settings.ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+	err = settings.client.Status().Update(context.TODO(), settings.ingress)
+	require.NoError(t, err, "Failed to update gslb Ingress Address")
+	createHealthyService(t, &settings, serviceName)
+	defer deleteHealthyService(t, &settings, serviceName)
+	// act
+	reconcileAndUpdateGslb(t, settings)
+	// assert
+	err = settings.client.Get(context.TODO(), settings.request.NamespacedName, &externaldns.DNSEndpoint{})
+	assert.True(t, errors.IsNotFound(err), "DNSEndpoint must not be written in dry-run mode")
+	require.NotNil(t, settings.gslb.Status.DryRun)
+	assert.Contains(t, settings.gslb.Status.DryRun.DNSEndpoint, k8gbv1beta1.DNSChange{
+		Action: "create", Name: "roundrobin.cloud.example.com", Type: "A", Targets: []string{"10.0.0.1"}, TTL: 30})
+	assert.Empty(t, settings.gslb.Status.HealthyRecords)
+}
+
+func TestDryRunAnnotationKeepsExistingDNSEndpoint(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	settings := provideSettings(t, predefinedConfig)
+	before := &externaldns.DNSEndpoint{}
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, before))
+	settings.gslb.Annotations = map[string]string{dryRunAnnotation: "true"}
+	require.NoError(t, settings.client.Update(context.TODO(), settings.gslb))
+	err := settings.client.Get(context.TODO(), settings.request.NamespacedName, settings.ingress)
+	require.NoError(t, err, "Failed to get expected ingress")
+	settings.ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+	require.NoError(t, settings.client.Status().Update(context.TODO(), settings.ingress))
+	createHealthyService(t, &settings, serviceName)
+	defer deleteHealthyService(t, &settings, serviceName)
+	// act
+	reconcileAndUpdateGslb(t, settings)
+	// assert
+	after := &externaldns.DNSEndpoint{}
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, after))
+	assert.Equal(t, before.Spec, after.Spec)
+	require.NotNil(t, settings.gslb.Status.DryRun)
+	assert.NotEmpty(t, settings.gslb.Status.DryRun.DNSEndpoint)
+
+	// act: switching dry-run off writes the planned changes
+	gslb := &k8gbv1beta1.Gslb{}
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, gslb))
+	gslb.Annotations = nil
+	require.NoError(t, settings.client.Update(context.TODO(), gslb))
+	settings.gslb = &k8gbv1beta1.Gslb{}
+	reconcileAndUpdateGslb(t, settings)
+	// assert
+	assert.Nil(t, settings.gslb.Status.DryRun)
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, after))
+	assert.NotEqual(t, before.Spec, after.Spec)
+}
+
+func TestDiffEndpoints(t *testing.T) {
+	// arrange
+	current := []*externaldns.Endpoint{
+		{DNSName: "a.cloud.example.com", RecordType: "A", RecordTTL: 30, Targets: externaldns.Targets{"10.0.0.2", "10.0.0.1"}},
+		{DNSName: "b.cloud.example.com", RecordType: "A", RecordTTL: 30, Targets: externaldns.Targets{"10.0.0.1"}},
+		{DNSName: "c.cloud.example.com", RecordType: "A", RecordTTL: 30, Targets: externaldns.Targets{"10.0.0.1"}},
+	}
+	desired := []*externaldns.Endpoint{
+		{DNSName: "a.cloud.example.com", RecordType: "A", RecordTTL: 30, Targets: externaldns.Targets{"10.0.0.1", "10.0.0.2"}},
+		{DNSName: "b.cloud.example.com", RecordType: "A", RecordTTL: 60, Targets: externaldns.Targets{"10.0.0.1"}},
+		{DNSName: "d.cloud.example.com", RecordType: "A", RecordTTL: 30, Targets: externaldns.Targets{"10.0.0.3"}},
+	}
+	// act
+	changes := diffEndpoints(current, desired)
+	// assert
+	assert.Equal(t, []k8gbv1beta1.DNSChange{
+		{Action: "update", Name: "b.cloud.example.com", Type: "A", Targets: []string{"10.0.0.1"}, TTL: 60},
+		{Action: "delete", Name: "c.cloud.example.com", Type: "A"},
+		{Action: "create", Name: "d.cloud.example.com", Type: "A", Targets: []string{"10.0.0.3"}, TTL: 30},
+	}, changes)
+}
+
+func TestDryRunHandlerServesPlannedChanges(t *testing.T) {
+	// arrange
+	config := predefinedConfig
+	config.DryRun = true
+	settings := provideSettings(t, config)
+	handler := NewDryRunHandler(settings.client, &config)
+	recorder := httptest.NewRecorder()
+	// act
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/debug/dryrun", nil))
+	// assert
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	report := dryRunReport{}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &report))
+	assert.True(t, report.DryRun)
+	assert.Contains(t, report.Gslbs, settings.gslb.Namespace+"/"+settings.gslb.Name)
+}
@@ -27,11 +27,13 @@ func (r *GslbReconciler) finalizeGslb(gslb *k8gbv1beta1.Gslb) (err error) {
 	// needs to do before the CR can be deleted. Examples
 	// of finalizers include performing backups and deleting
 	// resources that are not owned by this CR, like a PVC.
-	err = r.DNSProvider.Finalize(gslb)
+	err = r.ZoneDelegation.Release(context.TODO(), gslb)
 	if err != nil {
 		log.Err(err).Msg("Can't finalize GSLB")
 		return
 	}
+	r.Debug.forget(gslb)
+	r.Metrics.RetainFailoverHosts(gslb, nil)
 	log.Info().Msg("Successfully finalized Gslb")
 	return
 }
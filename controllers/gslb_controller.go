@@ -19,7 +19,7 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"strconv"
+	"time"
 
 	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
 	"github.com/AbsaOSS/k8gb/controllers/depresolver"
@@ -27,17 +27,16 @@ import (
 	"github.com/AbsaOSS/k8gb/controllers/logging"
 	"github.com/AbsaOSS/k8gb/controllers/providers/dns"
 	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+	"github.com/AbsaOSS/k8gb/controllers/tracing"
 
 	str "github.com/AbsaOSS/gopkg/strings"
 	corev1 "k8s.io/api/core/v1"
 	v1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	types "k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
-	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
@@ -52,26 +51,48 @@ type GslbReconciler struct {
 	DepResolver *depresolver.DependencyResolver
 	Metrics     *metrics.PrometheusMetrics
 	DNSProvider dns.Provider
+	// ZoneDelegation is released by finalizer of the Gslb
+	ZoneDelegation *ZoneDelegationReconciler
+	// PeerTargets triggers reconciliation when targets of external clusters change
+	PeerTargets *PeerTargetsRefresher
+	// Debug keeps state served by debug API; nil unless the API is enabled
+	Debug *DebugState
 }
 
+// Annotations of Ingress and Gslb, which are set also by kubectl-k8gb plugin
+const (
+	// StrategyAnnotation creates Gslb of the annotated Ingress with given strategy
+	StrategyAnnotation = "k8gb.io/strategy"
+	// PrimaryGeoTagAnnotation sets primary cluster of failover strategy of Gslb created from the annotated Ingress
+	PrimaryGeoTagAnnotation = "k8gb.io/primary-geotag"
+	// DrainAnnotation takes the annotated Gslb out of global rotation
+	DrainAnnotation = "k8gb.io/drain"
+)
+
 const (
 	gslbFinalizer                        = "k8gb.absa.oss/finalizer"
 	geoStrategy                          = "geoip"
 	roundRobinStrategy                   = "roundRobin"
 	failoverStrategy                     = "failover"
-	primaryGeoTagAnnotation              = "k8gb.io/primary-geotag"
-	strategyAnnotation                   = "k8gb.io/strategy"
 	dnsTTLSecondsAnnotation              = "k8gb.io/dns-ttl-seconds"
 	splitBrainThresholdSecondsAnnotation = "k8gb.io/splitbrain-threshold-seconds"
+	dryRunAnnotation                     = "k8gb.io/dry-run"
+	// backendServiceIndex indexes Gslbs by names of referenced backend services
+	backendServiceIndex = "spec.ingress.backendServices"
 )
 
 var log = logging.Logger()
 
 // +kubebuilder:rbac:groups=k8gb.absa.oss,resources=gslbs,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=k8gb.absa.oss,resources=gslbs/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=k8gb.absa.oss,resources=gslbpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 
 // Reconcile runs main reconiliation loop
 func (r *GslbReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	ctx, span := tracing.Start(ctx, "reconcile", tracing.GslbKey.String(req.NamespacedName.String()))
+	defer span.End()
+	log := logging.FromContext(ctx)
 	result := utils.NewReconcileResultHandler(r.Config.ReconcileRequeueSeconds)
 	// Fetch the Gslb instance
 	gslb := &k8gbv1beta1.Gslb{}
@@ -85,14 +106,9 @@ func (r *GslbReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		}
 		return result.RequeueError(fmt.Errorf("error reading the object (%s)", err))
 	}
+	// status is written only when it differs from the observed one
+	observedStatus := gslb.Status.DeepCopy()
 
-	err = r.DepResolver.ResolveGslbSpec(ctx, gslb, r.Client)
-	if err != nil {
-		return result.RequeueError(fmt.Errorf("resolving spec (%s)", err))
-	}
-	log.Debug().
-		Str("Strategy", str.ToString(gslb.Spec.Strategy)).
-		Msg("Resolved strategy")
 	// == Finalizer business ==
 
 	// Check if the Gslb instance is marked to be deleted, which is
@@ -130,185 +146,209 @@ func (r *GslbReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.
 		}
 	}
 
-	// == Ingress ==========
-	ingress, err := r.gslbIngress(gslb)
+	// == Spec ==========
+	// defaults are resolved in memory, after finalizer is written, so they aren't persisted
+	specCtx, specSpan := tracing.Start(ctx, "reconcile.spec")
+	err = r.DepResolver.ResolveGslbSpec(specCtx, gslb, r.Client)
+	tracing.End(specSpan, err)
+	if _, invalid := err.(*depresolver.InvalidSpecError); invalid {
+		log.Err(err).Msgf("Invalid spec of Gslb %s/%s", gslb.Namespace, gslb.Name)
+		setSpecValidCondition(gslb, err)
+		if err = r.saveStatus(gslb, observedStatus); err != nil {
+			return result.RequeueError(err)
+		}
+		// Gslb is reconciled again when its spec or GslbPolicy changes
+		return result.Stop()
+	}
 	if err != nil {
-		return result.RequeueError(err)
+		return result.RequeueError(fmt.Errorf("resolving spec (%s)", err))
 	}
+	setSpecValidCondition(gslb, nil)
+	log.Debug().
+		Str("Strategy", str.ToString(gslb.Spec.Strategy)).
+		Msg("Resolved strategy")
 
-	err = r.saveIngress(gslb, ingress)
+	// == Ingress ==========
+	err = r.runStage(ctx, metrics.IngressStage, func(context.Context) error {
+		return r.reconcileIngress(gslb)
+	})
 	if err != nil {
 		return result.RequeueError(err)
 	}
 
 	// == external-dns dnsendpoints CRs ==
-	dnsEndpoint, err := r.gslbDNSEndpoint(gslb)
+	err = r.runStage(ctx, metrics.DNSEndpointStage, func(ctx context.Context) error {
+		return r.reconcileDNSEndpoint(ctx, gslb)
+	})
 	if err != nil {
 		return result.RequeueError(err)
 	}
 
-	err = r.DNSProvider.SaveDNSEndpoint(gslb, dnsEndpoint)
+	// == Status =
+	err = r.runStage(ctx, metrics.StatusStage, func(context.Context) error {
+		return r.updateGslbStatus(gslb, observedStatus)
+	})
 	if err != nil {
 		return result.RequeueError(err)
 	}
 
-	// == handle delegated zone in Edge DNS
-	err = r.DNSProvider.CreateZoneDelegationForExternalDNS(gslb)
+	// == Finish ==========
+	// Local changes are driven by watches and changes of external targets by PeerTargets refresher,
+	// so the Gslb is requeued only when draining period is about to end
+	if remaining := r.drainRemaining(gslb); remaining > 0 {
+		return result.RequeueAfter(remaining)
+	}
+	return result.Stop()
+}
+
+// runStage runs reconcile stage in its own span and records its duration and failure
+func (r *GslbReconciler) runStage(ctx context.Context, stage string, fn func(context.Context) error) error {
+	ctx, span := tracing.Start(ctx, "reconcile."+stage)
+	start := time.Now()
+	err := fn(ctx)
+	r.Metrics.ObserveReconcileStage(stage, start, err)
+	tracing.End(span, err)
+	return err
+}
+
+func (r *GslbReconciler) reconcileIngress(gslb *k8gbv1beta1.Gslb) error {
+	ingress, err := r.gslbIngress(gslb)
 	if err != nil {
-		log.Err(err).Msg("Unable to create zone delegation")
-		return result.Requeue()
+		return err
 	}
+	return r.saveIngress(gslb, ingress)
+}
 
-	// == Status =
-	err = r.updateGslbStatus(gslb)
+func (r *GslbReconciler) reconcileDNSEndpoint(ctx context.Context, gslb *k8gbv1beta1.Gslb) error {
+	r.updateDrainStatus(gslb)
+	dnsEndpoint, changes, err := r.gslbDNSEndpoint(ctx, gslb)
 	if err != nil {
-		return result.RequeueError(err)
+		return err
+	}
+	r.Debug.recordDNSEndpoint(gslb, dnsEndpoint)
+	if isDryRun(r.Config, gslb) {
+		return r.planDNSEndpoint(ctx, gslb, dnsEndpoint)
 	}
+	gslb.Status.DryRun = nil
+	_, span := tracing.Start(ctx, "provider.SaveDNSEndpoint", tracing.ProviderKey.String(providerName(r.DNSProvider)))
+	err = r.DNSProvider.SaveDNSEndpoint(gslb, dnsEndpoint)
+	tracing.End(span, err)
+	if err != nil {
+		return err
+	}
+	// targets are changed only once DNSEndpoint is written
+	r.recordTargetChanges(gslb, changes)
+	return nil
+}
 
-	// == Finish ==========
-	// Everything went fine, requeue after some time to catch up
-	// with external Gslb status
-	// TODO: potentially enhance with smarter reaction to external Event
-	return result.Requeue()
+// providerName names DNS provider in spans
+func providerName(provider dns.Provider) string {
+	if s, ok := provider.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return fmt.Sprintf("%T", provider)
 }
 
 // SetupWithManager configures controller manager
 func (r *GslbReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	// Figure out Gslb resource name to Reconcile when non controlled Name is updated
 
-	endpointMapHandler := handler.EnqueueRequestsFromMapFunc(
+	err := mgr.GetFieldIndexer().IndexField(context.TODO(), &k8gbv1beta1.Gslb{}, backendServiceIndex, gslbBackendServices)
+	if err != nil {
+		return err
+	}
+	endpointMapHandler := handler.EnqueueRequestsFromMapFunc(gslbsForEndpoints(mgr.GetClient()))
+
+	// Gslbs created from annotations don't own the Ingress, but its status determines their targets
+	ingressMapHandler := handler.EnqueueRequestsFromMapFunc(
 		func(a client.Object) []reconcile.Request {
-			gslbList := &k8gbv1beta1.GslbList{}
-			opts := []client.ListOption{
-				client.InNamespace(a.GetNamespace()),
-			}
-			c := mgr.GetClient()
-			err := c.List(context.TODO(), gslbList, opts...)
-			if err != nil {
-				log.Info().Msg("Can't fetch gslb objects")
-				return nil
-			}
-			gslbName := ""
-			for _, gslb := range gslbList.Items {
-				for _, rule := range gslb.Spec.Ingress.Rules {
-					for _, path := range rule.HTTP.Paths {
-						if path.Backend.ServiceName == a.GetName() {
-							gslbName = gslb.Name
-						}
-					}
-				}
-			}
-			if len(gslbName) > 0 {
-				return []reconcile.Request{
-					{NamespacedName: types.NamespacedName{
-						Name:      gslbName,
-						Namespace: a.GetNamespace(),
-					}},
-				}
-			}
-			return nil
+			return []reconcile.Request{{NamespacedName: types.NamespacedName{
+				Name:      a.GetName(),
+				Namespace: a.GetNamespace(),
+			}}}
 		})
 
-	createGslbFromIngress := func(annotationKey string, annotationValue string, a client.Object, strategy string) {
-		log.Info().Msgf("Detected strategy annotation(%s:%s) on Ingress(%s)",
-			annotationKey, annotationValue, a.GetName())
-		c := mgr.GetClient()
-		ingressToReuse := &v1beta1.Ingress{}
-		err := c.Get(context.Background(), client.ObjectKey{
-			Namespace: a.GetNamespace(),
-			Name:      a.GetName(),
-		}, ingressToReuse)
-		if err != nil {
-			log.Info().Msgf("Ingress(%s) does not exist anymore. Skipping Glsb creation...", a.GetName())
-			return
-		}
-		gslbExist := &k8gbv1beta1.Gslb{}
-		err = c.Get(context.Background(), client.ObjectKey{
-			Namespace: a.GetNamespace(),
-			Name:      a.GetName(),
-		}, gslbExist)
-		if err == nil {
-			log.Info().Msgf("Gslb(%s) already exists. Skipping Gslb creation...", gslbExist.Name)
-			return
-		}
-		gslb := &k8gbv1beta1.Gslb{
-			ObjectMeta: metav1.ObjectMeta{
-				Namespace:   a.GetNamespace(),
-				Name:        a.GetName(),
-				Annotations: a.GetAnnotations(),
-			},
-			Spec: k8gbv1beta1.GslbSpec{
-				Ingress: k8gbv1beta1.FromV1Beta1IngressSpec(ingressToReuse.Spec),
-				Strategy: k8gbv1beta1.Strategy{
-					Type: strategy,
-				},
-			},
+	b := ctrl.NewControllerManagedBy(mgr).
+		For(&k8gbv1beta1.Gslb{}).
+		Owns(&v1beta1.Ingress{}).
+		Owns(&externaldns.DNSEndpoint{}).
+		Watches(&source.Kind{Type: &corev1.Endpoints{}}, endpointMapHandler).
+		Watches(&source.Kind{Type: &v1beta1.Ingress{}}, ingressMapHandler).
+		Watches(&source.Kind{Type: &k8gbv1beta1.GslbPolicy{}}, handler.EnqueueRequestsFromMapFunc(allGslbs(mgr.GetClient())))
+	if r.PeerTargets != nil {
+		if err := mgr.Add(r.PeerTargets); err != nil {
+			return err
 		}
+		b = b.Watches(r.PeerTargets.Source(), &handler.EnqueueRequestForObject{})
+	}
+	return b.Complete(r)
+}
 
-		strToInt := func(str string) int {
-			intValue, err := strconv.Atoi(str)
-			if err != nil {
-				log.Err(err).Msgf("can't convert string to int (%s)", err)
-			}
-			return intValue
+// gslbBackendServices indexes Gslb by names of services referenced by its default backend and rule paths
+func gslbBackendServices(o client.Object) (services []string) {
+	gslb, ok := o.(*k8gbv1beta1.Gslb)
+	if !ok {
+		return nil
+	}
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if name != "" && !seen[name] {
+			seen[name] = true
+			services = append(services, name)
 		}
-
-		for annotationKey, annotationValue := range a.GetAnnotations() {
-			switch annotationKey {
-			case dnsTTLSecondsAnnotation:
-				gslb.Spec.Strategy.DNSTtlSeconds = strToInt(annotationValue)
-			case splitBrainThresholdSecondsAnnotation:
-				gslb.Spec.Strategy.SplitBrainThresholdSeconds = strToInt(annotationValue)
-			}
+	}
+	if gslb.Spec.Ingress.Backend != nil {
+		add(gslb.Spec.Ingress.Backend.ServiceName)
+	}
+	for _, rule := range gslb.Spec.Ingress.Rules {
+		if rule.HTTP == nil {
+			continue
 		}
-
-		if strategy == failoverStrategy {
-			for annotationKey, annotationValue := range a.GetAnnotations() {
-				if annotationKey == primaryGeoTagAnnotation {
-					gslb.Spec.Strategy.PrimaryGeoTag = annotationValue
-				}
-			}
-			if gslb.Spec.Strategy.PrimaryGeoTag == "" {
-				log.Info().Msgf("%s annotation is missing, skipping Gslb creation...", primaryGeoTagAnnotation)
-				return
-			}
+		for _, path := range rule.HTTP.Paths {
+			add(path.Backend.ServiceName)
 		}
+	}
+	return services
+}
 
-		err = controllerutil.SetControllerReference(ingressToReuse, gslb, r.Scheme)
+// gslbsForEndpoints maps Endpoints to every Gslb in the namespace referencing the service of the same name
+func gslbsForEndpoints(c client.Reader) handler.MapFunc {
+	return func(a client.Object) []reconcile.Request {
+		gslbList := &k8gbv1beta1.GslbList{}
+		err := c.List(context.TODO(), gslbList,
+			client.InNamespace(a.GetNamespace()),
+			client.MatchingFields{backendServiceIndex: a.GetName()})
 		if err != nil {
-			log.Err(err).
-				Str("Ingress", ingressToReuse.Name).
-				Str("Gslb", gslb.Name).
-				Msg("Cannot set the Ingress as the owner of the Gslb")
+			log.Err(err).Msg("Can't fetch gslb objects")
+			return nil
 		}
-
-		log.Info().Msgf("Creating new Gslb(%s) out of Ingress annotation", gslb.Name)
-		err = c.Create(context.Background(), gslb)
-		if err != nil {
-			log.Err(err).Msg("Glsb creation failed")
+		requests := make([]reconcile.Request, 0, len(gslbList.Items))
+		for _, gslb := range gslbList.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      gslb.Name,
+				Namespace: gslb.Namespace,
+			}})
 		}
+		return requests
 	}
+}
 
-	ingressMapHandler := handler.EnqueueRequestsFromMapFunc(
-		func(a client.Object) []reconcile.Request {
-			for annotationKey, annotationValue := range a.GetAnnotations() {
-				if annotationKey == strategyAnnotation {
-					switch annotationValue {
-					case roundRobinStrategy:
-						createGslbFromIngress(annotationKey, annotationKey, a, roundRobinStrategy)
-					case failoverStrategy:
-						createGslbFromIngress(annotationKey, annotationKey, a, failoverStrategy)
-					}
-				}
-			}
+// allGslbs maps any object to all Gslbs, e.g. GslbPolicy, which may select namespaces of any Gslb
+func allGslbs(c client.Reader) handler.MapFunc {
+	return func(client.Object) []reconcile.Request {
+		gslbList := &k8gbv1beta1.GslbList{}
+		err := c.List(context.TODO(), gslbList)
+		if err != nil {
+			log.Err(err).Msg("Can't fetch gslb objects")
 			return nil
-		})
-
-	return ctrl.NewControllerManagedBy(mgr).
-		For(&k8gbv1beta1.Gslb{}).
-		Owns(&v1beta1.Ingress{}).
-		Owns(&externaldns.DNSEndpoint{}).
-		Watches(&source.Kind{Type: &corev1.Endpoints{}}, endpointMapHandler).
-		Watches(&source.Kind{Type: &v1beta1.Ingress{}}, ingressMapHandler).
-		Complete(r)
+		}
+		requests := make([]reconcile.Request, 0, len(gslbList.Items))
+		for _, gslb := range gslbList.Items {
+			requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+				Name:      gslb.Name,
+				Namespace: gslb.Namespace,
+			}})
+		}
+		return requests
+	}
 }
@@ -19,6 +19,7 @@ package controllers
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net"
@@ -34,15 +35,21 @@ import (
 	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
 	"github.com/AbsaOSS/k8gb/controllers/providers/dns"
 	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+	"github.com/AbsaOSS/k8gb/controllers/tracing"
 
 	str "github.com/AbsaOSS/gopkg/strings"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	otelattribute "go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -52,6 +59,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	externaldns "sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/yaml"
 )
 
 type testSettings struct {
@@ -67,6 +75,16 @@ type testSettings struct {
 
 var crSampleYaml = "../deploy/crds/k8gb.absa.oss_v1beta1_gslb_cr.yaml"
 
+var gslbCRDYaml = "../chart/k8gb/templates/crds/k8gb.absa.oss_gslbs.yaml"
+
+// jsonSchema is the part of CRD validation schema inspected by tests
+type jsonSchema struct {
+	Properties map[string]jsonSchema `json:"properties"`
+	Items      *jsonSchema           `json:"items"`
+	Required   []string              `json:"required"`
+	Enum       []string              `json:"enum"`
+}
+
 var predefinedConfig = depresolver.Config{
 	ReconcileRequeueSeconds: 30,
 	ClusterGeoTag:           "us-west-1",
@@ -85,6 +103,9 @@ var predefinedConfig = depresolver.Config{
 		HTTPPoolConnections: 20,
 		HTTPRequestTimeout:  10,
 	},
+	ZoneDelegation: depresolver.ZoneDelegation{
+		TTL: 30,
+	},
 	Override: depresolver.Override{
 		FakeInfobloxEnabled: true,
 	},
@@ -290,9 +311,23 @@ func TestMetricLinterCheck(t *testing.T) {
 	require.NoError(t, err)
 	healthyRecordsMetric := settings.reconciler.Metrics.GetHealthyRecordsMetric()
 	ingressHostsPerStatusMetric := settings.reconciler.Metrics.GetIngressHostsPerStatusMetric()
+	reconcileDurationMetric := settings.reconciler.Metrics.GetReconcileDurationMetric()
+	reconcileErrorsMetric := settings.reconciler.Metrics.GetReconcileErrorsMetric()
+	failoverTransitionsMetric := settings.reconciler.Metrics.GetFailoverTransitionsMetric()
+	failoverActiveClusterMetric := settings.reconciler.Metrics.GetFailoverActiveClusterMetric()
+	peerLookupErrorsMetric := settings.reconciler.Metrics.GetPeerLookupErrorsMetric()
+	heartbeatAgeMetric := settings.reconciler.Metrics.GetHeartbeatAgeMetric()
+	providerErrorsMetric := settings.reconciler.Metrics.GetProviderErrorsMetric()
 	for name, scenario := range map[string]prometheus.Collector{
-		"healthy_records":          healthyRecordsMetric,
-		"ingress_hosts_per_status": ingressHostsPerStatusMetric,
+		"healthy_records":            healthyRecordsMetric,
+		"ingress_hosts_per_status":   ingressHostsPerStatusMetric,
+		"reconcile_duration_seconds": reconcileDurationMetric,
+		"reconcile_errors_total":     reconcileErrorsMetric,
+		"failover_transitions_total": failoverTransitionsMetric,
+		"failover_active_cluster":    failoverActiveClusterMetric,
+		"peer_lookup_errors_total":   peerLookupErrorsMetric,
+		"heartbeat_age_seconds":      heartbeatAgeMetric,
+		"provider_errors_total":      providerErrorsMetric,
 	} {
 		// act
 		// assert
@@ -302,6 +337,105 @@ func TestMetricLinterCheck(t *testing.T) {
 	}
 }
 
+func TestFailoverTransitionIsCounted(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	host := "roundrobin.cloud.example.com"
+	customConfig := predefinedConfig
+	customConfig.ClusterGeoTag = "eu"
+	settings := provideSettings(t, customConfig)
+	settings.gslb.Spec.Strategy.Type = failoverStrategy
+	settings.gslb.Spec.Strategy.PrimaryGeoTag = "eu"
+	err := settings.client.Update(context.TODO(), settings.gslb)
+	require.NoError(t, err, "Can't update gslb")
+	createHealthyService(t, &settings, serviceName)
+	reconcileAndUpdateGslb(t, settings)
+	// act
+	deleteHealthyService(t, &settings, serviceName)
+	reconcileAndUpdateGslb(t, settings)
+	// assert
+	transitions := settings.reconciler.Metrics.GetFailoverTransitionsMetric()
+	assert.Equal(t, 1., testutil.ToFloat64(transitions.With(prometheus.Labels{"namespace": settings.gslb.Namespace,
+		"name": settings.gslb.Name, "host": host, "from": "eu", "to": metrics.NoActiveCluster})))
+	active := settings.reconciler.Metrics.GetFailoverActiveClusterMetric()
+	// single series per host, the one of previously active cluster is removed
+	assert.Equal(t, len(settings.gslb.Spec.Ingress.Rules), testutil.CollectAndCount(active))
+	assert.Equal(t, 1., testutil.ToFloat64(active.With(prometheus.Labels{"namespace": settings.gslb.Namespace,
+		"name": settings.gslb.Name, "host": host, "geotag": metrics.NoActiveCluster})))
+}
+
+func TestFailoverActiveClusterOfRemovedHostsIsForgotten(t *testing.T) {
+	// arrange
+	customConfig := predefinedConfig
+	customConfig.ClusterGeoTag = "eu"
+	settings := provideSettings(t, customConfig)
+	settings.gslb.Spec.Strategy.Type = failoverStrategy
+	settings.gslb.Spec.Strategy.PrimaryGeoTag = "eu"
+	err := settings.client.Update(context.TODO(), settings.gslb)
+	require.NoError(t, err, "Can't update gslb")
+	reconcileAndUpdateGslb(t, settings)
+	active := settings.reconciler.Metrics.GetFailoverActiveClusterMetric()
+	require.Equal(t, len(settings.gslb.Spec.Ingress.Rules), testutil.CollectAndCount(active))
+	// act
+	settings.gslb.Spec.Ingress.Rules = settings.gslb.Spec.Ingress.Rules[:1]
+	err = settings.client.Update(context.TODO(), settings.gslb)
+	require.NoError(t, err, "Can't update gslb")
+	reconcileAndUpdateGslb(t, settings)
+	retained := testutil.CollectAndCount(active)
+	err = settings.reconciler.finalizeGslb(settings.gslb)
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 1, retained)
+	assert.Zero(t, testutil.CollectAndCount(active))
+}
+
+func TestTargetChangesAreKeptInBoundedHistory(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	host := "roundrobin.cloud.example.com"
+	customConfig := predefinedConfig
+	customConfig.Audit.HistorySize = 2
+	settings := provideSettings(t, customConfig)
+	settings.ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}, {IP: "10.0.0.2"}}
+	err := settings.client.Status().Update(context.TODO(), settings.ingress)
+	require.NoError(t, err, "Failed to update gslb Ingress Address")
+	createHealthyService(t, &settings, serviceName)
+	reconcileAndUpdateGslb(t, settings)
+	deleteHealthyService(t, &settings, serviceName)
+	reconcileAndUpdateGslb(t, settings)
+	// act
+	createHealthyService(t, &settings, serviceName)
+	reconcileAndUpdateGslb(t, settings)
+	reconcileAndUpdateGslb(t, settings)
+	// assert
+	history := settings.gslb.Status.TargetHistory[host]
+	require.Len(t, history, 2, "history is bounded and unchanged targets are not recorded")
+	assert.Equal(t, "NotFound", history[0].Reason)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, history[0].OldTargets)
+	assert.Empty(t, history[0].NewTargets)
+	assert.Equal(t, "Healthy", history[1].Reason)
+	assert.Empty(t, history[1].OldTargets)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, history[1].NewTargets)
+	assert.Equal(t, roundRobinStrategy, history[1].Strategy)
+	assert.NotContains(t, settings.gslb.Status.TargetHistory, "notfound.cloud.example.com",
+		"targets of host without service are never published")
+}
+
+func TestFailedReconcileStageIsCounted(t *testing.T) {
+	// arrange
+	customConfig := predefinedConfig
+	customConfig.EdgeDNSZone = "otherdnszone.com"
+	settings := provideSettings(t, predefinedConfig)
+	settings.reconciler.Config = &customConfig
+	// act
+	_, err := settings.reconciler.Reconcile(context.TODO(), settings.request)
+	// assert
+	require.Error(t, err)
+	stageErrors := settings.reconciler.Metrics.GetReconcileErrorsMetric()
+	assert.Equal(t, 1., testutil.ToFloat64(stageErrors.With(prometheus.Labels{"stage": metrics.DNSEndpointStage})))
+	assert.Equal(t, 0., testutil.ToFloat64(stageErrors.With(prometheus.Labels{"stage": metrics.IngressStage})))
+}
+
 func TestGslbCreatesDNSEndpointCRForHealthyIngressHosts(t *testing.T) {
 	// arrange
 	serviceName := "frontend-podinfo"
@@ -615,11 +749,74 @@ func TestReturnsExternalRecordsUsingFailoverStrategy(t *testing.T) {
 		}).RequireNoError(t)
 }
 
+func TestReconcileIsTracedWithChosenTargets(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	defer otel.SetTracerProvider(otel.GetTracerProvider())
+	otel.SetTracerProvider(provider)
+	customConfig := predefinedConfig
+	customConfig.ClusterGeoTag = "za"
+	customConfig.EdgeDNSServer = "localhost"
+	utils.NewFakeDNS(fakeDNSSettings).
+		AddARecord("localtargets-roundrobin.cloud.example.com.", net.IPv4(10, 1, 0, 1)).
+		Start().
+		RunTestFunc(func() {
+			settings := provideSettings(t, customConfig)
+			settings.gslb.Spec.Strategy.Type = failoverStrategy
+			settings.gslb.Spec.Strategy.PrimaryGeoTag = "eu"
+			err := settings.client.Update(context.TODO(), settings.gslb)
+			require.NoError(t, err, "Can't update gslb")
+			createHealthyService(t, &settings, serviceName)
+			defer deleteHealthyService(t, &settings, serviceName)
+			exporter.Reset()
+			// act
+			reconcileAndUpdateGslb(t, settings)
+		}).RequireNoError(t)
+	// assert
+	spans := make(map[string][]tracetest.SpanStub)
+	for _, span := range exporter.GetSpans() {
+		spans[span.Name] = append(spans[span.Name], span)
+	}
+	for _, name := range []string{"reconcile", "reconcile.spec", "reconcile.ingress", "reconcile.dnsendpoint", "reconcile.status",
+		"health.evaluate", "strategy.decide", "peer.lookup", "provider.SaveDNSEndpoint"} {
+		require.NotEmpty(t, spans[name], "missing span %s", name)
+		assert.Equal(t, spans["reconcile"][0].SpanContext.TraceID(), spans[name][0].SpanContext.TraceID(),
+			"span %s belongs to other trace", name)
+	}
+	decision := hostSpan(spans["strategy.decide"], "roundrobin.cloud.example.com")
+	assert.Equal(t, failoverStrategy, attribute(decision, tracing.StrategyKey).AsString())
+	assert.Equal(t, []string{"10.1.0.1"}, attribute(decision, tracing.TargetsKey).AsStringSlice())
+	lookup := hostSpan(spans["peer.lookup"], "roundrobin.cloud.example.com")
+	assert.Equal(t, decision.SpanContext.SpanID(), lookup.Parent.SpanID(), "peer lookup isn't child of strategy decision")
+}
+
+// hostSpan returns span recorded for host
+func hostSpan(spans []tracetest.SpanStub, host string) (span tracetest.SpanStub) {
+	for _, s := range spans {
+		if attribute(s, tracing.HostKey).AsString() == host {
+			span = s
+		}
+	}
+	return span
+}
+
+// attribute returns value of span attribute with given key
+func attribute(span tracetest.SpanStub, key otelattribute.Key) otelattribute.Value {
+	for _, a := range span.Attributes {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return otelattribute.Value{}
+}
+
 func TestGslbProperlyPropagatesAnnotationDownToIngress(t *testing.T) {
 	// arrange
 	settings := provideSettings(t, predefinedConfig)
-	expectedAnnotations := map[string]string{"annotation": "test"}
-	settings.gslb.Annotations = expectedAnnotations
+	expectedAnnotations := map[string]string{"annotation": "test", StrategyAnnotation: roundRobinStrategy}
+	settings.gslb.Annotations = map[string]string{"annotation": "test"}
 	err := settings.client.Update(context.TODO(), settings.gslb)
 	require.NoError(t, err, "Can't update gslb")
 	// act
@@ -630,6 +827,146 @@ func TestGslbProperlyPropagatesAnnotationDownToIngress(t *testing.T) {
 	assert.Equal(t, expectedAnnotations, settings.ingress.Annotations)
 }
 
+func TestReconcileWithoutChangesDoesNotWriteResources(t *testing.T) {
+	// arrange
+	settings := provideSettings(t, predefinedConfig)
+	dnsEndpoint := &externaldns.DNSEndpoint{}
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, dnsEndpoint))
+	gslbVersion := settings.gslb.ResourceVersion
+	endpointVersion := dnsEndpoint.ResourceVersion
+	// act
+	reconcileAndUpdateGslb(t, settings)
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, dnsEndpoint))
+	// assert
+	assert.Equal(t, gslbVersion, settings.gslb.ResourceVersion)
+	assert.Equal(t, endpointVersion, dnsEndpoint.ResourceVersion)
+}
+
+func TestDefaultBackendDeterminesHealthOfHostWithoutPaths(t *testing.T) {
+	// arrange
+	serviceName := "default-podinfo"
+	settings := provideSettings(t, predefinedConfig)
+	settings.gslb.Spec.Ingress.Backend = &v1beta1.IngressBackend{ServiceName: serviceName}
+	settings.gslb.Spec.Ingress.Rules = append(settings.gslb.Spec.Ingress.Rules, k8gbv1beta1.IngressRule{Host: "default.cloud.example.com"})
+	require.NoError(t, settings.client.Update(context.TODO(), settings.gslb), "Can't update gslb")
+	createHealthyService(t, &settings, serviceName)
+	defer deleteHealthyService(t, &settings, serviceName)
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, settings.ingress))
+	settings.ingress.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "10.0.0.1"}}
+	require.NoError(t, settings.client.Status().Update(context.TODO(), settings.ingress))
+	ingress := &v1beta1.Ingress{}
+	// act
+	reconcileAndUpdateGslb(t, settings)
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, ingress))
+	// assert
+	assert.Equal(t, "Healthy", settings.gslb.Status.ServiceHealth["default.cloud.example.com"])
+	assert.Equal(t, "NotFound", settings.gslb.Status.ServiceHealth["roundrobin.cloud.example.com"])
+	assert.Equal(t, []string{"10.0.0.1"}, settings.gslb.Status.HealthyRecords["default.cloud.example.com"])
+	require.NotNil(t, ingress.Spec.Backend)
+	assert.Equal(t, serviceName, ingress.Spec.Backend.ServiceName)
+}
+
+func TestHostWithoutPathsAndDefaultBackendIsIgnored(t *testing.T) {
+	// arrange
+	settings := provideSettings(t, predefinedConfig)
+	settings.gslb.Spec.Ingress.Rules = append(settings.gslb.Spec.Ingress.Rules, k8gbv1beta1.IngressRule{Host: "default.cloud.example.com"})
+	require.NoError(t, settings.client.Update(context.TODO(), settings.gslb), "Can't update gslb")
+	// act
+	_, err := settings.reconciler.Reconcile(context.TODO(), settings.request)
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, settings.gslb))
+	// assert
+	require.NoError(t, err)
+	assert.NotContains(t, settings.gslb.Status.ServiceHealth, "default.cloud.example.com")
+	assert.Equal(t, "NotFound", settings.gslb.Status.ServiceHealth["notfound.cloud.example.com"])
+}
+
+func TestGslbCRDAllowsRulesWithoutHTTP(t *testing.T) {
+	// act
+	rule := gslbRuleSchema(t)
+	// assert
+	assert.Contains(t, rule.Properties, "http")
+	assert.NotContains(t, rule.Required, "http", "rules may use only the default backend")
+	rules, _ := json.Marshal(k8gbv1beta1.IngressRule{Host: "default.cloud.example.com"})
+	assert.NotContains(t, string(rules), "http")
+}
+
+func TestGslbCRDRestrictsHostStrategyType(t *testing.T) {
+	// act
+	rule := gslbRuleSchema(t)
+	// assert
+	assert.Equal(t, []string{roundRobinStrategy, failoverStrategy, geoStrategy}, rule.Properties["strategy"].Properties["type"].Enum)
+}
+
+func TestHostStrategyOverridesGslbStrategy(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	settings := provideDrainSettings(t, false, 0)
+	settings.reconciler.DNSProvider = externalTargetsProvider{Provider: settings.reconciler.DNSProvider, targets: []string{"10.1.0.1"}}
+	createHealthyService(t, &settings, serviceName)
+	defer deleteHealthyService(t, &settings, serviceName)
+	settings.gslb.Spec.Ingress.Rules = append(settings.gslb.Spec.Ingress.Rules, k8gbv1beta1.IngressRule{
+		Host:             "failover.cloud.example.com",
+		IngressRuleValue: settings.gslb.Spec.Ingress.Rules[2].IngressRuleValue,
+		Strategy:         &k8gbv1beta1.StrategyOverride{Type: failoverStrategy, PrimaryGeoTag: "us", DNSTtlSeconds: 10},
+	})
+	require.NoError(t, settings.client.Update(context.TODO(), settings.gslb), "Can't update gslb")
+	// act
+	reconcileAndUpdateGslb(t, settings)
+	// assert
+	endpoints := endpointsOf(t, settings)
+	require.Contains(t, endpoints, "roundrobin.cloud.example.com")
+	assert.Equal(t, externaldns.Targets{"10.0.0.1", "10.1.0.1"}, endpoints["roundrobin.cloud.example.com"].Targets)
+	assert.Equal(t, externaldns.TTL(30), endpoints["roundrobin.cloud.example.com"].RecordTTL)
+	require.Contains(t, endpoints, "failover.cloud.example.com")
+	// secondary cluster returns targets of healthy primary
+	assert.Equal(t, externaldns.Targets{"10.1.0.1"}, endpoints["failover.cloud.example.com"].Targets)
+	assert.Equal(t, externaldns.TTL(10), endpoints["failover.cloud.example.com"].RecordTTL)
+	assert.Equal(t, failoverStrategy, endpoints["failover.cloud.example.com"].Labels["strategy"])
+	assert.Equal(t, k8gbv1beta1.Strategy{Type: roundRobinStrategy, DNSTtlSeconds: 30, SplitBrainThresholdSeconds: 300},
+		settings.gslb.Status.Strategies["roundrobin.cloud.example.com"])
+	assert.Equal(t, k8gbv1beta1.Strategy{Type: failoverStrategy, PrimaryGeoTag: "us", DNSTtlSeconds: 10, SplitBrainThresholdSeconds: 300},
+		settings.gslb.Status.Strategies["failover.cloud.example.com"])
+}
+
+func TestValidSpecIsReportedInCondition(t *testing.T) {
+	// arrange
+	settings := provideSettings(t, predefinedConfig)
+	// act
+	reconcileAndUpdateGslb(t, settings)
+	condition := meta.FindStatusCondition(settings.gslb.Status.Conditions, specValidCondition)
+	// assert
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "Valid", condition.Reason)
+}
+
+func TestSpecBreakingPolicyIsReportedInCondition(t *testing.T) {
+	// arrange
+	settings := provideSettings(t, predefinedConfig)
+	dnsEndpoint := &externaldns.DNSEndpoint{}
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, dnsEndpoint))
+	policy := &k8gbv1beta1.GslbPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: "failover-only"},
+		Spec:       k8gbv1beta1.GslbPolicySpec{AllowedStrategies: []string{failoverStrategy}},
+	}
+	require.NoError(t, settings.client.Create(context.TODO(), policy))
+	defer func() { require.NoError(t, settings.client.Delete(context.TODO(), policy)) }()
+	// act
+	res, err := settings.reconciler.Reconcile(context.TODO(), settings.request)
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, settings.gslb))
+	condition := meta.FindStatusCondition(settings.gslb.Status.Conditions, specValidCondition)
+	unchanged := &externaldns.DNSEndpoint{}
+	require.NoError(t, settings.client.Get(context.TODO(), settings.request.NamespacedName, unchanged))
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, reconcile.Result{}, res)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionFalse, condition.Status)
+	assert.Equal(t, "InvalidSpec", condition.Reason)
+	assert.Equal(t, "GslbPolicy failover-only: strategy 'roundRobin' is not allowed", condition.Message)
+	assert.Equal(t, dnsEndpoint.ResourceVersion, unchanged.ResourceVersion)
+}
+
 func TestReflectGeoTagInStatusAsUnsetByDefault(t *testing.T) {
 	// arrange
 	want := "us-west-1"
@@ -731,10 +1068,10 @@ func TestCreatesNSDNSRecordsForRoute53(t *testing.T) {
 	settings.reconciler.Config = &customConfig
 	// If config is changed, new Route53 provider needs to be re-created. There is no way and reason to change provider
 	// configuration at another time than startup
-	f, _ := dns.NewDNSProviderFactory(settings.reconciler.Client, customConfig)
+	f, _ := dns.NewDNSProviderFactory(settings.reconciler.Client, customConfig, settings.reconciler.Metrics)
 	settings.reconciler.DNSProvider = f.Provider()
 
-	reconcileAndUpdateGslb(t, settings)
+	reconcileZoneDelegation(t, settings)
 	err = settings.client.Get(context.TODO(), client.ObjectKey{Namespace: predefinedConfig.K8gbNamespace, Name: "k8gb-ns-route53"}, dnsEndpointRoute53)
 	require.NoError(t, err, "Failed to get expected DNSEndpoint")
 	got := dnsEndpointRoute53.Annotations["k8gb.absa.oss/dnstype"]
@@ -802,10 +1139,10 @@ func TestCreatesNSDNSRecordsForNS1(t *testing.T) {
 	settings.reconciler.Config = &customConfig
 	// If config is changed, new Route53 provider needs to be re-created. There is no way and reason to change provider
 	// configuration at another time than startup
-	f, _ := dns.NewDNSProviderFactory(settings.reconciler.Client, customConfig)
+	f, _ := dns.NewDNSProviderFactory(settings.reconciler.Client, customConfig, settings.reconciler.Metrics)
 	settings.reconciler.DNSProvider = f.Provider()
 
-	reconcileAndUpdateGslb(t, settings)
+	reconcileZoneDelegation(t, settings)
 	err = settings.client.Get(context.TODO(), client.ObjectKey{Namespace: predefinedConfig.K8gbNamespace, Name: "k8gb-ns-ns1"}, dnsEndpointNS1)
 	require.NoError(t, err, "Failed to get expected DNSEndpoint")
 	got := dnsEndpointNS1.Annotations["k8gb.absa.oss/dnstype"]
@@ -885,7 +1222,13 @@ func TestRoute53ZoneDelegationGarbageCollection(t *testing.T) {
 	customConfig.EdgeDNSType = depresolver.DNSTypeRoute53
 	// apply new environment variables and update config only
 	settings.reconciler.Config = &customConfig
+	f, _ := dns.NewDNSProviderFactory(settings.reconciler.Client, customConfig, settings.reconciler.Metrics)
+	settings.reconciler.DNSProvider = f.Provider()
 	reconcileAndUpdateGslb(t, settings)
+	reconcileZoneDelegation(t, settings)
+	err = settings.client.Get(context.TODO(), client.ObjectKey{Namespace: predefinedConfig.K8gbNamespace, Name: "k8gb-ns-route53"},
+		&externaldns.DNSEndpoint{})
+	require.NoError(t, err, "k8gb-ns-route53 DNSEndpoint should be created")
 
 	deletionTimestamp := metav1.Now()
 	settings.gslb.SetDeletionTimestamp(&deletionTimestamp)
@@ -900,6 +1243,30 @@ func TestRoute53ZoneDelegationGarbageCollection(t *testing.T) {
 	require.Error(t, err, "k8gb-ns-route53 DNSEndpoint should be garbage collected")
 }
 
+func TestPartialEdgeDNSFailureIsSurfacedInStatus(t *testing.T) {
+	// arrange
+	settings := provideSettings(t, predefinedConfig)
+	working := settings.reconciler.DNSProvider
+	settings.reconciler.DNSProvider = dns.NewMultiProvider(working, failingProvider{working})
+
+	// act
+	reconcileZoneDelegation(t, settings)
+	failed := &k8gbv1beta1.Gslb{}
+	err := settings.client.Get(context.TODO(), settings.request.NamespacedName, failed)
+	require.NoError(t, err, "Failed to get expected gslb")
+	settings.reconciler.DNSProvider = dns.NewMultiProvider(working)
+	reconcileZoneDelegation(t, settings)
+	reconcileAndUpdateGslb(t, settings)
+	recovered := &k8gbv1beta1.Gslb{}
+	err = settings.client.Get(context.TODO(), settings.request.NamespacedName, recovered)
+	require.NoError(t, err, "Failed to get expected gslb")
+
+	// assert
+	assert.Equal(t, map[string]string{"FAILING": "connection refused"}, failed.Status.EdgeDNSErrors)
+	assert.Empty(t, recovered.Status.EdgeDNSErrors)
+	assert.Equal(t, predefinedConfig.ClusterGeoTag, recovered.Status.GeoTag)
+}
+
 func TestGslbSetsAnnotationsOnTheIngress(t *testing.T) {
 	// arrange
 	settings := provideSettings(t, predefinedConfig)
@@ -912,7 +1279,7 @@ func TestGslbSetsAnnotationsOnTheIngress(t *testing.T) {
 	err := settings.client.Get(context.Background(), client.ObjectKey{Namespace: settings.gslb.Namespace, Name: settings.gslb.Name}, ingress)
 	require.NoError(t, err, "Gslb should be created from annotated Ingress")
 
-	assert.Equal(t, map[string]string{strategyAnnotation: "roundRobin"}, ingress.Annotations)
+	assert.Equal(t, map[string]string{StrategyAnnotation: "roundRobin"}, ingress.Annotations)
 }
 
 func TestGslbGetFinalizer(t *testing.T) {
@@ -968,6 +1335,52 @@ func TestGslbRemoveBothFinalizers(t *testing.T) {
 	assert.Len(t, gslb.Finalizers, 0)
 }
 
+func TestGslbIsIndexedByAllBackendServices(t *testing.T) {
+	// arrange
+	gslb := &k8gbv1beta1.Gslb{Spec: k8gbv1beta1.GslbSpec{Ingress: k8gbv1beta1.IngressSpec{
+		Backend: &v1beta1.IngressBackend{ServiceName: "default-backend"},
+		Rules: []k8gbv1beta1.IngressRule{
+			{Host: "app.cloud.example.com", IngressRuleValue: k8gbv1beta1.IngressRuleValue{HTTP: &v1beta1.HTTPIngressRuleValue{
+				Paths: []v1beta1.HTTPIngressPath{
+					{Path: "/", Backend: v1beta1.IngressBackend{ServiceName: "frontend"}},
+					{Path: "/api", Backend: v1beta1.IngressBackend{ServiceName: "backend"}},
+				},
+			}}},
+			{Host: "www.cloud.example.com", IngressRuleValue: k8gbv1beta1.IngressRuleValue{HTTP: &v1beta1.HTTPIngressRuleValue{
+				Paths: []v1beta1.HTTPIngressPath{{Path: "/", Backend: v1beta1.IngressBackend{ServiceName: "frontend"}}},
+			}}},
+			{Host: "default.cloud.example.com"},
+		},
+	}}}
+	// act
+	services := gslbBackendServices(gslb)
+	// assert
+	assert.Equal(t, []string{"default-backend", "frontend", "backend"}, services)
+}
+
+func TestEndpointsAreMappedToEveryReferencingGslb(t *testing.T) {
+	// arrange
+	s := runtime.NewScheme()
+	require.NoError(t, k8gbv1beta1.AddToScheme(s))
+	gslb := func(name string) *k8gbv1beta1.Gslb {
+		return &k8gbv1beta1.Gslb{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "test-gslb", Name: name},
+			Spec: k8gbv1beta1.GslbSpec{Ingress: k8gbv1beta1.IngressSpec{
+				Backend: &v1beta1.IngressBackend{ServiceName: "frontend"},
+			}},
+		}
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(gslb("app1"), gslb("app2")).Build()
+	endpoints := &corev1.Endpoints{ObjectMeta: metav1.ObjectMeta{Namespace: "test-gslb", Name: "frontend"}}
+	// act
+	requests := gslbsForEndpoints(c)(endpoints)
+	// assert
+	assert.ElementsMatch(t, []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: "test-gslb", Name: "app1"}},
+		{NamespacedName: types.NamespacedName{Namespace: "test-gslb", Name: "app2"}},
+	}, requests)
+}
+
 func createHealthyService(t *testing.T, s *testSettings, serviceName string) {
 	t.Helper()
 	service := &corev1.Service{
@@ -1123,16 +1536,53 @@ func reconcileAndUpdateGslb(t *testing.T, s testSettings) {
 		return
 	}
 
-	if !s.finalCall {
-		if res != (reconcile.Result{RequeueAfter: time.Second * 30}) {
-			t.Error("reconcile did not return Result with Requeue")
-		}
-	}
-
 	err = s.reconciler.Get(context.TODO(), s.request.NamespacedName, s.gslb)
 	if err != nil {
 		t.Fatalf("Failed to get expected gslb: (%v)", err)
 	}
+
+	if !s.finalCall {
+		// reconciliation is driven by watches, Gslb is not requeued periodically
+		// except for draining Gslb, which is requeued when drain period ends
+		expected := reconcile.Result{RequeueAfter: res.RequeueAfter}
+		if s.gslb.Status.DrainingSince == nil {
+			expected = reconcile.Result{}
+		}
+		if res != expected {
+			t.Errorf("reconcile returned unexpected Result %v", res)
+		}
+	}
+}
+
+// reconcileZoneDelegation runs zone delegation reconciler with config and provider of the Gslb reconciler,
+// which are replaced by some tests
+func reconcileZoneDelegation(t *testing.T, s testSettings) {
+	t.Helper()
+	s.reconciler.ZoneDelegation.Config = s.reconciler.Config
+	s.reconciler.ZoneDelegation.DNSProvider = s.reconciler.DNSProvider
+	_, err := s.reconciler.ZoneDelegation.Reconcile(context.TODO(), zoneDelegationRequest)
+	require.NoError(t, err, "Failed to reconcile zone delegation")
+}
+
+// gslbRuleSchema returns validation schema of Gslb ingress rules from the CRD of the chart
+func gslbRuleSchema(t *testing.T) *jsonSchema {
+	t.Helper()
+	data, err := ioutil.ReadFile(gslbCRDYaml)
+	require.NoError(t, err)
+	crd := struct {
+		Spec struct {
+			Versions []struct {
+				Schema struct {
+					OpenAPIV3Schema jsonSchema `json:"openAPIV3Schema"`
+				} `json:"schema"`
+			} `json:"versions"`
+		} `json:"spec"`
+	}{}
+	require.NoError(t, yaml.Unmarshal(data, &crd))
+	require.NotEmpty(t, crd.Spec.Versions)
+	rule := crd.Spec.Versions[0].Schema.OpenAPIV3Schema.Properties["spec"].Properties["ingress"].Properties["rules"].Items
+	require.NotNil(t, rule)
+	return rule
 }
 
 func provideSettings(t *testing.T, expected depresolver.Config) (settings testSettings) {
@@ -1154,7 +1604,7 @@ func provideSettings(t *testing.T, expected depresolver.Config) (settings testSe
 	}
 	// Register operator types with the runtime scheme.
 	s := scheme.Scheme
-	s.AddKnownTypes(k8gbv1beta1.GroupVersion, gslb)
+	s.AddKnownTypes(k8gbv1beta1.GroupVersion, gslb, &k8gbv1beta1.GslbList{}, &k8gbv1beta1.GslbPolicy{}, &k8gbv1beta1.GslbPolicyList{})
 	// Register external-dns DNSEndpoint CRD
 	s.AddKnownTypes(schema.GroupVersion{Group: "externaldns.k8s.io", Version: "v1alpha1"}, &externaldns.DNSEndpoint{})
 	// Create a fake client to mock API calls.
@@ -1177,12 +1627,20 @@ func provideSettings(t *testing.T, expected depresolver.Config) (settings testSe
 	}
 
 	var f *dns.ProviderFactory
-	f, err = dns.NewDNSProviderFactory(r.Client, *r.Config)
+	f, err = dns.NewDNSProviderFactory(r.Client, *r.Config, r.Metrics)
 	if err != nil {
 		t.Fatalf("reconcile: (%v)", err)
 	}
 	r.DNSProvider = f.Provider()
-	a := assistant.NewGslbAssistant(r.Client, r.Config.K8gbNamespace, r.Config.EdgeDNSServer, r.Config.EdgeDNSServerPort)
+	a := assistant.NewGslbAssistant(r.Client, r.Config.K8gbNamespace, r.Config.EdgeDNSServer, r.Config.EdgeDNSServerPort, r.Metrics)
+	r.ZoneDelegation = &ZoneDelegationReconciler{
+		Client:      cl,
+		Config:      r.Config,
+		DepResolver: r.DepResolver,
+		DNSProvider: r.DNSProvider,
+		Assistant:   a,
+		Metrics:     r.Metrics,
+	}
 	res, err := r.Reconcile(context.TODO(), req)
 	if err != nil {
 		t.Fatalf("reconcile: (%v)", err)
@@ -1214,6 +1672,19 @@ func provideSettings(t *testing.T, expected depresolver.Config) (settings testSe
 	return settings
 }
 
+// failingProvider fails zone delegation, the rest of calls is handled by embedded provider
+type failingProvider struct {
+	dns.Provider
+}
+
+func (p failingProvider) CreateZoneDelegationForExternalDNS(*dns.ZoneDelegation) error {
+	return fmt.Errorf("connection refused")
+}
+
+func (p failingProvider) String() string {
+	return "FAILING"
+}
+
 func oldEdgeTimestamp(threshold string) string {
 	now := time.Now()
 	duration, _ := time.ParseDuration(threshold)
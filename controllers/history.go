@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"fmt"
+	"sort"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/logging"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Reasons of target changes besides service health of the host, e.g. Unhealthy or NotFound
+const (
+	drainedReason            = "Drained"
+	healthyReason            = "Healthy"
+	primaryServingReason     = "PrimaryServing"
+	primaryUnavailableReason = "PrimaryUnavailable"
+	localTargetsReason       = "LocalTargetsChanged"
+	peerTargetsReason        = "PeerTargetsChanged"
+)
+
+// hostTargets are inputs and outcome of the target decision for a single host
+type hostTargets struct {
+	strategy k8gbv1beta1.Strategy
+	health   string
+	drained  bool
+	local    []string
+	external []string
+	final    []string
+}
+
+// targetChange returns change of the host targets against the targets observed in status, if there is any
+func (r *GslbReconciler) targetChange(gslb *k8gbv1beta1.Gslb, host string, t hostTargets) (k8gbv1beta1.TargetChange, bool) {
+	oldTargets := sortTargets(append([]string{}, gslb.Status.HealthyRecords[host]...))
+	newTargets := sortTargets(append([]string{}, t.final...))
+	if equality.Semantic.DeepEqual(oldTargets, newTargets) ||
+		len(oldTargets) == 0 && len(newTargets) == 0 {
+		return k8gbv1beta1.TargetChange{}, false
+	}
+	return k8gbv1beta1.TargetChange{
+		Time:       metav1.Now(),
+		OldTargets: oldTargets,
+		NewTargets: newTargets,
+		Reason:     r.targetChangeReason(t, oldTargets),
+		Strategy:   t.strategy.Type,
+	}, true
+}
+
+// targetChangeReason explains why the host got new targets
+func (r *GslbReconciler) targetChangeReason(t hostTargets, oldTargets []string) string {
+	if t.strategy.Type == failoverStrategy && t.strategy.PrimaryGeoTag != r.Config.ClusterGeoTag {
+		if len(t.external) > 0 {
+			return primaryServingReason
+		}
+		if t.health == "Healthy" && !t.drained {
+			return primaryUnavailableReason
+		}
+	}
+	switch {
+	case t.drained:
+		return drainedReason
+	case t.health != "Healthy":
+		return t.health
+	case !containsAny(oldTargets, t.local):
+		return healthyReason
+	case equality.Semantic.DeepEqual(without(oldTargets, t.local), without(t.final, t.local)):
+		return localTargetsReason
+	}
+	return peerTargetsReason
+}
+
+// recordTargetChanges appends changes to bounded history of the hosts in status and writes them to audit log.
+// History of hosts which are not in the spec anymore is dropped
+func (r *GslbReconciler) recordTargetChanges(gslb *k8gbv1beta1.Gslb, changes map[string]k8gbv1beta1.TargetChange) {
+	hosts := make([]string, 0, len(changes))
+	for host := range changes {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+	for _, host := range hosts {
+		change := changes[host]
+		log.Info().Msgf("Targets of host %s of %s Gslb changed from %v to %v (%s)",
+			host, gslb.Name, change.OldTargets, change.NewTargets, change.Reason)
+		logging.Audit().Log().
+			Str("gslb", fmt.Sprintf("%s/%s", gslb.Namespace, gslb.Name)).
+			Str("host", host).
+			Strs("oldTargets", change.OldTargets).
+			Strs("newTargets", change.NewTargets).
+			Str("reason", change.Reason).
+			Str("strategy", change.Strategy).
+			Str("geoTag", r.Config.ClusterGeoTag).
+			Msg("DNS targets changed")
+	}
+
+	size := r.Config.Audit.HistorySize
+	if size == 0 {
+		gslb.Status.TargetHistory = nil
+		return
+	}
+	for _, host := range hosts {
+		if gslb.Status.TargetHistory == nil {
+			gslb.Status.TargetHistory = make(map[string][]k8gbv1beta1.TargetChange)
+		}
+		history := append(gslb.Status.TargetHistory[host], changes[host])
+		if len(history) > size {
+			history = history[len(history)-size:]
+		}
+		gslb.Status.TargetHistory[host] = history
+	}
+	for host := range gslb.Status.TargetHistory {
+		if !hasRule(gslb, host) {
+			delete(gslb.Status.TargetHistory, host)
+		}
+	}
+}
+
+func hasRule(gslb *k8gbv1beta1.Gslb, host string) bool {
+	for _, rule := range gslb.Spec.Ingress.Rules {
+		if rule.Host == host {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAny(list, values []string) bool {
+	for _, v := range values {
+		if contains(list, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// without returns sorted targets which are not in excluded
+func without(targets, excluded []string) []string {
+	var rest []string
+	for _, t := range targets {
+		if !contains(excluded, t) {
+			rest = append(rest, t)
+		}
+	}
+	return sortTargets(rest)
+}
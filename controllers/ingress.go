@@ -32,9 +32,9 @@ import (
 )
 
 func (r *GslbReconciler) gslbIngress(gslb *k8gbv1beta1.Gslb) (*v1beta1.Ingress, error) {
-	metav1.SetMetaDataAnnotation(&gslb.ObjectMeta, strategyAnnotation, gslb.Spec.Strategy.Type)
+	metav1.SetMetaDataAnnotation(&gslb.ObjectMeta, StrategyAnnotation, gslb.Spec.Strategy.Type)
 	if gslb.Spec.Strategy.PrimaryGeoTag != "" {
-		metav1.SetMetaDataAnnotation(&gslb.ObjectMeta, primaryGeoTagAnnotation, gslb.Spec.Strategy.PrimaryGeoTag)
+		metav1.SetMetaDataAnnotation(&gslb.ObjectMeta, PrimaryGeoTagAnnotation, gslb.Spec.Strategy.PrimaryGeoTag)
 	}
 	ingress := &v1beta1.Ingress{
 		ObjectMeta: metav1.ObjectMeta{
@@ -78,6 +78,11 @@ func (r *GslbReconciler) saveIngress(instance *k8gbv1beta1.Gslb, i *v1beta1.Ingr
 		return err
 	}
 
+	if metav1.IsControlledBy(instance, found) {
+		// Gslb was created from annotations of the Ingress, which is kept in sync by IngressReconciler
+		return nil
+	}
+
 	// Update existing object with new spec and annotations
 	if !ingressEqual(found, i) {
 		found.Spec = i.Spec
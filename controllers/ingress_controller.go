@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"strconv"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/utils"
+
+	"k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// IngressReconciler keeps Gslbs created from k8gb.io/strategy annotation of Ingress in sync with the Ingress.
+// Spec of the Ingress and k8gb.io annotations are copied to the Gslb of the same name, which is controlled
+// by the Ingress; removal of the strategy annotation deletes the Gslb. Gslbs not created from annotations and
+// Ingresses created by Gslbs are left alone.
+type IngressReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+	Config *depresolver.Config
+}
+
+// gslbAnnotations are annotations of Ingress which are synced to the Gslb
+var gslbAnnotations = []string{StrategyAnnotation, PrimaryGeoTagAnnotation, dnsTTLSecondsAnnotation, splitBrainThresholdSecondsAnnotation}
+
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch
+
+// Reconcile creates, updates or deletes Gslb according to annotations of the Ingress
+func (r *IngressReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	result := utils.NewReconcileResultHandler(r.Config.ReconcileRequeueSeconds)
+	ingress := &v1beta1.Ingress{}
+	err := r.Get(ctx, req.NamespacedName, ingress)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// Gslb controlled by the Ingress is garbage collected
+			return result.Stop()
+		}
+		return result.RequeueError(err)
+	}
+	if metav1.GetControllerOf(ingress) != nil || ingress.GetDeletionTimestamp() != nil {
+		// Ingress created by Gslb or being deleted
+		return result.Stop()
+	}
+
+	gslb := &k8gbv1beta1.Gslb{}
+	err = r.Get(ctx, req.NamespacedName, gslb)
+	if err != nil && !errors.IsNotFound(err) {
+		return result.RequeueError(err)
+	}
+	exists := err == nil
+	if exists && !metav1.IsControlledBy(gslb, ingress) {
+		log.Debug().Msgf("Gslb(%s) is not managed by Ingress annotations, skipping...", gslb.Name)
+		return result.Stop()
+	}
+
+	strategy, annotated := ingress.Annotations[StrategyAnnotation]
+	if !annotated {
+		if exists {
+			log.Info().Msgf("Strategy annotation removed from Ingress(%s), deleting Gslb", ingress.Name)
+			err = r.Delete(ctx, gslb)
+			if err != nil && !errors.IsNotFound(err) {
+				return result.RequeueError(err)
+			}
+		}
+		return result.Stop()
+	}
+	if strategy != roundRobinStrategy && strategy != failoverStrategy {
+		log.Info().Msgf("Unsupported strategy annotation(%s:%s) on Ingress(%s), skipping...",
+			StrategyAnnotation, strategy, ingress.Name)
+		return result.Stop()
+	}
+	if strategy == failoverStrategy && ingress.Annotations[PrimaryGeoTagAnnotation] == "" {
+		log.Info().Msgf("%s annotation is missing on Ingress(%s), skipping...", PrimaryGeoTagAnnotation, ingress.Name)
+		return result.Stop()
+	}
+
+	if !exists {
+		gslb = &k8gbv1beta1.Gslb{ObjectMeta: metav1.ObjectMeta{
+			Namespace: ingress.Namespace,
+			Name:      ingress.Name,
+		}}
+		gslbFromIngress(gslb, ingress)
+		err = controllerutil.SetControllerReference(ingress, gslb, r.Scheme)
+		if err != nil {
+			return result.RequeueError(err)
+		}
+		log.Info().Msgf("Creating new Gslb(%s) out of Ingress annotation", gslb.Name)
+		err = r.Create(ctx, gslb)
+		if err != nil {
+			return result.RequeueError(err)
+		}
+		return result.Stop()
+	}
+
+	desired := gslb.DeepCopy()
+	gslbFromIngress(desired, ingress)
+	if equality.Semantic.DeepEqual(gslb.Spec, desired.Spec) && equality.Semantic.DeepEqual(gslb.Annotations, desired.Annotations) {
+		return result.Stop()
+	}
+	log.Info().Msgf("Updating Gslb(%s) out of Ingress annotation", gslb.Name)
+	err = r.Update(ctx, desired)
+	if errors.IsConflict(err) {
+		return result.Requeue()
+	}
+	if err != nil {
+		return result.RequeueError(err)
+	}
+	return result.Stop()
+}
+
+// gslbFromIngress sets spec and k8gb.io annotations of Gslb from the Ingress. TTL and split brain threshold
+// without annotation are reset, so defaults of GslbPolicy or the operator apply; type and primary geo tag
+// without annotation keep their current values.
+func gslbFromIngress(gslb *k8gbv1beta1.Gslb, ingress *v1beta1.Ingress) {
+	gslb.Spec.Ingress = k8gbv1beta1.FromV1Beta1IngressSpec(ingress.Spec)
+	if gslb.Annotations == nil {
+		gslb.Annotations = make(map[string]string)
+	}
+	for _, key := range gslbAnnotations {
+		value, found := ingress.Annotations[key]
+		if !found {
+			delete(gslb.Annotations, key)
+			switch key {
+			case dnsTTLSecondsAnnotation:
+				gslb.Spec.Strategy.DNSTtlSeconds = 0
+			case splitBrainThresholdSecondsAnnotation:
+				gslb.Spec.Strategy.SplitBrainThresholdSeconds = 0
+			}
+			continue
+		}
+		gslb.Annotations[key] = value
+		switch key {
+		case StrategyAnnotation:
+			gslb.Spec.Strategy.Type = value
+		case PrimaryGeoTagAnnotation:
+			gslb.Spec.Strategy.PrimaryGeoTag = value
+		case dnsTTLSecondsAnnotation:
+			gslb.Spec.Strategy.DNSTtlSeconds = annotationToInt(key, value)
+		case splitBrainThresholdSecondsAnnotation:
+			gslb.Spec.Strategy.SplitBrainThresholdSeconds = annotationToInt(key, value)
+		}
+	}
+}
+
+func annotationToInt(key, value string) int {
+	intValue, err := strconv.Atoi(value)
+	if err != nil {
+		log.Err(err).Msgf("can't convert annotation %s to int (%s)", key, err)
+	}
+	return intValue
+}
+
+// SetupWithManager configures controller manager
+func (r *IngressReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	// status of Ingress doesn't affect the Gslb; changes of owned Gslb spec are reverted to the annotations
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&v1beta1.Ingress{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Owns(&k8gbv1beta1.Gslb{}, builder.WithPredicates(
+			predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))).
+		Complete(r)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/api/networking/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+var ingressRequest = reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "test-gslb", Name: "app"}}
+
+func newIngressReconciler(t *testing.T, objs ...runtime.Object) *IngressReconciler {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, k8gbv1beta1.AddToScheme(s))
+	config := predefinedConfig
+	return &IngressReconciler{
+		Client: fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).Build(),
+		Scheme: s,
+		Config: &config,
+	}
+}
+
+func annotatedIngress(annotations map[string]string) *v1beta1.Ingress {
+	return &v1beta1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-gslb", Name: "app", UID: "ingress-uid", Annotations: annotations},
+		Spec: v1beta1.IngressSpec{Rules: []v1beta1.IngressRule{{
+			Host: "app.cloud.example.com",
+			IngressRuleValue: v1beta1.IngressRuleValue{HTTP: &v1beta1.HTTPIngressRuleValue{
+				Paths: []v1beta1.HTTPIngressPath{{Path: "/", Backend: v1beta1.IngressBackend{ServiceName: "frontend"}}},
+			}},
+		}}},
+	}
+}
+
+func reconcileIngress(t *testing.T, r *IngressReconciler) {
+	t.Helper()
+	_, err := r.Reconcile(context.TODO(), ingressRequest)
+	require.NoError(t, err)
+}
+
+func updateIngressAnnotations(t *testing.T, r *IngressReconciler, annotations map[string]string) {
+	t.Helper()
+	ingress := &v1beta1.Ingress{}
+	require.NoError(t, r.Get(context.TODO(), ingressRequest.NamespacedName, ingress))
+	ingress.Annotations = annotations
+	require.NoError(t, r.Update(context.TODO(), ingress))
+}
+
+func TestIngressAnnotationCreatesGslb(t *testing.T) {
+	// arrange
+	r := newIngressReconciler(t, annotatedIngress(map[string]string{
+		StrategyAnnotation:      roundRobinStrategy,
+		dnsTTLSecondsAnnotation: "60",
+		"other":                 "annotation",
+	}))
+	gslb := &k8gbv1beta1.Gslb{}
+	// act
+	reconcileIngress(t, r)
+	// assert
+	require.NoError(t, r.Get(context.TODO(), ingressRequest.NamespacedName, gslb))
+	assert.Equal(t, roundRobinStrategy, gslb.Spec.Strategy.Type)
+	assert.Equal(t, 60, gslb.Spec.Strategy.DNSTtlSeconds)
+	assert.Equal(t, "app.cloud.example.com", gslb.Spec.Ingress.Rules[0].Host)
+	assert.Equal(t, map[string]string{StrategyAnnotation: roundRobinStrategy, dnsTTLSecondsAnnotation: "60"}, gslb.Annotations)
+	require.NotNil(t, metav1.GetControllerOf(gslb))
+	assert.Equal(t, types.UID("ingress-uid"), metav1.GetControllerOf(gslb).UID)
+}
+
+func TestIngressAnnotationChangeUpdatesGslb(t *testing.T) {
+	// arrange
+	r := newIngressReconciler(t, annotatedIngress(map[string]string{
+		StrategyAnnotation:                   roundRobinStrategy,
+		dnsTTLSecondsAnnotation:              "60",
+		splitBrainThresholdSecondsAnnotation: "600",
+	}))
+	reconcileIngress(t, r)
+	updateIngressAnnotations(t, r, map[string]string{
+		StrategyAnnotation:      failoverStrategy,
+		PrimaryGeoTagAnnotation: "eu",
+	})
+	gslb := &k8gbv1beta1.Gslb{}
+	// act
+	reconcileIngress(t, r)
+	// assert
+	require.NoError(t, r.Get(context.TODO(), ingressRequest.NamespacedName, gslb))
+	assert.Equal(t, failoverStrategy, gslb.Spec.Strategy.Type)
+	assert.Equal(t, "eu", gslb.Spec.Strategy.PrimaryGeoTag)
+	// removed annotations fall back to defaults
+	assert.Zero(t, gslb.Spec.Strategy.DNSTtlSeconds)
+	assert.Zero(t, gslb.Spec.Strategy.SplitBrainThresholdSeconds)
+	assert.Equal(t, map[string]string{StrategyAnnotation: failoverStrategy, PrimaryGeoTagAnnotation: "eu"}, gslb.Annotations)
+}
+
+func TestIngressAnnotationRemovalDeletesGslb(t *testing.T) {
+	// arrange
+	r := newIngressReconciler(t, annotatedIngress(map[string]string{StrategyAnnotation: roundRobinStrategy}))
+	reconcileIngress(t, r)
+	updateIngressAnnotations(t, r, nil)
+	// act
+	reconcileIngress(t, r)
+	// assert
+	err := r.Get(context.TODO(), ingressRequest.NamespacedName, &k8gbv1beta1.Gslb{})
+	assert.True(t, errors.IsNotFound(err), "Gslb should be deleted, got %v", err)
+}
+
+func TestIngressAnnotationsDontTouchGslbNotCreatedFromThem(t *testing.T) {
+	// arrange
+	gslb := &k8gbv1beta1.Gslb{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-gslb", Name: "app", UID: "gslb-uid"},
+		Spec:       k8gbv1beta1.GslbSpec{Strategy: k8gbv1beta1.Strategy{Type: roundRobinStrategy, DNSTtlSeconds: 30}},
+	}
+	r := newIngressReconciler(t, gslb, annotatedIngress(map[string]string{
+		StrategyAnnotation:      roundRobinStrategy,
+		dnsTTLSecondsAnnotation: "60",
+	}))
+	got := &k8gbv1beta1.Gslb{}
+	// act
+	reconcileIngress(t, r)
+	updateIngressAnnotations(t, r, nil)
+	reconcileIngress(t, r)
+	// assert
+	require.NoError(t, r.Get(context.TODO(), ingressRequest.NamespacedName, got))
+	assert.Equal(t, 30, got.Spec.Strategy.DNSTtlSeconds)
+}
+
+func TestFailoverAnnotationWithoutPrimaryGeoTagIsIgnored(t *testing.T) {
+	// arrange
+	r := newIngressReconciler(t, annotatedIngress(map[string]string{StrategyAnnotation: failoverStrategy}))
+	// act
+	reconcileIngress(t, r)
+	// assert
+	err := r.Get(context.TODO(), ingressRequest.NamespacedName, &k8gbv1beta1.Gslb{})
+	assert.True(t, errors.IsNotFound(err), "Gslb should not be created, got %v", err)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+// Package heartbeat writes and reads payload of heartbeat TXT records, which clusters use to tell each other
+// they are alive.
+//
+// Payload is a single string of key=value fields separated by semicolons, e.g.
+//
+//	v=1;ts=2021-06-10T22:14:03Z;geo=eu;ver=v0.8.0;health=2/3;stale=za;sig=Y2mD...
+//
+// Unknown fields are ignored, so fields can be added without breaking older readers. Payload written before versioning
+// is a bare timestamp in UTC, e.g. 2021-06-10T22:14:03, which is still accepted.
+package heartbeat
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Version of payload written by Payload
+const Version = 1
+
+const (
+	legacyTimestampLayout = "2006-01-02T15:04:05"
+	fieldSeparator        = ";"
+	// signatureSeparator separates signature from signed content; payload has no spaces, so it stays a single TXT string
+	signatureSeparator = fieldSeparator + "sig="
+	versionField       = "v"
+	timestampField     = "ts"
+	geoTagField        = "geo"
+	operatorField      = "ver"
+	healthField        = "health"
+	staleField         = "stale"
+	listSeparator      = ","
+)
+
+// ErrInvalidSignature is returned when signature of heartbeat is missing or doesn't match
+var ErrInvalidSignature = errors.New("invalid heartbeat signature")
+
+// Heartbeat is content of heartbeat TXT record
+type Heartbeat struct {
+	// Version of the payload, 0 for bare timestamp written before versioning
+	Version int
+	// Timestamp when the heartbeat was written
+	Timestamp time.Time
+	// GeoTag of the cluster which wrote the heartbeat
+	GeoTag string
+	// OperatorVersion of k8gb which wrote the heartbeat
+	OperatorVersion string
+	// Health summary of the Gslb, e.g. 2/3 when two of three hosts are healthy; optional
+	Health string
+	// Stale are geo tags of clusters whose heartbeats are stale in view of the cluster which wrote the heartbeat
+	Stale []string
+}
+
+// Payload returns content of heartbeat TXT record fqdn. It is signed by key, unless the key is empty. Empty fields
+// are omitted
+func Payload(fqdn string, h Heartbeat, key string) string {
+	fields := []string{field(versionField, strconv.Itoa(Version)), field(timestampField, h.Timestamp.UTC().Format(time.RFC3339))}
+	for _, f := range []struct{ key, value string }{
+		{geoTagField, h.GeoTag}, {operatorField, h.OperatorVersion}, {healthField, h.Health},
+		{staleField, strings.Join(h.Stale, listSeparator)},
+	} {
+		if f.value != "" {
+			fields = append(fields, field(f.key, f.value))
+		}
+	}
+	payload := strings.Join(fields, fieldSeparator)
+	if key == "" {
+		return payload
+	}
+	return payload + signatureSeparator + sign(fqdn, payload, key)
+}
+
+// Parse reads heartbeat payload of TXT record fqdn. The signature is verified when key isn't empty, otherwise it is
+// ignored, so clusters can start signing one by one
+func Parse(fqdn, payload, key string) (Heartbeat, error) {
+	payload = strings.TrimSpace(payload)
+	content, signature := payload, ""
+	if i := strings.Index(payload, signatureSeparator); i >= 0 {
+		content, signature = payload[:i], payload[i+len(signatureSeparator):]
+	}
+	if key != "" && !hmac.Equal([]byte(signature), []byte(sign(fqdn, content, key))) {
+		return Heartbeat{}, fmt.Errorf("%w of %s", ErrInvalidSignature, fqdn)
+	}
+	if !strings.Contains(content, "=") {
+		t, err := parseTimestamp(content)
+		return Heartbeat{Timestamp: t}, err
+	}
+	var h Heartbeat
+	var err error
+	timestamp := ""
+	for _, f := range strings.Split(content, fieldSeparator) {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		value := strings.TrimSpace(kv[1])
+		switch strings.ToLower(strings.TrimSpace(kv[0])) {
+		case versionField:
+			if h.Version, err = strconv.Atoi(value); err != nil {
+				return Heartbeat{}, fmt.Errorf("invalid heartbeat version %q of %s", value, fqdn)
+			}
+		case timestampField:
+			timestamp = value
+		case geoTagField:
+			h.GeoTag = value
+		case operatorField:
+			h.OperatorVersion = value
+		case healthField:
+			h.Health = value
+		case staleField:
+			h.Stale = parseList(value)
+		}
+	}
+	if timestamp == "" {
+		return Heartbeat{}, fmt.Errorf("missing heartbeat timestamp of %s", fqdn)
+	}
+	h.Timestamp, err = parseTimestamp(timestamp)
+	return h, err
+}
+
+// parseTimestamp accepts RFC 3339 timestamps and timestamps without zone, which are in UTC
+func parseTimestamp(timestamp string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, timestamp); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse(legacyTimestampLayout, timestamp)
+}
+
+func parseList(value string) (items []string) {
+	for _, item := range strings.Split(value, listSeparator) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// field returns key=value; separators in value are dropped, so the value can't inject other fields
+func field(key, value string) string {
+	value = strings.NewReplacer(fieldSeparator, "", " ", "", "\"", "").Replace(value)
+	return key + "=" + value
+}
+
+// sign binds content to the record, so heartbeat of one cluster can't be replayed as heartbeat of another
+func sign(fqdn, content, key string) string {
+	mac := hmac.New(sha256.New, []byte(key))
+	_, _ = mac.Write([]byte(strings.ToLower(strings.TrimSuffix(fqdn, ".")) + "\n" + content))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package heartbeat
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const fqdn = "test-gslb-heartbeat-us.example.com"
+
+var written = Heartbeat{
+	Version:         Version,
+	Timestamp:       time.Date(2021, 6, 10, 22, 14, 3, 0, time.UTC),
+	GeoTag:          "us",
+	OperatorVersion: "v0.8.0",
+	Health:          "2/3",
+	Stale:           []string{"eu", "za"},
+}
+
+func TestUnsignedPayload(t *testing.T) {
+	// act
+	payload := Payload(fqdn, written, "")
+	parsed, err := Parse(fqdn, payload, "")
+	// assert
+	assert.Equal(t, "v=1;ts=2021-06-10T22:14:03Z;geo=us;ver=v0.8.0;health=2/3;stale=eu,za", payload)
+	assert.NoError(t, err)
+	assert.Equal(t, written, parsed)
+}
+
+func TestPayloadOmitsEmptyFields(t *testing.T) {
+	// arrange
+	local := time.Date(2021, 6, 11, 0, 14, 3, 0, time.FixedZone("CEST", 2*60*60))
+	// act
+	payload := Payload(fqdn, Heartbeat{Timestamp: local}, "")
+	parsed, err := Parse(fqdn, payload, "")
+	// assert
+	assert.Equal(t, "v=1;ts=2021-06-10T22:14:03Z", payload)
+	assert.NoError(t, err)
+	assert.Equal(t, Heartbeat{Version: Version, Timestamp: written.Timestamp}, parsed)
+}
+
+func TestSignedPayload(t *testing.T) {
+	// arrange
+	payload := Payload(fqdn, written, "secret")
+	// act
+	parsed, err := Parse(fqdn+".", payload, "secret")
+	unverified, unverifiedErr := Parse(fqdn, payload, "")
+	// assert
+	assert.True(t, strings.HasPrefix(payload, "v=1;ts=2021-06-10T22:14:03Z;geo=us;ver=v0.8.0;health=2/3;stale=eu,za;sig="))
+	assert.NotContains(t, payload, " ")
+	assert.NoError(t, err)
+	assert.Equal(t, written, parsed)
+	assert.NoError(t, unverifiedErr, "signature is ignored without key")
+	assert.Equal(t, written, unverified)
+}
+
+func TestInvalidSignature(t *testing.T) {
+	// arrange
+	signed := Payload(fqdn, written, "secret")
+	forged := strings.Replace(signed, "ts=2021-06-10T22:14:03Z", "ts=2021-06-10T23:14:03Z", 1)
+	for name, parse := range map[string]func() (Heartbeat, error){
+		"unsigned":         func() (Heartbeat, error) { return Parse(fqdn, Payload(fqdn, written, ""), "secret") },
+		"another key":      func() (Heartbeat, error) { return Parse(fqdn, Payload(fqdn, written, "another"), "secret") },
+		"another record":   func() (Heartbeat, error) { return Parse("test-gslb-heartbeat-eu.example.com", signed, "secret") },
+		"forged timestamp": func() (Heartbeat, error) { return Parse(fqdn, forged, "secret") },
+	} {
+		// act
+		_, err := parse()
+		// assert
+		assert.True(t, errors.Is(err, ErrInvalidSignature), name)
+	}
+}
+
+func TestLegacyPayload(t *testing.T) {
+	// arrange
+	legacy := Heartbeat{Timestamp: written.Timestamp}
+	for name, payload := range map[string]string{
+		"bare timestamp": "2021-06-10T22:14:03",
+		"rfc 3339":       "2021-06-11T00:14:03+02:00",
+		"whitespace":     " 2021-06-10T22:14:03\n",
+	} {
+		// act
+		parsed, err := Parse(fqdn, payload, "")
+		// assert
+		assert.NoError(t, err, name)
+		assert.Equal(t, legacy, parsed, name)
+	}
+}
+
+func TestSignedLegacyPayload(t *testing.T) {
+	// arrange
+	payload := "2021-06-10T22:14:03" + signatureSeparator + sign(fqdn, "2021-06-10T22:14:03", "secret")
+	// act
+	parsed, err := Parse(fqdn, payload, "secret")
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, written.Timestamp, parsed.Timestamp)
+}
+
+func TestTolerantParsing(t *testing.T) {
+	// arrange
+	payload := "V=2; ts=2021-06-10T22:14:03Z ;geo=us;zone=eu-west-1;broken;ver=v0.9.0;stale=eu, ,za"
+	// act
+	parsed, err := Parse(fqdn, payload, "")
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, Heartbeat{Version: 2, Timestamp: written.Timestamp, GeoTag: "us", OperatorVersion: "v0.9.0",
+		Stale: []string{"eu", "za"}}, parsed)
+}
+
+func TestInvalidPayload(t *testing.T) {
+	for name, payload := range map[string]string{
+		"empty":             "",
+		"missing timestamp": "v=1;geo=us",
+		"invalid timestamp": "v=1;ts=yesterday",
+		"invalid version":   "v=one;ts=2021-06-10T22:14:03Z",
+		"garbage":           "alive",
+	} {
+		// act
+		_, err := Parse(fqdn, payload, "")
+		// assert
+		assert.Error(t, err, name)
+	}
+}
+
+func TestSeparatorsAreDroppedFromValues(t *testing.T) {
+	// arrange
+	h := Heartbeat{Timestamp: written.Timestamp, GeoTag: "us;ts=2030-01-01T00:00:00Z", OperatorVersion: "v0.8.0 \"rc\""}
+	// act
+	parsed, err := Parse(fqdn, Payload(fqdn, h, ""), "")
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, written.Timestamp, parsed.Timestamp)
+	assert.Equal(t, "v0.8.0rc", parsed.OperatorVersion)
+}
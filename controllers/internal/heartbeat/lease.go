@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package heartbeat
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+// PayloadAnnotation holds payload of heartbeat stored in Lease
+const PayloadAnnotation = "k8gb.absa.oss/heartbeat"
+
+// Backend stores heartbeats outside of edge DNS
+type Backend interface {
+	// Write stores payload of heartbeat fqdn, which is considered expired after ttl
+	Write(ctx context.Context, fqdn, payload string, ttl time.Duration) error
+	// Read returns payloads of heartbeat fqdn, none when the heartbeat doesn't exist
+	Read(ctx context.Context, fqdn string) ([]string, error)
+	// Delete removes heartbeat fqdn; missing heartbeat isn't an error
+	Delete(ctx context.Context, fqdn string) error
+}
+
+// Lease stores heartbeats as coordination.k8s.io Leases in a cluster shared by all clusters. Lease is named by FQDN
+// of the heartbeat and holds its payload in PayloadAnnotation
+type Lease struct {
+	client    client.Client
+	namespace string
+	// holder is identity of the cluster renewing its Leases
+	holder string
+}
+
+// NewLease creates Lease backend storing Leases in namespace of cluster of client c; holder identifies this cluster
+func NewLease(c client.Client, namespace, holder string) *Lease {
+	return &Lease{client: c, namespace: namespace, holder: holder}
+}
+
+// NewLeaseForConfig creates Lease backend storing Leases in namespace of the shared cluster of restConfig. The cluster
+// isn't contacted until heartbeats are written or read
+func NewLeaseForConfig(restConfig *rest.Config, namespace, holder string) (*Lease, error) {
+	mapper, err := apiutil.NewDynamicRESTMapper(restConfig, apiutil.WithLazyDiscovery)
+	if err != nil {
+		return nil, err
+	}
+	c, err := client.New(restConfig, client.Options{Mapper: mapper})
+	if err != nil {
+		return nil, err
+	}
+	return NewLease(c, namespace, holder), nil
+}
+
+// Write creates or renews Lease of heartbeat fqdn
+func (l *Lease) Write(ctx context.Context, fqdn, payload string, ttl time.Duration) error {
+	lease := &coordinationv1.Lease{}
+	err := l.client.Get(ctx, client.ObjectKey{Namespace: l.namespace, Name: leaseName(fqdn)}, lease)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	create := errors.IsNotFound(err)
+	if create {
+		lease = &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: l.namespace, Name: leaseName(fqdn)}}
+	}
+	if lease.Annotations == nil {
+		lease.Annotations = map[string]string{}
+	}
+	lease.Annotations[PayloadAnnotation] = payload
+	now := metav1.NewMicroTime(time.Now())
+	lease.Spec.HolderIdentity = pointer.StringPtr(l.holder)
+	lease.Spec.LeaseDurationSeconds = pointer.Int32Ptr(int32(ttl.Seconds()))
+	lease.Spec.RenewTime = &now
+	if create {
+		lease.Spec.AcquireTime = &now
+		return l.client.Create(ctx, lease)
+	}
+	return l.client.Update(ctx, lease)
+}
+
+// Read returns payload of Lease of heartbeat fqdn. Renew time is returned as payload of Lease without annotation,
+// e.g. renewed by another tool
+func (l *Lease) Read(ctx context.Context, fqdn string) ([]string, error) {
+	lease := &coordinationv1.Lease{}
+	err := l.client.Get(ctx, client.ObjectKey{Namespace: l.namespace, Name: leaseName(fqdn)}, lease)
+	if errors.IsNotFound(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if payload, ok := lease.Annotations[PayloadAnnotation]; ok {
+		return []string{payload}, nil
+	}
+	if lease.Spec.RenewTime == nil {
+		return nil, nil
+	}
+	return []string{lease.Spec.RenewTime.UTC().Format(time.RFC3339)}, nil
+}
+
+// Delete removes Lease of heartbeat fqdn
+func (l *Lease) Delete(ctx context.Context, fqdn string) error {
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: l.namespace, Name: leaseName(fqdn)}}
+	return client.IgnoreNotFound(l.client.Delete(ctx, lease))
+}
+
+// leaseName returns FQDN of the heartbeat, which is valid name of Kubernetes object
+func leaseName(fqdn string) string {
+	return strings.ToLower(strings.TrimSuffix(fqdn, "."))
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package heartbeat
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLeaseIsCreatedAndRenewed(t *testing.T) {
+	// arrange
+	c := fake.NewFakeClientWithScheme(scheme.Scheme)
+	l := NewLease(c, "k8gb", "us")
+	// act
+	require.NoError(t, l.Write(context.TODO(), fqdn+".", "v=1;ts=2021-06-10T22:14:03Z", time.Minute))
+	require.NoError(t, l.Write(context.TODO(), fqdn, "v=1;ts=2021-06-10T22:14:33Z", 5*time.Minute))
+	payloads, err := l.Read(context.TODO(), fqdn)
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"v=1;ts=2021-06-10T22:14:33Z"}, payloads)
+	lease := &coordinationv1.Lease{}
+	require.NoError(t, c.Get(context.TODO(), client.ObjectKey{Namespace: "k8gb", Name: fqdn}, lease))
+	assert.Equal(t, "us", *lease.Spec.HolderIdentity)
+	assert.Equal(t, int32(300), *lease.Spec.LeaseDurationSeconds)
+	assert.NotNil(t, lease.Spec.AcquireTime)
+	assert.NotNil(t, lease.Spec.RenewTime)
+}
+
+func TestMissingLease(t *testing.T) {
+	// arrange
+	l := NewLease(fake.NewFakeClientWithScheme(scheme.Scheme), "k8gb", "us")
+	// act
+	payloads, err := l.Read(context.TODO(), fqdn)
+	deleteErr := l.Delete(context.TODO(), fqdn)
+	// assert
+	assert.NoError(t, err)
+	assert.Empty(t, payloads)
+	assert.NoError(t, deleteErr)
+}
+
+func TestLeaseIsDeleted(t *testing.T) {
+	// arrange
+	l := NewLease(fake.NewFakeClientWithScheme(scheme.Scheme), "k8gb", "us")
+	require.NoError(t, l.Write(context.TODO(), fqdn, "v=1;ts=2021-06-10T22:14:03Z", time.Minute))
+	// act
+	err := l.Delete(context.TODO(), fqdn)
+	payloads, readErr := l.Read(context.TODO(), fqdn)
+	// assert
+	assert.NoError(t, err)
+	assert.NoError(t, readErr)
+	assert.Empty(t, payloads)
+}
+
+func TestLeaseWithoutPayloadIsReadByRenewTime(t *testing.T) {
+	// arrange
+	renewed := metav1.NewMicroTime(written.Timestamp)
+	lease := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: "k8gb", Name: fqdn},
+		Spec: coordinationv1.LeaseSpec{RenewTime: &renewed}}
+	l := NewLease(fake.NewFakeClientWithScheme(scheme.Scheme, lease), "k8gb", "us")
+	// act
+	payloads, err := l.Read(context.TODO(), fqdn)
+	// assert
+	require.NoError(t, err)
+	require.Len(t, payloads, 1)
+	parsed, err := Parse(fqdn, payloads[0], "")
+	assert.NoError(t, err)
+	assert.Equal(t, written.Timestamp, parsed.Timestamp)
+}
@@ -53,6 +53,11 @@ func (r *ReconcileResultHandler) Requeue() (ctrl.Result, error) {
 	return r.delayedResult, nil
 }
 
+// RequeueAfter requeue loop after given duration, when time-driven change of the state is pending
+func (r *ReconcileResultHandler) RequeueAfter(d time.Duration) (ctrl.Result, error) {
+	return ctrl.Result{RequeueAfter: d}, nil
+}
+
 func (r *ReconcileResultHandler) RequeueNow() (ctrl.Result, error) {
 	return ctrl.Result{Requeue: true}, nil
 }
@@ -18,6 +18,7 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package logging
 
 import (
+	"io"
 	"os"
 	"time"
 
@@ -72,3 +73,20 @@ func (l *loggerFactory) get() zerolog.Logger {
 		Msg("Logger settings")
 	return logger
 }
+
+// newAuditLogger returns JSON logger of audit stream, regardless of log format and level, or disabled logger
+// unless the audit log is enabled
+func newAuditLogger(config *depresolver.Config) zerolog.Logger {
+	if config == nil || !config.Audit.LogEnabled {
+		return zerolog.Nop()
+	}
+	return newAuditLoggerTo(os.Stdout)
+}
+
+func newAuditLoggerTo(w io.Writer) zerolog.Logger {
+	return zerolog.New(w).
+		With().
+		Timestamp().
+		Str("stream", "audit").
+		Logger()
+}
@@ -18,15 +18,18 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package logging
 
 import (
+	"context"
 	"sync"
 
 	"github.com/AbsaOSS/k8gb/controllers/depresolver"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
-	once sync.Once
-	log  zerolog.Logger
+	once  sync.Once
+	log   zerolog.Logger
+	audit = zerolog.Nop()
 )
 
 // Logger public static logger, providing instance of initialised logger
@@ -34,9 +37,29 @@ func Logger() *zerolog.Logger {
 	return &log
 }
 
+// Audit returns logger of dedicated audit stream. It discards entries unless the audit log is enabled
+func Audit() *zerolog.Logger {
+	return &audit
+}
+
+// FromContext returns logger with trace and span IDs of the span in ctx, so log lines can be correlated with
+// traces. The static logger is returned when ctx carries no span
+func FromContext(ctx context.Context) *zerolog.Logger {
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !spanContext.IsValid() {
+		return &log
+	}
+	logger := log.With().
+		Str("trace_id", spanContext.TraceID().String()).
+		Str("span_id", spanContext.SpanID().String()).
+		Logger()
+	return &logger
+}
+
 // Init always initialise logger, no mif config is nil or not
 func Init(c *depresolver.Config) {
 	once.Do(func() {
 		log = newLogger(c).get()
+		audit = newAuditLogger(c)
 	})
 }
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestLoggerFromContextCarriesTraceIDs(t *testing.T) {
+	// arrange
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(tracetest.NewInMemoryExporter()))
+	ctx, span := provider.Tracer("test").Start(context.TODO(), "test")
+	defer span.End()
+	var buf bytes.Buffer
+	// act
+	logger := FromContext(ctx).Output(&buf)
+	logger.Info().Msg("traced")
+	// assert
+	fields := map[string]string{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &fields))
+	assert.Equal(t, span.SpanContext().TraceID().String(), fields["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), fields["span_id"])
+}
+
+func TestLoggerFromContextWithoutSpan(t *testing.T) {
+	// act
+	logger := FromContext(context.TODO())
+	// assert
+	assert.Equal(t, Logger(), logger)
+}
+
+func TestAuditLogIgnoresLogLevel(t *testing.T) {
+	// arrange
+	defer zerolog.SetGlobalLevel(zerolog.GlobalLevel())
+	zerolog.SetGlobalLevel(zerolog.ErrorLevel)
+	var buf bytes.Buffer
+	logger := newAuditLoggerTo(&buf)
+	// act
+	logger.Log().Str("host", "app.cloud.example.com").Msg("DNS targets changed")
+	// assert
+	fields := map[string]string{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &fields))
+	assert.Equal(t, "audit", fields["stream"])
+	assert.Equal(t, "app.cloud.example.com", fields["host"])
+}
+
+func TestAuditLogIsDisabledByDefault(t *testing.T) {
+	// act
+	logger := newAuditLogger(&depresolver.Config{})
+	// assert
+	assert.Equal(t, zerolog.Disabled, logger.GetLevel())
+}
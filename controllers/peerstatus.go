@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/peerstatus"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+)
+
+const localTargetsPrefix = "localtargets-"
+
+// PeerStatus returns status of hosts of all Gslbs, which is served to peer clusters. Targets of a host are the same
+// as in its localtargets-<host> record, so peers get the same targets from status API and from DNS.
+func (r *GslbReconciler) PeerStatus(ctx context.Context) (*peerstatus.Status, error) {
+	gslbList := &k8gbv1beta1.GslbList{}
+	if err := r.List(ctx, gslbList); err != nil {
+		return nil, err
+	}
+	status := &peerstatus.Status{GeoTag: r.Config.ClusterGeoTag, Hosts: make(map[string]peerstatus.Host)}
+	for i := range gslbList.Items {
+		gslb := &gslbList.Items[i]
+		if gslb.GetDeletionTimestamp() != nil {
+			continue
+		}
+		targets, err := r.localTargets(ctx, gslb)
+		if err != nil {
+			return nil, err
+		}
+		for host, health := range gslb.Status.ServiceHealth {
+			strategy := hostStrategy(gslb, host)
+			status.Hosts[host] = peerstatus.Host{
+				Gslb:          fmt.Sprintf("%s/%s", gslb.Namespace, gslb.Name),
+				Strategy:      strategy.Type,
+				PrimaryGeoTag: strategy.PrimaryGeoTag,
+				Health:        health,
+				Draining:      gslb.Status.DrainingSince != nil,
+				Targets:       targets[host],
+			}
+		}
+	}
+	return status, nil
+}
+
+// localTargets returns targets of localtargets-<host> records of the Gslb DNSEndpoint by host
+func (r *GslbReconciler) localTargets(ctx context.Context, gslb *k8gbv1beta1.Gslb) (map[string][]string, error) {
+	targets := make(map[string][]string)
+	dnsEndpoint := &externaldns.DNSEndpoint{}
+	err := r.Get(ctx, types.NamespacedName{Namespace: gslb.Namespace, Name: gslb.Name}, dnsEndpoint)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			// DNSEndpoint is not written in dry-run mode
+			return targets, nil
+		}
+		return nil, err
+	}
+	for _, endpoint := range dnsEndpoint.Spec.Endpoints {
+		if strings.HasPrefix(endpoint.DNSName, localTargetsPrefix) && endpoint.RecordType == "A" {
+			targets[strings.TrimPrefix(endpoint.DNSName, localTargetsPrefix)] = endpoint.Targets
+		}
+	}
+	return targets, nil
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package peerstatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+)
+
+const (
+	requestTimeout = 5 * time.Second
+	// cacheTTL bounds how long status of a peer is reused. Targets are asked for every host of every Gslb,
+	// so status, including failure of unavailable peer, is fetched once for all of them
+	cacheTTL = 5 * time.Second
+)
+
+// Client fetches status of peer clusters
+type Client struct {
+	endpoints map[string]string
+	http      *http.Client
+	// mu guards peers only; it isn't held while status is fetched, so slow peer doesn't block the others
+	mu    sync.Mutex
+	peers map[string]*peerCache
+}
+
+// peerCache holds the last status of the peer. Its lock is held while the status is fetched, so concurrent
+// lookups of the same peer wait for a single request.
+type peerCache struct {
+	mu     sync.Mutex
+	status *Status
+	err    error
+	time   time.Time
+}
+
+// NewClient creates client of status API of peers with configured endpoint
+func NewClient(config depresolver.Config) (*Client, error) {
+	tlsConfig, err := clientTLSConfig(config.PeerStatus.CertDir)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{
+		endpoints: config.PeerStatus.Endpoints,
+		http: &http.Client{
+			Timeout:   requestTimeout,
+			Transport: &http.Transport{TLSClientConfig: tlsConfig},
+		},
+		peers: make(map[string]*peerCache),
+	}, nil
+}
+
+// Has returns true if status API endpoint of the peer is configured
+func (c *Client) Has(peer string) bool {
+	_, found := c.endpoints[peer]
+	return found
+}
+
+// Targets returns targets the peer serves for host. It is empty if the peer doesn't serve the host
+func (c *Client) Targets(ctx context.Context, peer, host string) ([]string, error) {
+	status, err := c.Status(ctx, peer)
+	if err != nil {
+		return nil, err
+	}
+	return status.Hosts[host].Targets, nil
+}
+
+// Status returns status of the peer, which is fetched at most once per cacheTTL
+func (c *Client) Status(ctx context.Context, peer string) (*Status, error) {
+	p := c.peer(peer)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.time.IsZero() && time.Since(p.time) < cacheTTL {
+		return p.status, p.err
+	}
+	p.status, p.err = c.fetch(ctx, peer)
+	p.time = time.Now()
+	return p.status, p.err
+}
+
+func (c *Client) peer(name string) *peerCache {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, found := c.peers[name]
+	if !found {
+		p = &peerCache{}
+		c.peers[name] = p
+	}
+	return p
+}
+
+func (c *Client) fetch(ctx context.Context, peer string) (*Status, error) {
+	endpoint, found := c.endpoints[peer]
+	if !found {
+		return nil, fmt.Errorf("no status API endpoint of %s cluster", peer)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("https://%s%s", endpoint, Path), nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status API of %s cluster responded %s", peer, resp.Status)
+	}
+	status := &Status{}
+	if err = json.NewDecoder(resp.Body).Decode(status); err != nil {
+		return nil, fmt.Errorf("decoding status of %s cluster: %w", peer, err)
+	}
+	if status.GeoTag != peer {
+		return nil, fmt.Errorf("endpoint %s of %s cluster serves status of %s cluster", endpoint, peer, status.GeoTag)
+	}
+	return status, nil
+}
@@ -0,0 +1,257 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package peerstatus
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var euStatus = &Status{
+	GeoTag: "eu",
+	Hosts: map[string]Host{
+		"roundrobin.cloud.example.com": {Gslb: "test-gslb/test-gslb", Strategy: "roundRobin", Health: "Healthy",
+			Targets: []string{"10.0.0.1", "10.0.0.2"}},
+		"failover.cloud.example.com": {Gslb: "test-gslb/test-gslb", Strategy: "failover", PrimaryGeoTag: "eu",
+			Health: "Healthy", Draining: true, Targets: []string{"10.0.0.1", "10.0.0.2"}},
+	},
+}
+
+func TestClientReadsTargetsOfPeer(t *testing.T) {
+	// arrange
+	ca := newCA(t)
+	endpoint := startServer(t, ca.issue(t, t.TempDir()), euStatus)
+	client, err := NewClient(clientConfig(ca.issue(t, t.TempDir()), endpoint))
+	require.NoError(t, err)
+	// act
+	targets, err := client.Targets(context.TODO(), "eu", "roundrobin.cloud.example.com")
+	unknown, unknownErr := client.Targets(context.TODO(), "eu", "unknown.cloud.example.com")
+	status, statusErr := client.Status(context.TODO(), "eu")
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, targets)
+	assert.NoError(t, unknownErr)
+	assert.Empty(t, unknown)
+	assert.NoError(t, statusErr)
+	assert.Equal(t, euStatus, status)
+	assert.True(t, client.Has("eu"))
+	assert.False(t, client.Has("za"))
+}
+
+func TestServerRejectsPeerWithUntrustedCertificate(t *testing.T) {
+	// arrange
+	ca := newCA(t)
+	endpoint := startServer(t, ca.issue(t, t.TempDir()), euStatus)
+	// the client trusts the server, but presents certificate of another CA
+	foreignDir := newCA(t).issue(t, t.TempDir())
+	copyFile(t, filepath.Join(ca.issue(t, t.TempDir()), caFile), filepath.Join(foreignDir, caFile))
+	client, err := NewClient(clientConfig(foreignDir, endpoint))
+	require.NoError(t, err)
+	// act
+	_, err = client.Targets(context.TODO(), "eu", "roundrobin.cloud.example.com")
+	// assert
+	assert.Error(t, err)
+}
+
+func TestClientRejectsStatusOfAnotherCluster(t *testing.T) {
+	// arrange
+	ca := newCA(t)
+	endpoint := startServer(t, ca.issue(t, t.TempDir()), euStatus)
+	config := clientConfig(ca.issue(t, t.TempDir()), endpoint)
+	config.PeerStatus.Endpoints = map[string]string{"za": endpoint}
+	client, err := NewClient(config)
+	require.NoError(t, err)
+	// act
+	_, err = client.Targets(context.TODO(), "za", "roundrobin.cloud.example.com")
+	// assert
+	assert.Error(t, err)
+}
+
+func TestClientCachesFailureOfPeer(t *testing.T) {
+	// arrange
+	ca := newCA(t)
+	var calls int32
+	endpoint := startServerOf(t, ca.issue(t, t.TempDir()), func(context.Context) (*Status, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, errors.New("cache not synced")
+	})
+	client, err := NewClient(clientConfig(ca.issue(t, t.TempDir()), endpoint))
+	require.NoError(t, err)
+	// act
+	_, err1 := client.Targets(context.TODO(), "eu", "roundrobin.cloud.example.com")
+	_, err2 := client.Targets(context.TODO(), "eu", "failover.cloud.example.com")
+	// assert
+	assert.Error(t, err1)
+	assert.Equal(t, err1, err2)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestUnavailablePeerDoesNotBlockOtherPeers(t *testing.T) {
+	// arrange
+	ca := newCA(t)
+	endpoint := startServer(t, ca.issue(t, t.TempDir()), euStatus)
+	// za accepts connections, but never completes TLS handshake
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		for {
+			if _, err := listener.Accept(); err != nil {
+				return
+			}
+		}
+	}()
+	config := clientConfig(ca.issue(t, t.TempDir()), endpoint)
+	config.PeerStatus.Endpoints["za"] = listener.Addr().String()
+	client, err := NewClient(config)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	blocked := make(chan error)
+	go func() {
+		_, err := client.Status(ctx, "za")
+		blocked <- err
+	}()
+	time.Sleep(100 * time.Millisecond)
+	// act
+	start := time.Now()
+	targets, err := client.Targets(context.TODO(), "eu", "roundrobin.cloud.example.com")
+	elapsed := time.Since(start)
+	cancel()
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2"}, targets)
+	assert.Less(t, elapsed.Seconds(), requestTimeout.Seconds()/2)
+	assert.Error(t, <-blocked)
+}
+
+func TestNewClientWithoutCertificates(t *testing.T) {
+	// act
+	_, err := NewClient(clientConfig(t.TempDir(), "localhost:8443"))
+	// assert
+	assert.Error(t, err)
+}
+
+func clientConfig(certDir, endpoint string) depresolver.Config {
+	config := depresolver.Config{ClusterGeoTag: "us", ExtClustersGeoTags: []string{"eu", "za"}}
+	config.PeerStatus = depresolver.PeerStatus{Enabled: true, CertDir: certDir, Endpoints: map[string]string{"eu": endpoint}}
+	return config
+}
+
+func startServer(t *testing.T, certDir string, status *Status) string {
+	return startServerOf(t, certDir, func(context.Context) (*Status, error) {
+		return status, nil
+	})
+}
+
+// startServerOf serves status until the test is finished and returns its endpoint
+func startServerOf(t *testing.T, certDir string, source Source) string {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &Server{certDir: certDir, source: source}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- server.serve(ctx, listener)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		assert.NoError(t, <-done)
+	})
+	return listener.Addr().String()
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	key  *ecdsa.PrivateKey
+	pem  []byte
+}
+
+func newCA(t *testing.T) *testCA {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "k8gb peer status CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return &testCA{cert: cert, key: key, pem: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})}
+}
+
+// issue writes certificate valid for both server and client of 127.0.0.1 together with the CA into dir
+func (ca *testCA) issue(t *testing.T, dir string) string {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: "k8gb"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.cert, &key.PublicKey, ca.key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	writeFile(t, filepath.Join(dir, certFile), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+	writeFile(t, filepath.Join(dir, keyFile), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	writeFile(t, filepath.Join(dir, caFile), ca.pem)
+	return dir
+}
+
+func writeFile(t *testing.T, path string, data []byte) {
+	t.Helper()
+	require.NoError(t, ioutil.WriteFile(path, data, 0600))
+}
+
+func copyFile(t *testing.T, from, to string) {
+	t.Helper()
+	data, err := ioutil.ReadFile(from)
+	require.NoError(t, err)
+	writeFile(t, to, data)
+}
@@ -0,0 +1,100 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package peerstatus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+)
+
+const shutdownTimeout = 5 * time.Second
+
+// Source returns the current status of the cluster
+type Source func(ctx context.Context) (*Status, error)
+
+// Server serves status of the cluster to peers. Only peers presenting certificate signed by the configured CA
+// are served
+type Server struct {
+	addr    string
+	certDir string
+	source  Source
+}
+
+// NewServer creates server of the status returned by source
+func NewServer(config depresolver.Config, source Source) *Server {
+	return &Server{
+		addr:    fmt.Sprintf(":%d", config.PeerStatus.Port),
+		certDir: config.PeerStatus.CertDir,
+		source:  source,
+	}
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	status, err := s.source(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err = json.NewEncoder(w).Encode(status); err != nil {
+		log.Err(err).Msg("Unable to write peer status")
+	}
+}
+
+// Start serves the status until the context is done; it implements manager.Runnable
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	return s.serve(ctx, listener)
+}
+
+func (s *Server) serve(ctx context.Context, listener net.Listener) error {
+	tlsConfig, err := serverTLSConfig(s.certDir)
+	if err != nil {
+		_ = listener.Close()
+		return err
+	}
+	mux := http.NewServeMux()
+	mux.Handle(Path, s)
+	server := &http.Server{Handler: mux, TLSConfig: tlsConfig, ReadHeaderTimeout: requestTimeout}
+	errs := make(chan error, 1)
+	go func() {
+		log.Info().Msgf("Serving peer status at %s", listener.Addr())
+		errs <- server.ServeTLS(listener, "", "")
+	}()
+	select {
+	case err = <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
@@ -0,0 +1,54 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+// Package peerstatus exchanges status of Gslb hosts between clusters over mutual TLS. It complements resolution
+// of localtargets-<host> records from nameservers of peer clusters, which carries targets only.
+package peerstatus
+
+import (
+	"github.com/AbsaOSS/k8gb/controllers/logging"
+)
+
+// Path of the status API
+const Path = "/v1/status"
+
+var log = logging.Logger()
+
+// Status of hosts of all Gslbs of the cluster
+type Status struct {
+	// GeoTag of the cluster
+	GeoTag string `json:"geoTag"`
+	// Hosts by FQDN
+	Hosts map[string]Host `json:"hosts"`
+}
+
+// Host is status of Gslb host served by the cluster. Strategies don't weight targets, so no weights are published;
+// unknown fields are ignored by readers, so they can be added once a weighted strategy exists.
+type Host struct {
+	// Gslb of the host in form namespace/name
+	Gslb string `json:"gslb"`
+	// Strategy type of the host
+	Strategy string `json:"strategy"`
+	// PrimaryGeoTag of failover strategy
+	PrimaryGeoTag string `json:"primaryGeoTag,omitempty"`
+	// Health of the service of the host, e.g. Healthy, Unhealthy or NotFound
+	Health string `json:"health"`
+	// Draining is true while the Gslb is taken out of global rotation
+	Draining bool `json:"draining"`
+	// Targets the cluster serves for the host, the same as its localtargets-<host> record
+	Targets []string `json:"targets"`
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package peerstatus
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+)
+
+// files in the certificate directory, as in secret of kubernetes.io/tls type with CA bundle
+const (
+	certFile = "tls.crt"
+	keyFile  = "tls.key"
+	caFile   = "ca.crt"
+)
+
+// loadTLS reads certificate of the cluster and CA verifying certificates of peers from certDir
+func loadTLS(certDir string) (tls.Certificate, *x509.CertPool, error) {
+	cert, err := tls.LoadX509KeyPair(filepath.Join(certDir, certFile), filepath.Join(certDir, keyFile))
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("loading certificate of peer status API: %w", err)
+	}
+	ca, err := ioutil.ReadFile(filepath.Join(certDir, caFile))
+	if err != nil {
+		return tls.Certificate{}, nil, fmt.Errorf("loading CA of peer status API: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(ca) {
+		return tls.Certificate{}, nil, fmt.Errorf("no certificate found in %s", filepath.Join(certDir, caFile))
+	}
+	return cert, pool, nil
+}
+
+// serverTLSConfig serves only peers presenting certificate signed by the CA
+func serverTLSConfig(certDir string) (*tls.Config, error) {
+	cert, pool, err := loadTLS(certDir)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		ClientCAs:    pool,
+		ClientAuth:   tls.RequireAndVerifyClientCert,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
+
+// clientTLSConfig presents certificate of the cluster and trusts peers with certificate signed by the CA
+func clientTLSConfig(certDir string) (*tls.Config, error) {
+	cert, pool, err := loadTLS(certDir)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS12,
+	}, nil
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPeerStatusPublishesLocalTargetsAndDrain(t *testing.T) {
+	// arrange
+	serviceName := "frontend-podinfo"
+	settings := provideDrainSettings(t, true, 3600)
+	createHealthyService(t, &settings, serviceName)
+	defer deleteHealthyService(t, &settings, serviceName)
+	_, err := settings.reconciler.Reconcile(context.TODO(), settings.request)
+	require.NoError(t, err)
+	// act
+	status, err := settings.reconciler.PeerStatus(context.TODO())
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "eu", status.GeoTag)
+	require.Contains(t, status.Hosts, "roundrobin.cloud.example.com")
+	host := status.Hosts["roundrobin.cloud.example.com"]
+	assert.Equal(t, "test-gslb/test-gslb", host.Gslb)
+	assert.Equal(t, "roundRobin", host.Strategy)
+	assert.Equal(t, "Healthy", host.Health)
+	assert.True(t, host.Draining)
+	assert.Equal(t, []string{"10.0.0.1"}, host.Targets)
+	require.Contains(t, status.Hosts, "notfound.cloud.example.com")
+	assert.Equal(t, "NotFound", status.Hosts["notfound.cloud.example.com"].Health)
+	assert.Empty(t, status.Hosts["notfound.cloud.example.com"].Targets)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/providers/dns"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// PeerTargetsRefresher periodically resolves targets which other clusters serve for hosts of Gslbs and triggers
+// reconciliation of Gslbs whose external targets changed. Changes within the cluster are handled by watches,
+// so Gslbs aren't requeued periodically.
+type PeerTargetsRefresher struct {
+	client      client.Reader
+	dnsProvider dns.Provider
+	interval    time.Duration
+	events      chan event.GenericEvent
+	// last seen external targets of hosts per Gslb
+	targets map[types.NamespacedName]map[string][]string
+}
+
+// NewPeerTargetsRefresher creates refresher resolving external targets every interval
+func NewPeerTargetsRefresher(c client.Reader, dnsProvider dns.Provider, interval time.Duration) *PeerTargetsRefresher {
+	return &PeerTargetsRefresher{
+		client:      c,
+		dnsProvider: dnsProvider,
+		interval:    interval,
+		events:      make(chan event.GenericEvent),
+		targets:     make(map[types.NamespacedName]map[string][]string),
+	}
+}
+
+// Source of events for Gslbs whose external targets changed
+func (r *PeerTargetsRefresher) Source() source.Source {
+	return &source.Channel{Source: r.events}
+}
+
+// Start runs the refresher until the context is done; it implements manager.Runnable
+func (r *PeerTargetsRefresher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := r.refresh(ctx); err != nil {
+				log.Err(err).Msg("Unable to refresh targets of external clusters")
+			}
+		}
+	}
+}
+
+// refresh resolves external targets of all Gslbs and emits event for every Gslb whose targets changed since
+// the previous refresh. Gslbs seen for the first time emit event too, as targets might have changed between
+// their last reconciliation (e.g. on operator start) and the first refresh.
+func (r *PeerTargetsRefresher) refresh(ctx context.Context) error {
+	gslbList := &k8gbv1beta1.GslbList{}
+	if err := r.client.List(ctx, gslbList); err != nil {
+		return err
+	}
+	seen := make(map[types.NamespacedName]map[string][]string, len(gslbList.Items))
+	for i := range gslbList.Items {
+		gslb := &gslbList.Items[i]
+		if gslb.GetDeletionTimestamp() != nil {
+			continue
+		}
+		key := types.NamespacedName{Namespace: gslb.Namespace, Name: gslb.Name}
+		targets := make(map[string][]string)
+		for _, rule := range gslb.Spec.Ingress.Rules {
+			external := r.dnsProvider.GetExternalTargets(ctx, rule.Host)
+			sort.Strings(external)
+			targets[rule.Host] = external
+		}
+		seen[key] = targets
+		if previous, found := r.targets[key]; !found || !reflect.DeepEqual(previous, targets) {
+			log.Info().Msgf("Targets of external clusters changed for Gslb %s/%s", gslb.Namespace, gslb.Name)
+			select {
+			case r.events <- event.GenericEvent{Object: gslb}:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+	}
+	r.targets = seen
+	return nil
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/providers/dns"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func newPeerTargetsRefresher(t *testing.T, provider dns.Provider) *PeerTargetsRefresher {
+	s := runtime.NewScheme()
+	require.NoError(t, k8gbv1beta1.AddToScheme(s))
+	gslb := &k8gbv1beta1.Gslb{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "test-gslb", Name: "app"},
+		Spec: k8gbv1beta1.GslbSpec{Ingress: k8gbv1beta1.IngressSpec{
+			Rules: []k8gbv1beta1.IngressRule{{Host: "app.cloud.example.com"}},
+		}},
+	}
+	c := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(gslb).Build()
+	r := NewPeerTargetsRefresher(c, provider, time.Minute)
+	// buffered, so refresh doesn't block without controller reading the source
+	r.events = make(chan event.GenericEvent, 1)
+	return r
+}
+
+func TestPeerTargetsRefresherEmitsEventOnlyOnChange(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	provider := dns.NewMockProvider(ctrl)
+	gomock.InOrder(
+		provider.EXPECT().GetExternalTargets(gomock.Any(), "app.cloud.example.com").Return([]string{"10.1.0.2", "10.1.0.1"}),
+		provider.EXPECT().GetExternalTargets(gomock.Any(), "app.cloud.example.com").Return([]string{"10.1.0.1", "10.1.0.2"}),
+		provider.EXPECT().GetExternalTargets(gomock.Any(), "app.cloud.example.com").Return([]string{"10.1.0.1"}),
+	)
+	r := newPeerTargetsRefresher(t, provider)
+	// act
+	require.NoError(t, r.refresh(context.TODO()))
+	firstSighting := len(r.events)
+	<-r.events
+	require.NoError(t, r.refresh(context.TODO()))
+	reordered := len(r.events)
+	require.NoError(t, r.refresh(context.TODO()))
+	// assert
+	assert.Equal(t, 1, firstSighting)
+	assert.Equal(t, 0, reordered)
+	require.Len(t, r.events, 1)
+	e := <-r.events
+	assert.Equal(t, "app", e.Object.GetName())
+	assert.Equal(t, "test-gslb", e.Object.GetNamespace())
+}
+
+func TestPeerTargetsChangedBeforeFirstRefreshTriggersReconcile(t *testing.T) {
+	// arrange
+	// Gslb was reconciled on operator start with no external targets, peer came up before the first tick
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	provider := dns.NewMockProvider(ctrl)
+	provider.EXPECT().GetExternalTargets(gomock.Any(), "app.cloud.example.com").Return([]string{"10.1.0.1"})
+	r := newPeerTargetsRefresher(t, provider)
+	// act
+	err := r.refresh(context.TODO())
+	// assert
+	require.NoError(t, err)
+	require.Len(t, r.events, 1)
+	e := <-r.events
+	assert.Equal(t, "app", e.Object.GetName())
+	assert.Equal(t, "test-gslb", e.Object.GetNamespace())
+}
@@ -18,9 +18,11 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package assistant
 
 import (
+	"context"
 	"time"
 
 	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
 	externaldns "sigs.k8s.io/external-dns/endpoint"
 )
 
@@ -30,7 +32,7 @@ type Assistant interface {
 	// GslbIngressExposedIPs retrieves list of IP's exposed by all GSLB ingresses
 	GslbIngressExposedIPs(gslb *k8gbv1beta1.Gslb) ([]string, error)
 	// GetExternalTargets retrieves slice of targets from external clusters
-	GetExternalTargets(host string, extClusterNsNames map[string]string) (targets []string)
+	GetExternalTargets(ctx context.Context, host string, extClusterNsNames map[string]string) (targets []string)
 	// SaveDNSEndpoint update DNS endpoint or create new one if doesnt exist
 	SaveDNSEndpoint(namespace string, i *externaldns.DNSEndpoint) error
 	// RemoveEndpoint removes endpoint
@@ -38,4 +40,10 @@ type Assistant interface {
 	// InspectTXTThreshold inspects fqdn TXT record from edgeDNSServer. If record doesn't exists or timestamp is greater than
 	// splitBrainThreshold the error is returned. In case fakeDNSEnabled is true, 127.0.0.1:7753 is used as edgeDNSServer
 	InspectTXTThreshold(fqdn string, splitBrainThreshold time.Duration) error
+	// InspectHeartbeat returns the freshest heartbeat of fqdn TXT record from edgeDNSServer, or from heartbeat backend
+	// if it is used. If record doesn't exists or the heartbeat is older than splitBrainThreshold the error is returned
+	InspectHeartbeat(fqdn string, splitBrainThreshold time.Duration) (heartbeat.Heartbeat, error)
+	// LookupRecords resolves records of given type (A, NS, TXT) from edgeDNSServer. Referrals are followed into
+	// authority section, so NS records of delegated zone are returned as well
+	LookupRecords(fqdn string, recordType string) ([]string, error)
 }
@@ -22,10 +22,12 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package assistant
 
 import (
+	context "context"
 	reflect "reflect"
 	time "time"
 
 	v1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	heartbeat "github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
 	gomock "github.com/golang/mock/gomock"
 	endpoint "sigs.k8s.io/external-dns/endpoint"
 )
@@ -69,17 +71,17 @@ func (mr *MockAssistantMockRecorder) CoreDNSExposedIPs() *gomock.Call {
 }
 
 // GetExternalTargets mocks base method.
-func (m *MockAssistant) GetExternalTargets(host string, extClusterNsNames map[string]string) []string {
+func (m *MockAssistant) GetExternalTargets(ctx context.Context, host string, extClusterNsNames map[string]string) []string {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetExternalTargets", host, extClusterNsNames)
+	ret := m.ctrl.Call(m, "GetExternalTargets", ctx, host, extClusterNsNames)
 	ret0, _ := ret[0].([]string)
 	return ret0
 }
 
 // GetExternalTargets indicates an expected call of GetExternalTargets.
-func (mr *MockAssistantMockRecorder) GetExternalTargets(host, extClusterNsNames interface{}) *gomock.Call {
+func (mr *MockAssistantMockRecorder) GetExternalTargets(ctx, host, extClusterNsNames interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalTargets", reflect.TypeOf((*MockAssistant)(nil).GetExternalTargets), host, extClusterNsNames)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExternalTargets", reflect.TypeOf((*MockAssistant)(nil).GetExternalTargets), ctx, host, extClusterNsNames)
 }
 
 // GslbIngressExposedIPs mocks base method.
@@ -97,6 +99,21 @@ func (mr *MockAssistantMockRecorder) GslbIngressExposedIPs(gslb interface{}) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GslbIngressExposedIPs", reflect.TypeOf((*MockAssistant)(nil).GslbIngressExposedIPs), gslb)
 }
 
+// InspectHeartbeat mocks base method.
+func (m *MockAssistant) InspectHeartbeat(fqdn string, splitBrainThreshold time.Duration) (heartbeat.Heartbeat, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectHeartbeat", fqdn, splitBrainThreshold)
+	ret0, _ := ret[0].(heartbeat.Heartbeat)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectHeartbeat indicates an expected call of InspectHeartbeat.
+func (mr *MockAssistantMockRecorder) InspectHeartbeat(fqdn, splitBrainThreshold interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectHeartbeat", reflect.TypeOf((*MockAssistant)(nil).InspectHeartbeat), fqdn, splitBrainThreshold)
+}
+
 // InspectTXTThreshold mocks base method.
 func (m *MockAssistant) InspectTXTThreshold(fqdn string, splitBrainThreshold time.Duration) error {
 	m.ctrl.T.Helper()
@@ -111,6 +128,21 @@ func (mr *MockAssistantMockRecorder) InspectTXTThreshold(fqdn, splitBrainThresho
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectTXTThreshold", reflect.TypeOf((*MockAssistant)(nil).InspectTXTThreshold), fqdn, splitBrainThreshold)
 }
 
+// LookupRecords mocks base method.
+func (m *MockAssistant) LookupRecords(fqdn, recordType string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LookupRecords", fqdn, recordType)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LookupRecords indicates an expected call of LookupRecords.
+func (mr *MockAssistantMockRecorder) LookupRecords(fqdn, recordType interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LookupRecords", reflect.TypeOf((*MockAssistant)(nil).LookupRecords), fqdn, recordType)
+}
+
 // RemoveEndpoint mocks base method.
 func (m *MockAssistant) RemoveEndpoint(endpointName string) error {
 	m.ctrl.T.Helper()
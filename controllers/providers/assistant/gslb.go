@@ -21,12 +21,16 @@ import (
 	"context"
 	coreerrors "errors"
 	"fmt"
+	"reflect"
 	"strings"
 	"time"
 
 	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
 	"github.com/AbsaOSS/k8gb/controllers/internal/utils"
 	"github.com/AbsaOSS/k8gb/controllers/logging"
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+	"github.com/AbsaOSS/k8gb/controllers/tracing"
 
 	str "github.com/AbsaOSS/gopkg/strings"
 	"github.com/miekg/dns"
@@ -34,11 +38,16 @@ import (
 	v1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	externaldns "sigs.k8s.io/external-dns/endpoint"
 )
 
-const coreDNSExtServiceName = "k8gb-coredns-lb"
+const (
+	coreDNSExtServiceName = "k8gb-coredns-lb"
+	// dnsEndpointProvider labels metrics of DNSEndpoint writes
+	dnsEndpointProvider = "dnsendpoint"
+)
 
 // Gslb is common wrapper operating on GSLB instance.
 // It uses apimachinery client to call kubernetes API
@@ -47,16 +56,25 @@ type Gslb struct {
 	k8gbNamespace     string
 	edgeDNSServer     string
 	edgeDNSServerPort int
+	metrics           *metrics.PrometheusMetrics
+	lookups           lookups
+	peerStatus        PeerStatus
+	tsig              *tsigKey
+	heartbeatKey      string
+	heartbeatBackend  heartbeat.Backend
 }
 
 var log = logging.Logger()
 
-func NewGslbAssistant(client client.Client, k8gbNamespace, edgeDNSServer string, edgeDNSServerPort int) *Gslb {
+// NewGslbAssistant creates assistant; metrics may be nil
+func NewGslbAssistant(client client.Client, k8gbNamespace, edgeDNSServer string, edgeDNSServerPort int,
+	metrics *metrics.PrometheusMetrics) *Gslb {
 	return &Gslb{
 		client:            client,
 		k8gbNamespace:     k8gbNamespace,
 		edgeDNSServer:     edgeDNSServer,
 		edgeDNSServerPort: edgeDNSServerPort,
+		metrics:           metrics,
 	}
 }
 
@@ -135,7 +153,9 @@ func (r *Gslb) SaveDNSEndpoint(namespace string, i *externaldns.DNSEndpoint) err
 
 		// Create the DNSEndpoint
 		log.Info().Msgf("Creating a new DNSEndpoint:\n %s", str.ToString(i))
+		start := time.Now()
 		err = r.client.Create(context.TODO(), i)
+		r.metrics.ObserveProviderRequest(dnsEndpointProvider, "create", start, err)
 
 		if err != nil {
 			// Creation failed
@@ -151,11 +171,18 @@ func (r *Gslb) SaveDNSEndpoint(namespace string, i *externaldns.DNSEndpoint) err
 		return err
 	}
 
+	if reflect.DeepEqual(found.Spec, i.Spec) && reflect.DeepEqual(found.Annotations, i.Annotations) &&
+		reflect.DeepEqual(found.Labels, i.Labels) {
+		return nil
+	}
+
 	// Update existing object with new spec, labels and annotations
 	found.Spec = i.Spec
 	found.ObjectMeta.Annotations = i.ObjectMeta.Annotations
 	found.ObjectMeta.Labels = i.ObjectMeta.Labels
+	start := time.Now()
 	err = r.client.Update(context.TODO(), found)
+	r.metrics.ObserveProviderRequest(dnsEndpointProvider, "update", start, err)
 
 	if err != nil {
 		// Update failed
@@ -178,49 +205,168 @@ func (r *Gslb) RemoveEndpoint(endpointName string) error {
 		}
 		return err
 	}
+	start := time.Now()
 	err = r.client.Delete(context.TODO(), dnsEndpoint)
+	r.metrics.ObserveProviderRequest(dnsEndpointProvider, "delete", start, err)
 	return err
 }
 
 // InspectTXTThreshold inspects fqdn TXT record from edgeDNSServer. If record doesn't exists or timestamp is greater than
 // splitBrainThreshold the error is returned.
 func (r *Gslb) InspectTXTThreshold(fqdn string, splitBrainThreshold time.Duration) error {
+	_, err := r.InspectHeartbeat(fqdn, splitBrainThreshold)
+	return err
+}
+
+// InspectHeartbeat returns the freshest heartbeat of fqdn TXT record from edgeDNSServer, or from heartbeat backend
+// if it is used. If record doesn't exists or the heartbeat is older than splitBrainThreshold the error is returned
+func (r *Gslb) InspectHeartbeat(fqdn string, splitBrainThreshold time.Duration) (heartbeat.Heartbeat, error) {
+	h, age, err := r.heartbeatAge(fqdn)
+	r.lookups.recordHeartbeat(fqdn, h, age, err)
+	if err != nil {
+		return heartbeat.Heartbeat{}, err
+	}
+	if age > splitBrainThreshold {
+		return heartbeat.Heartbeat{}, errors.NewResourceExpired(
+			fmt.Sprintf("Split brain TXT record expired the time threshold: (%s)", splitBrainThreshold))
+	}
+	return h, nil
+}
+
+// HeartbeatAge returns time since the freshest heartbeat of fqdn TXT record from edgeDNSServer was written
+func (r *Gslb) HeartbeatAge(fqdn string) (time.Duration, error) {
+	h, age, err := r.heartbeatAge(fqdn)
+	r.lookups.recordHeartbeat(fqdn, h, age, err)
+	return age, err
+}
+
+// UseHeartbeatBackend reads heartbeats from backend instead of edge DNS. They are verified and inspected the same way
+func (r *Gslb) UseHeartbeatBackend(backend heartbeat.Backend) {
+	r.heartbeatBackend = backend
+}
+
+// ReadLeaseHeartbeats reads heartbeats from Leases in namespace of the shared cluster of restConfig instead of edge
+// DNS, e.g. for clients inspecting clusters which use the Lease heartbeat backend
+func (r *Gslb) ReadLeaseHeartbeats(restConfig *rest.Config, namespace string) error {
+	lease, err := heartbeat.NewLeaseForConfig(restConfig, namespace, "")
+	if err != nil {
+		return err
+	}
+	r.UseHeartbeatBackend(lease)
+	return nil
+}
+
+func (r *Gslb) heartbeatAge(fqdn string) (heartbeat.Heartbeat, time.Duration, error) {
+	payloads, err := r.readHeartbeat(fqdn)
+	if err != nil {
+		return heartbeat.Heartbeat{}, 0, err
+	}
+	h, err := r.freshestHeartbeat(fqdn, payloads)
+	if err != nil {
+		return heartbeat.Heartbeat{}, 0, err
+	}
+	if h == nil {
+		if r.heartbeatBackend != nil {
+			return heartbeat.Heartbeat{}, 0, errors.NewResourceExpired(fmt.Sprintf("Can't find split brain heartbeat %s in backend", fqdn))
+		}
+		return heartbeat.Heartbeat{}, 0, errors.NewResourceExpired(
+			fmt.Sprintf("Can't find split brain TXT record at EdgeDNS server(%s:%v) and record %s ", r.edgeDNSServer, r.edgeDNSServerPort, fqdn))
+	}
+	diff := time.Now().UTC().Sub(h.Timestamp)
+	r.metrics.UpdateHeartbeatAge(fqdn, diff)
+	log.Debug().
+		Str("fqdn", fqdn).
+		Str("parsed", h.Timestamp.String()).
+		Str("geoTag", h.GeoTag).
+		Str("version", h.OperatorVersion).
+		Str("diff", diff.String()).
+		Msg("Split brain TXT")
+	return *h, diff, nil
+}
+
+// readHeartbeat returns payloads of heartbeat fqdn from the heartbeat backend or TXT record from edgeDNSServer
+func (r *Gslb) readHeartbeat(fqdn string) ([]string, error) {
+	if r.heartbeatBackend != nil {
+		payloads, err := r.heartbeatBackend.Read(context.TODO(), fqdn)
+		if err != nil {
+			log.Info().Msgf("Error reading split brain heartbeat %s from backend: (%s)", fqdn, err)
+		}
+		return payloads, err
+	}
 	m := new(dns.Msg)
 	m.SetQuestion(dns.Fqdn(fqdn), dns.TypeTXT)
 	ns := fmt.Sprintf("%s:%v", r.edgeDNSServer, r.edgeDNSServerPort)
 	txt, err := dns.Exchange(m, ns)
 	if err != nil {
 		log.Info().Msgf("Error contacting EdgeDNS server (%s) for TXT split brain record: (%s)", ns, err)
-		return err
+		return nil, err
 	}
-	if len(txt.Answer) > 0 {
-		if t, ok := txt.Answer[0].(*dns.TXT); ok {
-			timestamp := strings.Split(t.String(), "\t")[4]
-			timestamp = strings.Trim(timestamp, "\"") // Otherwise time.Parse() will miserably fail
-			timeFromTXT, err := time.Parse("2006-01-02T15:04:05", timestamp)
-			if err != nil {
-				log.Err(err).
-					Str("raw record", t.String()).
-					Str("raw timestamp", timestamp).
-					Msg("Split brain TXT: can't parse timestamp")
-				return err
-			}
-			now := time.Now().UTC()
-			diff := now.Sub(timeFromTXT)
-			log.Debug().
-				Str("raw record", t.String()).
-				Str("raw timestamp", timestamp).
-				Str("parsed", timeFromTXT.String()).
-				Str("diff", diff.String()).
-				Msg("Split brain TXT")
-
-			if diff > splitBrainThreshold {
-				return errors.NewResourceExpired(fmt.Sprintf("Split brain TXT record expired the time threshold: (%s)", splitBrainThreshold))
-			}
-			return nil
+	var payloads []string
+	for _, rr := range txt.Answer {
+		if t, ok := rr.(*dns.TXT); ok {
+			payloads = append(payloads, strings.Join(t.Txt, ""))
+		}
+	}
+	return payloads, nil
+}
+
+// freshestHeartbeat returns the newest valid heartbeat of payloads, which may be more of them, e.g. while edge
+// DNS replaces the record. The error of the last invalid heartbeat is returned when none is valid and nil heartbeat
+// when there is no payload at all
+func (r *Gslb) freshestHeartbeat(fqdn string, payloads []string) (*heartbeat.Heartbeat, error) {
+	var freshest *heartbeat.Heartbeat
+	var err error
+	for _, payload := range payloads {
+		h, parseErr := heartbeat.Parse(fqdn, payload, r.heartbeatKey)
+		if coreerrors.Is(parseErr, heartbeat.ErrInvalidSignature) {
+			r.metrics.ObserveVerificationFailure(metrics.HeartbeatVerification, fqdn)
+		}
+		if parseErr != nil {
+			log.Err(parseErr).
+				Str("fqdn", fqdn).
+				Str("payload", payload).
+				Msg("Split brain TXT: can't parse heartbeat")
+			err = parseErr
+			continue
+		}
+		if freshest == nil || h.Timestamp.After(freshest.Timestamp) {
+			freshest = &h
+		}
+	}
+	if freshest != nil {
+		return freshest, nil
+	}
+	return nil, err
+}
+
+// LookupRecords resolves records of given type (A, NS, TXT) from edgeDNSServer
+func (r *Gslb) LookupRecords(fqdn string, recordType string) ([]string, error) {
+	rrType, ok := dns.StringToType[recordType]
+	if !ok {
+		return nil, fmt.Errorf("unsupported record type %s", recordType)
+	}
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(fqdn), rrType)
+	ns := fmt.Sprintf("%s:%v", r.edgeDNSServer, r.edgeDNSServerPort)
+	msg, err := dns.Exchange(m, ns)
+	if err != nil {
+		return nil, err
+	}
+	var records []string
+	for _, rr := range append(msg.Answer, msg.Ns...) {
+		if rr.Header().Rrtype != rrType || !strings.EqualFold(rr.Header().Name, dns.Fqdn(fqdn)) {
+			continue
+		}
+		switch v := rr.(type) {
+		case *dns.A:
+			records = append(records, v.A.String())
+		case *dns.NS:
+			records = append(records, strings.TrimSuffix(v.Ns, "."))
+		case *dns.TXT:
+			records = append(records, strings.Join(v.Txt, ""))
 		}
 	}
-	return errors.NewResourceExpired(fmt.Sprintf("Can't find split brain TXT record at EdgeDNS server(%s) and record %s ", ns, fqdn))
+	return records, nil
 }
 
 func getARecords(msg *dns.Msg) []string {
@@ -244,29 +390,24 @@ func dnsQuery(host string, nameserver string, nameserverport int) (*dns.Msg, err
 	return dnsMsgA, err
 }
 
-func (r *Gslb) GetExternalTargets(host string, extClusterNsNames map[string]string) (targets []string) {
+func (r *Gslb) GetExternalTargets(ctx context.Context, host string, extClusterNsNames map[string]string) (targets []string) {
 	targets = []string{}
-	for _, cluster := range extClusterNsNames {
-		// Use edgeDNSServer for resolution of NS names and fallback to local nameservers
-		log.Info().Msgf("Adding external Gslb targets from %s cluster...", cluster)
-		glueA, err := dnsQuery(cluster, r.edgeDNSServer, r.edgeDNSServerPort)
-		if err != nil {
-			return
-		}
-		log.Info().Msgf("Resolved glue A record for NS(%s) using edgeDNSServer(%s) : (%v)", cluster, r.edgeDNSServer, glueA.Answer)
-		glueARecords := getARecords(glueA)
-		var nameServerToUse string
-		if len(glueARecords) > 0 {
-			nameServerToUse = glueARecords[0]
-		} else {
-			nameServerToUse = cluster
+	for peer, cluster := range extClusterNsNames {
+		peerCtx, span := tracing.Start(ctx, "peer.lookup", tracing.PeerKey.String(peer), tracing.HostKey.String(host))
+		start := time.Now()
+		clusterTargets, source, err := r.externalTargets(peerCtx, peer, cluster, host)
+		r.metrics.ObservePeerLookup(peer, start, err)
+		if coreerrors.Is(err, errVerification) {
+			r.metrics.ObserveVerificationFailure(metrics.TSIGVerification, peer)
 		}
-		host = fmt.Sprintf("localtargets-%s", host)
-		a, err := dnsQuery(host, nameServerToUse, r.edgeDNSServerPort)
+		r.lookups.recordPeerLookup(peer, cluster, host, source, clusterTargets, err)
+		span.SetAttributes(tracing.TargetsKey.StringSlice(clusterTargets), tracing.SourceKey.String(source))
+		tracing.End(span, err)
 		if err != nil {
-			return
+			// failed lookup, including failed verification, counts as the peer being down
+			log.Err(err).Msgf("Can't resolve external Gslb targets from %s cluster", cluster)
+			continue
 		}
-		clusterTargets := getARecords(a)
 		if len(clusterTargets) > 0 {
 			targets = append(targets, clusterTargets...)
 			log.Info().Msgf("Added external %s Gslb targets from %s cluster", clusterTargets, cluster)
@@ -274,3 +415,26 @@ func (r *Gslb) GetExternalTargets(host string, extClusterNsNames map[string]stri
 	}
 	return
 }
+
+// peerTargets resolves local targets of host exposed by nameserver of external cluster
+func (r *Gslb) peerTargets(host, cluster string) ([]string, error) {
+	// Use edgeDNSServer for resolution of NS names and fallback to local nameservers
+	log.Info().Msgf("Adding external Gslb targets from %s cluster...", cluster)
+	glueA, err := dnsQuery(cluster, r.edgeDNSServer, r.edgeDNSServerPort)
+	if err != nil {
+		return nil, err
+	}
+	log.Info().Msgf("Resolved glue A record for NS(%s) using edgeDNSServer(%s) : (%v)", cluster, r.edgeDNSServer, glueA.Answer)
+	glueARecords := getARecords(glueA)
+	var nameServerToUse string
+	if len(glueARecords) > 0 {
+		nameServerToUse = glueARecords[0]
+	} else {
+		nameServerToUse = cluster
+	}
+	a, err := r.peerQuery(fmt.Sprintf("localtargets-%s", host), nameServerToUse)
+	if err != nil {
+		return nil, err
+	}
+	return getARecords(a), nil
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package assistant
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBackend stores payloads of heartbeats by FQDN
+type memoryBackend map[string][]string
+
+func (b memoryBackend) Write(_ context.Context, fqdn, payload string, _ time.Duration) error {
+	b[fqdn] = []string{payload}
+	return nil
+}
+
+func (b memoryBackend) Read(_ context.Context, fqdn string) ([]string, error) {
+	if fqdn == "unavailable" {
+		return nil, fmt.Errorf("shared cluster is unavailable")
+	}
+	return b[fqdn], nil
+}
+
+func (b memoryBackend) Delete(_ context.Context, fqdn string) error {
+	delete(b, fqdn)
+	return nil
+}
+
+func TestHeartbeatIsReadFromBackend(t *testing.T) {
+	// arrange
+	backend := memoryBackend{heartbeatFQDN: {heartbeat.Payload(heartbeatFQDN,
+		heartbeat.Heartbeat{Timestamp: time.Now().Add(-time.Minute), GeoTag: "eu"}, "")}}
+	a := newBackendAssistant(backend)
+	// act
+	h, err := a.InspectHeartbeat(heartbeatFQDN, 5*time.Minute)
+	expiredErr := a.InspectTXTThreshold(heartbeatFQDN, 30*time.Second)
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "eu", h.GeoTag)
+	assert.Error(t, expiredErr, "threshold applies to heartbeats from backend")
+}
+
+func TestMissingHeartbeatInBackend(t *testing.T) {
+	// arrange
+	a := newBackendAssistant(memoryBackend{})
+	// act
+	err := a.InspectTXTThreshold(heartbeatFQDN, 5*time.Minute)
+	unavailableErr := a.InspectTXTThreshold("unavailable", 5*time.Minute)
+	// assert
+	assert.Error(t, err)
+	assert.Error(t, unavailableErr)
+	assert.Len(t, a.Heartbeats(), 2)
+}
+
+func TestForgedHeartbeatInBackend(t *testing.T) {
+	// arrange
+	backend := memoryBackend{heartbeatFQDN: {heartbeat.Payload(heartbeatFQDN, heartbeat.Heartbeat{Timestamp: time.Now()}, "forged")}}
+	a := newBackendAssistant(backend)
+	a.VerifyHeartbeats("heartbeat-secret")
+	// act
+	err := a.InspectTXTThreshold(heartbeatFQDN, 5*time.Minute)
+	// assert
+	assert.Error(t, err)
+	assert.Equal(t, 1., verificationFailures(a.metrics, metrics.HeartbeatVerification, heartbeatFQDN))
+}
+
+func newBackendAssistant(backend heartbeat.Backend) *Gslb {
+	// edge DNS isn't running, so every heartbeat must come from the backend
+	a := NewGslbAssistant(nil, "k8gb", "127.0.0.1", 1, metrics.NewPrometheusMetrics(depresolver.Config{K8gbNamespace: "k8gb"}))
+	a.UseHeartbeatBackend(backend)
+	return a
+}
@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package assistant
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
+)
+
+// PeerLookup is the last resolution of targets of the host from nameserver of a peer cluster
+type PeerLookup struct {
+	// Peer is geo tag of the cluster
+	Peer string `json:"peer"`
+	// Nameserver of the peer cluster
+	Nameserver string `json:"nameserver"`
+	Host       string `json:"host"`
+	// Source of the targets, dns or peerStatus
+	Source string `json:"source"`
+	// Targets served by the peer cluster for the host
+	Targets []string `json:"targets"`
+	// Error of the lookup, if it failed
+	Error string    `json:"error,omitempty"`
+	Time  time.Time `json:"time"`
+}
+
+// Heartbeat is the last inspection of heartbeat TXT record in edge DNS
+type Heartbeat struct {
+	FQDN string `json:"fqdn"`
+	// Age of the timestamp written in the record
+	Age string `json:"age,omitempty"`
+	// GeoTag, Version and Health written in the record by the peer cluster, if it publishes them
+	GeoTag  string `json:"geoTag,omitempty"`
+	Version string `json:"version,omitempty"`
+	Health  string `json:"health,omitempty"`
+	// Stale are clusters which the peer cluster considers stale; they are its votes in split brain quorum
+	Stale []string `json:"stale,omitempty"`
+	// Error of the inspection, e.g. missing record
+	Error string    `json:"error,omitempty"`
+	Time  time.Time `json:"time"`
+}
+
+// lookups keeps results of the last peer lookups and heartbeat inspections, so they can be inspected without
+// raising log level
+type lookups struct {
+	mu         sync.Mutex
+	peers      map[string]PeerLookup
+	heartbeats map[string]Heartbeat
+}
+
+func (l *lookups) recordPeerLookup(peer, nameserver, host, source string, targets []string, err error) {
+	lookup := PeerLookup{Peer: peer, Nameserver: nameserver, Host: host, Source: source, Targets: targets, Time: time.Now()}
+	if err != nil {
+		lookup.Error = err.Error()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.peers == nil {
+		l.peers = make(map[string]PeerLookup)
+	}
+	l.peers[peer+"/"+host] = lookup
+}
+
+func (l *lookups) recordHeartbeat(fqdn string, h heartbeat.Heartbeat, age time.Duration, err error) {
+	record := Heartbeat{FQDN: fqdn, GeoTag: h.GeoTag, Version: h.OperatorVersion, Health: h.Health, Stale: h.Stale,
+		Time: time.Now()}
+	if err != nil {
+		record.Error = err.Error()
+	} else {
+		record.Age = age.Round(time.Second).String()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.heartbeats == nil {
+		l.heartbeats = make(map[string]Heartbeat)
+	}
+	l.heartbeats[fqdn] = record
+}
+
+// PeerLookups returns the last lookup of every host from every peer cluster, ordered by peer and host
+func (r *Gslb) PeerLookups() []PeerLookup {
+	r.lookups.mu.Lock()
+	defer r.lookups.mu.Unlock()
+	result := make([]PeerLookup, 0, len(r.lookups.peers))
+	for _, lookup := range r.lookups.peers {
+		result = append(result, lookup)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Peer != result[j].Peer {
+			return result[i].Peer < result[j].Peer
+		}
+		return result[i].Host < result[j].Host
+	})
+	return result
+}
+
+// Heartbeats returns the last inspection of every heartbeat record, ordered by FQDN
+func (r *Gslb) Heartbeats() []Heartbeat {
+	r.lookups.mu.Lock()
+	defer r.lookups.mu.Unlock()
+	result := make([]Heartbeat, 0, len(r.lookups.heartbeats))
+	for _, record := range r.lookups.heartbeats {
+		result = append(result, record)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].FQDN < result[j].FQDN
+	})
+	return result
+}
@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package assistant
+
+import (
+	"context"
+)
+
+// sources of targets of peer clusters
+const (
+	dnsSource        = "dns"
+	peerStatusSource = "peerStatus"
+)
+
+// PeerStatus provides targets of hosts served by peer clusters from their status API
+type PeerStatus interface {
+	// Has returns true if status API of the peer is configured
+	Has(peer string) bool
+	// Targets returns targets the peer serves for host
+	Targets(ctx context.Context, peer, host string) ([]string, error)
+}
+
+// UsePeerStatus makes GetExternalTargets read targets from status API of peers which have it configured.
+// Targets of other peers, or when the status API fails, are resolved from DNS
+func (r *Gslb) UsePeerStatus(peerStatus PeerStatus) {
+	r.peerStatus = peerStatus
+}
+
+// externalTargets returns targets served by the peer for host together with the source of them
+func (r *Gslb) externalTargets(ctx context.Context, peer, cluster, host string) ([]string, string, error) {
+	if r.peerStatus != nil && r.peerStatus.Has(peer) {
+		targets, err := r.peerStatus.Targets(ctx, peer, host)
+		if err == nil {
+			return targets, peerStatusSource, nil
+		}
+		log.Warn().Err(err).Msgf("Can't get status of %s cluster, resolving targets of %s from DNS", peer, host)
+	}
+	targets, err := r.peerTargets(host, cluster)
+	return targets, dnsSource, err
+}
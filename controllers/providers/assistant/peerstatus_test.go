@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package assistant
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type staticPeerStatus map[string][]string
+
+func (s staticPeerStatus) Has(peer string) bool {
+	return peer != "za"
+}
+
+func (s staticPeerStatus) Targets(_ context.Context, peer, _ string) ([]string, error) {
+	if targets, found := s[peer]; found {
+		return targets, nil
+	}
+	return nil, errors.New("connection refused")
+}
+
+func TestExternalTargetsAreReadFromPeerStatus(t *testing.T) {
+	// arrange
+	// nothing listens on the edge DNS port, so every DNS lookup fails
+	a := NewGslbAssistant(nil, "k8gb", "127.0.0.1", 1, nil)
+	a.UsePeerStatus(staticPeerStatus{"eu": {"10.0.0.1"}})
+	// act
+	targets := a.GetExternalTargets(context.TODO(), "roundrobin.cloud.example.com",
+		map[string]string{"eu": "gslb-ns-eu-cloud.example.com"})
+	_, fallbackSource, fallbackErr := a.externalTargets(context.TODO(), "us", "gslb-ns-us-cloud.example.com",
+		"roundrobin.cloud.example.com")
+	_, unconfiguredSource, unconfiguredErr := a.externalTargets(context.TODO(), "za", "gslb-ns-za-cloud.example.com",
+		"roundrobin.cloud.example.com")
+	// assert
+	assert.Equal(t, []string{"10.0.0.1"}, targets)
+	assert.Equal(t, peerStatusSource, a.PeerLookups()[0].Source)
+	assert.Equal(t, dnsSource, fallbackSource, "failed status API falls back to DNS")
+	assert.Error(t, fallbackErr)
+	assert.Equal(t, dnsSource, unconfiguredSource, "peer without status API is resolved from DNS")
+	assert.Error(t, unconfiguredErr)
+}
@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package assistant
+
+import (
+	coreerrors "errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// tsigFudge is allowed difference of clocks of k8gb and peer nameserver in seconds
+const tsigFudge = 300
+
+// errVerification is wrapped by errors of peer lookups whose response isn't signed by the TSIG key
+var errVerification = coreerrors.New("TSIG verification failed")
+
+type tsigKey struct {
+	name      string
+	algorithm string
+	secret    string
+}
+
+// UseTSIG signs lookups of targets from nameservers of peer clusters by TSIG key and accepts only responses signed
+// by the same key. The secret is base64 encoded
+func (r *Gslb) UseTSIG(name, algorithm, secret string) {
+	r.tsig = &tsigKey{name: dns.Fqdn(name), algorithm: dns.Fqdn(strings.ToLower(algorithm)), secret: secret}
+}
+
+// VerifyHeartbeats makes heartbeat TXT records without valid signature by key treated as missing
+func (r *Gslb) VerifyHeartbeats(key string) {
+	r.heartbeatKey = key
+}
+
+// peerQuery resolves A record of host from nameserver of peer cluster, signed by TSIG key if it is set
+func (r *Gslb) peerQuery(host, nameserver string) (*dns.Msg, error) {
+	if r.tsig == nil {
+		return dnsQuery(host, nameserver, r.edgeDNSServerPort)
+	}
+	msg := new(dns.Msg)
+	msg.SetQuestion(dns.Fqdn(host), dns.TypeA)
+	msg.SetTsig(r.tsig.name, r.tsig.algorithm, tsigFudge, time.Now().Unix())
+	c := &dns.Client{TsigSecret: map[string]string{r.tsig.name: r.tsig.secret}}
+	resp, _, err := c.Exchange(msg, fmt.Sprintf("%s:%v", nameserver, r.edgeDNSServerPort))
+	switch {
+	case err == dns.ErrSig || err == dns.ErrTime || err == dns.ErrSecret:
+		err = fmt.Errorf("%w for %s from %s: %s", errVerification, host, nameserver, err)
+	case err == nil && resp.IsTsig() == nil:
+		err = fmt.Errorf("%w for %s from %s: response isn't signed", errVerification, host, nameserver)
+	}
+	if err != nil {
+		log.Warn().Msgf("Can't resolve FQDN(%s) using nameserver(%s) : (%v)", host, nameserver, err)
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,230 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package assistant
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+
+	"github.com/miekg/dns"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	tsigName       = "k8gb-peer."
+	tsigSecret     = "c2VjcmV0LW9mLWFsbC1jbHVzdGVycw=="
+	heartbeatFQDN  = "test-gslb-heartbeat-eu.example.com"
+	roundRobinHost = "roundrobin.cloud.example.com"
+)
+
+var peers = map[string]string{"eu": "gslb-ns-eu-cloud.example.com"}
+
+func TestTSIGSignedPeerLookup(t *testing.T) {
+	// arrange
+	a, m := newSecuredAssistant(t, startSigningDNS(t, tsigSecret, ""))
+	// act
+	targets := a.GetExternalTargets(context.TODO(), roundRobinHost, peers)
+	// assert
+	assert.Equal(t, []string{"10.0.0.1"}, targets)
+	assert.Empty(t, a.PeerLookups()[0].Error)
+	assert.Equal(t, 0., verificationFailures(m, metrics.TSIGVerification, "eu"))
+}
+
+func TestPeerLookupSignedByAnotherKeyIsRejected(t *testing.T) {
+	// arrange
+	a, m := newSecuredAssistant(t, startSigningDNS(t, "YW5vdGhlci1zZWNyZXQ=", ""))
+	// act
+	targets := a.GetExternalTargets(context.TODO(), roundRobinHost, peers)
+	// assert
+	assert.Empty(t, targets)
+	assert.NotEmpty(t, a.PeerLookups()[0].Error)
+	assert.Equal(t, 1., verificationFailures(m, metrics.TSIGVerification, "eu"))
+}
+
+func TestUnsignedPeerLookupResponseIsRejected(t *testing.T) {
+	// arrange
+	a, m := newSecuredAssistant(t, startSigningDNS(t, "", ""))
+	// act
+	targets := a.GetExternalTargets(context.TODO(), roundRobinHost, peers)
+	// assert
+	assert.Empty(t, targets)
+	assert.Equal(t, 1., verificationFailures(m, metrics.TSIGVerification, "eu"))
+}
+
+func TestPeerFailingVerificationDoesNotDropOtherPeers(t *testing.T) {
+	// arrange
+	// za is resolved from DNS signed by another key, eu from its status API
+	a, m := newSecuredAssistant(t, startSigningDNS(t, "YW5vdGhlci1zZWNyZXQ=", ""))
+	a.UsePeerStatus(staticPeerStatus{"eu": {"10.0.0.2"}})
+	clusters := map[string]string{"eu": "gslb-ns-eu-cloud.example.com", "za": "gslb-ns-za-cloud.example.com"}
+	// act
+	// peers are visited in random order, repeat so the failing one comes first
+	var targets [][]string
+	for i := 0; i < 10; i++ {
+		targets = append(targets, a.GetExternalTargets(context.TODO(), roundRobinHost, clusters))
+	}
+	// assert
+	for _, result := range targets {
+		assert.Equal(t, []string{"10.0.0.2"}, result)
+	}
+	assert.Equal(t, 10., verificationFailures(m, metrics.TSIGVerification, "za"))
+}
+
+func TestSignedHeartbeat(t *testing.T) {
+	// arrange
+	payload := heartbeat.Payload(heartbeatFQDN, heartbeat.Heartbeat{Timestamp: time.Now().Add(-time.Minute)}, "heartbeat-secret")
+	a, m := newSecuredAssistant(t, startSigningDNS(t, tsigSecret, payload))
+	// act
+	err := a.InspectTXTThreshold(heartbeatFQDN, 5*time.Minute)
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, 0., verificationFailures(m, metrics.HeartbeatVerification, heartbeatFQDN))
+}
+
+func TestForgedHeartbeatIsTreatedAsMissing(t *testing.T) {
+	// arrange
+	payload := heartbeat.Payload(heartbeatFQDN, heartbeat.Heartbeat{Timestamp: time.Now()}, "forged-secret")
+	a, m := newSecuredAssistant(t, startSigningDNS(t, tsigSecret, payload))
+	// act
+	err := a.InspectTXTThreshold(heartbeatFQDN, 5*time.Minute)
+	// assert
+	assert.Error(t, err)
+	assert.NotEmpty(t, a.Heartbeats()[0].Error)
+	assert.Equal(t, 1., verificationFailures(m, metrics.HeartbeatVerification, heartbeatFQDN))
+}
+
+func TestFreshestOfMultipleHeartbeatsIsInspected(t *testing.T) {
+	// arrange
+	legacy := time.Now().UTC().Add(-10 * time.Minute).Format("2006-01-02T15:04:05")
+	fresh := heartbeat.Payload(heartbeatFQDN, heartbeat.Heartbeat{Timestamp: time.Now().Add(-time.Minute), GeoTag: "eu",
+		OperatorVersion: "v0.8.0", Health: "1/1"}, "")
+	m := metrics.NewPrometheusMetrics(depresolver.Config{K8gbNamespace: "k8gb"})
+	a := NewGslbAssistant(nil, "k8gb", "127.0.0.1", startSigningDNS(t, "", legacy, fresh, "alive"), m)
+	// act
+	err := a.InspectTXTThreshold(heartbeatFQDN, 5*time.Minute)
+	// assert
+	require.NoError(t, err)
+	inspected := a.Heartbeats()[0]
+	assertAge(t, time.Minute, inspected.Age)
+	assert.Equal(t, "eu", inspected.GeoTag)
+	assert.Equal(t, "v0.8.0", inspected.Version)
+	assert.Equal(t, "1/1", inspected.Health)
+}
+
+func TestLegacyHeartbeatIsInspected(t *testing.T) {
+	// arrange
+	legacy := time.Now().UTC().Add(-10 * time.Minute).Format("2006-01-02T15:04:05")
+	m := metrics.NewPrometheusMetrics(depresolver.Config{K8gbNamespace: "k8gb"})
+	a := NewGslbAssistant(nil, "k8gb", "127.0.0.1", startSigningDNS(t, "", legacy), m)
+	// act
+	err := a.InspectTXTThreshold(heartbeatFQDN, 5*time.Minute)
+	// assert
+	assert.Error(t, err, "heartbeat is older than threshold")
+	assertAge(t, 10*time.Minute, a.Heartbeats()[0].Age)
+}
+
+func TestInvalidHeartbeatsOnly(t *testing.T) {
+	// arrange
+	m := metrics.NewPrometheusMetrics(depresolver.Config{K8gbNamespace: "k8gb"})
+	a := NewGslbAssistant(nil, "k8gb", "127.0.0.1", startSigningDNS(t, "", "alive", "v=1;geo=eu"), m)
+	// act
+	err := a.InspectTXTThreshold(heartbeatFQDN, 5*time.Minute)
+	// assert
+	assert.Error(t, err)
+	assert.Contains(t, a.Heartbeats()[0].Error, "missing heartbeat timestamp")
+}
+
+// assertAge tolerates truncation of timestamps to seconds
+func assertAge(t *testing.T, expected time.Duration, age string) {
+	t.Helper()
+	actual, err := time.ParseDuration(age)
+	require.NoError(t, err)
+	assert.InDelta(t, expected.Seconds(), actual.Seconds(), 2)
+}
+
+func newSecuredAssistant(t *testing.T, port int) (*Gslb, *metrics.PrometheusMetrics) {
+	t.Helper()
+	m := metrics.NewPrometheusMetrics(depresolver.Config{K8gbNamespace: "k8gb"})
+	a := NewGslbAssistant(nil, "k8gb", "127.0.0.1", port, m)
+	a.UseTSIG("k8gb-peer", "HMAC-SHA256", tsigSecret)
+	a.VerifyHeartbeats("heartbeat-secret")
+	return a, m
+}
+
+func verificationFailures(m *metrics.PrometheusMetrics, kind, name string) float64 {
+	failures := m.GetVerificationFailuresMetric()
+	return testutil.ToFloat64(failures.With(prometheus.Labels{"kind": kind, "name": name}))
+}
+
+// startSigningDNS serves edge DNS and nameserver of all clusters. Responses to signed queries are signed by secret,
+// unless it is empty. The heartbeat TXT record has an answer for every non-empty txt
+func startSigningDNS(t *testing.T, secret string, txt ...string) int {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	started := make(chan struct{})
+	server := &dns.Server{PacketConn: pc, NotifyStartedFunc: func() { close(started) }}
+	if secret != "" {
+		server.TsigSecret = map[string]string{tsigName: secret}
+	}
+	server.Handler = dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+		q := req.Question[0]
+		var rrs []string
+		switch {
+		case strings.HasPrefix(q.Name, "gslb-ns-") && q.Qtype == dns.TypeA:
+			rrs = append(rrs, q.Name+" 30 IN A 127.0.0.1")
+		case q.Name == "localtargets-"+roundRobinHost+"." && q.Qtype == dns.TypeA:
+			rrs = append(rrs, q.Name+" 30 IN A 10.0.0.1")
+		case q.Name == heartbeatFQDN+"." && q.Qtype == dns.TypeTXT:
+			for _, payload := range txt {
+				if payload != "" {
+					rrs = append(rrs, q.Name+` 30 IN TXT "`+payload+`"`)
+				}
+			}
+		}
+		for _, rr := range rrs {
+			answer, err := dns.NewRR(rr)
+			require.NoError(t, err)
+			m.Answer = append(m.Answer, answer)
+		}
+		if tsig := req.IsTsig(); tsig != nil && secret != "" {
+			m.SetTsig(tsig.Hdr.Name, tsig.Algorithm, 300, time.Now().Unix())
+		}
+		_ = w.WriteMsg(m)
+	})
+	go func() {
+		_ = server.ActivateAndServe()
+	}()
+	<-started
+	t.Cleanup(func() {
+		_ = server.Shutdown()
+	})
+	return pc.LocalAddr().(*net.UDPAddr).Port
+}
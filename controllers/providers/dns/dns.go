@@ -18,19 +18,34 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package dns
 
 import (
+	"context"
+
 	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
 	externaldns "sigs.k8s.io/external-dns/endpoint"
 )
 
+// ZoneDelegation is delegation of DNSZone to the cluster nameserver. The delegation is cluster-wide;
+// Gslbs reference it and keep their own heartbeat records only.
+type ZoneDelegation struct {
+	// TTL of NS and glue records
+	TTL int
+	// NameserverIPs are addresses of the cluster nameserver written as glue records
+	NameserverIPs []string
+	// Gslbs referencing the delegation; heartbeat records are kept for each of them
+	Gslbs []*k8gbv1beta1.Gslb
+	// Released Gslbs are being deleted; their heartbeat records are removed
+	Released []*k8gbv1beta1.Gslb
+}
+
 type Provider interface {
 	// CreateZoneDelegationForExternalDNS handles delegated zone in Edge DNS
-	CreateZoneDelegationForExternalDNS(*k8gbv1beta1.Gslb) error
+	CreateZoneDelegationForExternalDNS(*ZoneDelegation) error
 	// GslbIngressExposedIPs retrieves list of IP's exposed by all GSLB ingresses
 	GslbIngressExposedIPs(*k8gbv1beta1.Gslb) ([]string, error)
 	// GetExternalTargets retrieves list of external targets for specified host
-	GetExternalTargets(string) []string
+	GetExternalTargets(context.Context, string) []string
 	// SaveDNSEndpoint update DNS endpoint in gslb or create new one if doesn't exist
 	SaveDNSEndpoint(*k8gbv1beta1.Gslb, *externaldns.DNSEndpoint) error
-	// Finalize finalize gslb in k8gbNamespace
-	Finalize(*k8gbv1beta1.Gslb) error
+	// Finalize removes delegation of the zone to this cluster from Edge DNS once no Gslb references it
+	Finalize(*ZoneDelegation) error
 }
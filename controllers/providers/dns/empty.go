@@ -18,6 +18,8 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package dns
 
 import (
+	"context"
+
 	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
 	"github.com/AbsaOSS/k8gb/controllers/depresolver"
 	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
@@ -37,7 +39,7 @@ func NewEmptyDNS(config depresolver.Config, assistant assistant.Assistant) *Empt
 	}
 }
 
-func (p *EmptyDNSProvider) CreateZoneDelegationForExternalDNS(*k8gbv1beta1.Gslb) (err error) {
+func (p *EmptyDNSProvider) CreateZoneDelegationForExternalDNS(*ZoneDelegation) (err error) {
 	return
 }
 
@@ -45,16 +47,21 @@ func (p *EmptyDNSProvider) GslbIngressExposedIPs(gslb *k8gbv1beta1.Gslb) (r []st
 	return p.assistant.GslbIngressExposedIPs(gslb)
 }
 
-func (p *EmptyDNSProvider) GetExternalTargets(host string) (targets []string) {
-	return p.assistant.GetExternalTargets(host, p.config.GetExternalClusterNSNames())
+func (p *EmptyDNSProvider) GetExternalTargets(ctx context.Context, host string) (targets []string) {
+	return p.assistant.GetExternalTargets(ctx, host, p.config.GetExternalClusterNSNames())
 }
 
 func (p *EmptyDNSProvider) SaveDNSEndpoint(gslb *k8gbv1beta1.Gslb, i *externaldns.DNSEndpoint) error {
 	return p.assistant.SaveDNSEndpoint(gslb.Namespace, i)
 }
 
-func (p *EmptyDNSProvider) Finalize(gslb *k8gbv1beta1.Gslb) (err error) {
-	return p.assistant.RemoveEndpoint(gslb.Name)
+func (p *EmptyDNSProvider) Finalize(zd *ZoneDelegation) (err error) {
+	for _, gslb := range zd.Released {
+		if err = p.assistant.RemoveEndpoint(gslb.Name); err != nil {
+			return
+		}
+	}
+	return
 }
 
 func (p *EmptyDNSProvider) String() string {
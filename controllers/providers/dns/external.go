@@ -18,6 +18,7 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package dns
 
 import (
+	"context"
 	"fmt"
 	"sort"
 	"strings"
@@ -57,24 +58,14 @@ func NewExternalDNS(dnsType ExternalDNSType, config depresolver.Config, assistan
 	}
 }
 
-func (p *ExternalDNSProvider) CreateZoneDelegationForExternalDNS(gslb *k8gbv1beta1.Gslb) error {
-	ttl := externaldns.TTL(gslb.Spec.Strategy.DNSTtlSeconds)
+func (p *ExternalDNSProvider) CreateZoneDelegationForExternalDNS(zd *ZoneDelegation) error {
+	ttl := externaldns.TTL(zd.TTL)
 	log.Info().Msgf("Creating/Updating DNSEndpoint CRDs for %s...", p)
 	NSServerList := []string{p.config.GetClusterNSName()}
 	for _, v := range p.config.GetExternalClusterNSNames() {
 		NSServerList = append(NSServerList, v)
 	}
 	sort.Strings(NSServerList)
-	var NSServerIPs []string
-	var err error
-	if p.config.CoreDNSExposed {
-		NSServerIPs, err = p.assistant.CoreDNSExposedIPs()
-	} else {
-		NSServerIPs, err = p.assistant.GslbIngressExposedIPs(gslb)
-	}
-	if err != nil {
-		return err
-	}
 	NSRecord := &externaldns.DNSEndpoint{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        p.endpointName,
@@ -93,24 +84,20 @@ func (p *ExternalDNSProvider) CreateZoneDelegationForExternalDNS(gslb *k8gbv1bet
 					DNSName:    p.config.GetClusterNSName(),
 					RecordTTL:  ttl,
 					RecordType: "A",
-					Targets:    NSServerIPs,
+					Targets:    zd.NameserverIPs,
 				},
 			},
 		},
 	}
-	err = p.assistant.SaveDNSEndpoint(p.config.K8gbNamespace, NSRecord)
-	if err != nil {
-		return err
-	}
-	return nil
+	return p.assistant.SaveDNSEndpoint(p.config.K8gbNamespace, NSRecord)
 }
 
-func (p *ExternalDNSProvider) Finalize(*k8gbv1beta1.Gslb) error {
+func (p *ExternalDNSProvider) Finalize(*ZoneDelegation) error {
 	return p.assistant.RemoveEndpoint(p.endpointName)
 }
 
-func (p *ExternalDNSProvider) GetExternalTargets(host string) (targets []string) {
-	return p.assistant.GetExternalTargets(host, p.config.GetExternalClusterNSNames())
+func (p *ExternalDNSProvider) GetExternalTargets(ctx context.Context, host string) (targets []string) {
+	return p.assistant.GetExternalTargets(ctx, host, p.config.GetExternalClusterNSNames())
 }
 
 func (p *ExternalDNSProvider) GslbIngressExposedIPs(gslb *k8gbv1beta1.Gslb) ([]string, error) {
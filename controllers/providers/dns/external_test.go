@@ -74,6 +74,11 @@ var a = struct {
 	},
 }
 
+// delegation of a.Gslb announcing a.TargetIPs as glue
+func delegation() *ZoneDelegation {
+	return &ZoneDelegation{TTL: 30, NameserverIPs: a.TargetIPs, Gslbs: []*k8gbv1beta1.Gslb{a.Gslb}}
+}
+
 var expectedDNSEndpoint = &externaldns.DNSEndpoint{
 	ObjectMeta: metav1.ObjectMeta{
 		Name:        fmt.Sprintf("k8gb-ns-%s", externalDNSTypeRoute53),
@@ -105,11 +110,10 @@ func TestCreateZoneDelegationOnExternalDNS(t *testing.T) {
 	defer ctrl.Finish()
 	m := assistant.NewMockAssistant(ctrl)
 	p := NewExternalDNS(dnsType, a.Config, m)
-	m.EXPECT().GslbIngressExposedIPs(a.Gslb).Return(a.TargetIPs, nil).Times(1)
 	m.EXPECT().SaveDNSEndpoint(a.Config.K8gbNamespace, gomock.Eq(expectedDNSEndpoint)).Return(nil).Times(1)
 
 	// act, assert
-	err := p.CreateZoneDelegationForExternalDNS(a.Gslb)
+	err := p.CreateZoneDelegationForExternalDNS(delegation())
 	assert.NoError(t, err)
 }
 
@@ -139,7 +143,7 @@ func TestSaveNewDNSEndpointOnExternalDNS(t *testing.T) {
 
 	var cl = fake.NewClientBuilder().WithScheme(runtimeScheme).WithObjects(ep).Build()
 
-	assistant := assistant.NewGslbAssistant(cl, a.Config.K8gbNamespace, a.Config.EdgeDNSServer, a.Config.EdgeDNSServerPort)
+	assistant := assistant.NewGslbAssistant(cl, a.Config.K8gbNamespace, a.Config.EdgeDNSServer, a.Config.EdgeDNSServerPort, nil)
 	p := NewExternalDNS(dnsType, a.Config, assistant)
 	// act, assert
 	err := p.SaveDNSEndpoint(a.Gslb, expectedDNSEndpoint)
@@ -161,7 +165,7 @@ func TestSaveExistingDNSEndpointOnExternalDNS(t *testing.T) {
 	require.NoError(t, schemeBuilder.AddToScheme(runtimeScheme))
 
 	var cl = fake.NewClientBuilder().WithScheme(runtimeScheme).WithObjects(endpointToSave).Build()
-	assistant := assistant.NewGslbAssistant(cl, a.Config.K8gbNamespace, a.Config.EdgeDNSServer, a.Config.EdgeDNSServerPort)
+	assistant := assistant.NewGslbAssistant(cl, a.Config.K8gbNamespace, a.Config.EdgeDNSServer, a.Config.EdgeDNSServerPort, nil)
 	p := NewExternalDNS(dnsType, a.Config, assistant)
 	// act, assert
 	err := p.SaveDNSEndpoint(a.Gslb, endpointToSave)
@@ -21,36 +21,111 @@ import (
 	"fmt"
 
 	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
 	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
 
+	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 type ProviderFactory struct {
-	config depresolver.Config
-	client client.Client
+	config    depresolver.Config
+	client    client.Client
+	metrics   *metrics.PrometheusMetrics
+	assistant *assistant.Gslb
+	// heartbeats stores heartbeats instead of edge DNS, nil for edge DNS
+	heartbeats heartbeat.Backend
+	// plugin is created with the factory, so misconfigured plugin fails the startup
+	plugin *PluginProvider
 }
 
-func NewDNSProviderFactory(client client.Client, config depresolver.Config) (f *ProviderFactory, err error) {
+// NewDNSProviderFactory creates factory of DNS providers; metrics may be nil
+func NewDNSProviderFactory(client client.Client, config depresolver.Config, metrics *metrics.PrometheusMetrics) (f *ProviderFactory, err error) {
 	if client == nil {
 		err = fmt.Errorf("nil client")
 	}
 	f = &ProviderFactory{
-		config: config,
-		client: client,
+		config:    config,
+		client:    client,
+		metrics:   metrics,
+		assistant: assistant.NewGslbAssistant(client, config.K8gbNamespace, config.EdgeDNSServer, config.EdgeDNSServerPort, metrics),
+	}
+	if config.DNSSecurity.TSIGKeyName != "" {
+		f.assistant.UseTSIG(config.DNSSecurity.TSIGKeyName, config.DNSSecurity.TSIGAlgorithm, config.DNSSecurity.TSIGSecret)
+	}
+	f.assistant.VerifyHeartbeats(config.DNSSecurity.HeartbeatKey)
+	if config.Heartbeat.Backend == depresolver.HeartbeatBackendLease {
+		lease, leaseErr := newLeaseBackend(config)
+		if leaseErr != nil {
+			return f, leaseErr
+		}
+		f.heartbeats = lease
+		f.assistant.UseHeartbeatBackend(lease)
+	}
+	for _, t := range f.edgeDNSTypes() {
+		if t == depresolver.DNSTypePlugin {
+			plugin, pluginErr := NewPluginProvider(config, f.assistant)
+			if pluginErr != nil {
+				return f, pluginErr
+			}
+			f.plugin = plugin
+		}
 	}
 	return
 }
 
+// newLeaseBackend creates Lease heartbeat backend in the shared cluster of the kubeconfig. REST mappings are
+// discovered lazily, so the operator starts while the shared cluster is unavailable
+func newLeaseBackend(config depresolver.Config) (*heartbeat.Lease, error) {
+	restConfig, err := clientcmd.BuildConfigFromFlags("", config.Heartbeat.LeaseKubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("heartbeat lease kubeconfig: %w", err)
+	}
+	return heartbeat.NewLeaseForConfig(restConfig, config.Heartbeat.LeaseNamespace, config.ClusterGeoTag)
+}
+
+// Assistant returns assistant shared by providers of the factory, so results of their lookups can be inspected
+func (f *ProviderFactory) Assistant() *assistant.Gslb {
+	return f.assistant
+}
+
 func (f *ProviderFactory) Provider() Provider {
-	a := assistant.NewGslbAssistant(f.client, f.config.K8gbNamespace, f.config.EdgeDNSServer, f.config.EdgeDNSServerPort)
-	switch f.config.EdgeDNSType {
+	a := f.assistant
+	if f.config.EdgeDNSType == depresolver.DNSTypeMultipleProviders {
+		var providers []Provider
+		for _, t := range f.edgeDNSTypes() {
+			providers = append(providers, f.provider(t, a))
+		}
+		return NewMultiProvider(providers...)
+	}
+	return f.provider(f.config.EdgeDNSType, a)
+}
+
+// edgeDNSTypes returns types of providers created by the factory
+func (f *ProviderFactory) edgeDNSTypes() []depresolver.EdgeDNSType {
+	if f.config.EdgeDNSType == depresolver.DNSTypeMultipleProviders {
+		return f.config.GetEdgeDNSTypes()
+	}
+	return []depresolver.EdgeDNSType{f.config.EdgeDNSType}
+}
+
+func (f *ProviderFactory) provider(edgeDNSType depresolver.EdgeDNSType, a assistant.Assistant) Provider {
+	switch edgeDNSType {
 	case depresolver.DNSTypeNS1:
 		return NewExternalDNS(externalDNSTypeNS1, f.config, a)
 	case depresolver.DNSTypeRoute53:
 		return NewExternalDNS(externalDNSTypeRoute53, f.config, a)
 	case depresolver.DNSTypeInfoblox:
-		return NewInfobloxDNS(f.config, a)
+		p := NewInfobloxDNS(f.config, a, f.metrics)
+		p.heartbeats = f.heartbeats
+		return p
+	case depresolver.DNSTypePowerDNS:
+		p := NewPowerDNS(f.config, a)
+		p.heartbeats = f.heartbeats
+		return p
+	case depresolver.DNSTypePlugin:
+		return f.plugin
 	}
 	return NewEmptyDNS(f.config, a)
 }
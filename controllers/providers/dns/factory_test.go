@@ -19,9 +19,12 @@ package dns
 
 import (
 	"fmt"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
 	"github.com/AbsaOSS/k8gb/controllers/internal/utils"
 
 	"github.com/stretchr/testify/assert"
@@ -37,7 +40,7 @@ func TestFactoryInfoblox(t *testing.T) {
 	customConfig := predefinedConfig
 	customConfig.EdgeDNSType = depresolver.DNSTypeInfoblox
 	// act
-	f, err := NewDNSProviderFactory(client, customConfig)
+	f, err := NewDNSProviderFactory(client, customConfig, nil)
 	require.NoError(t, err)
 	provider := f.Provider()
 	// assert
@@ -52,7 +55,7 @@ func TestFactoryNS1(t *testing.T) {
 	customConfig := predefinedConfig
 	customConfig.EdgeDNSType = depresolver.DNSTypeNS1
 	// act
-	f, err := NewDNSProviderFactory(client, customConfig)
+	f, err := NewDNSProviderFactory(client, customConfig, nil)
 	require.NoError(t, err)
 	provider := f.Provider()
 	// assert
@@ -67,7 +70,7 @@ func TestFactoryRoute53(t *testing.T) {
 	customConfig := predefinedConfig
 	customConfig.EdgeDNSType = depresolver.DNSTypeRoute53
 	// act
-	f, err := NewDNSProviderFactory(client, customConfig)
+	f, err := NewDNSProviderFactory(client, customConfig, nil)
 	require.NoError(t, err)
 	provider := f.Provider()
 	// assert
@@ -76,13 +79,70 @@ func TestFactoryRoute53(t *testing.T) {
 	assert.Equal(t, "ROUTE53", fmt.Sprintf("%s", provider))
 }
 
+func TestFactoryPowerDNS(t *testing.T) {
+	// arrange
+	client := fake.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{}...)
+	customConfig := predefinedConfig
+	customConfig.EdgeDNSType = depresolver.DNSTypePowerDNS
+	// act
+	f, err := NewDNSProviderFactory(client, customConfig, nil)
+	require.NoError(t, err)
+	provider := f.Provider()
+	// assert
+	assert.NotNil(t, provider)
+	assert.Equal(t, "*PowerDNSProvider", utils.GetType(provider))
+	assert.Equal(t, "PowerDNS", fmt.Sprintf("%s", provider))
+}
+
+func TestFactoryPlugin(t *testing.T) {
+	// arrange
+	client := fake.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{}...)
+	customConfig := predefinedConfig
+	customConfig.EdgeDNSType = depresolver.DNSTypePlugin
+	customConfig.ProviderPlugin.Socket = "/var/run/k8gb/provider.sock"
+	// act
+	f, err := NewDNSProviderFactory(client, customConfig, nil)
+	require.NoError(t, err)
+	provider := f.Provider()
+	// assert
+	assert.Equal(t, "*PluginProvider", utils.GetType(provider))
+	assert.Equal(t, "Plugin", fmt.Sprintf("%s", provider))
+}
+
+func TestFactoryFailsOnInvalidPlugin(t *testing.T) {
+	// arrange
+	client := fake.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{}...)
+	customConfig := predefinedConfig
+	customConfig.EdgeDNSType = depresolver.DNSTypePlugin
+	customConfig.ProviderPlugin.Socket = ""
+	// act
+	_, err := NewDNSProviderFactory(client, customConfig, nil)
+	// assert
+	require.Error(t, err)
+}
+
+func TestFactoryMultipleProviders(t *testing.T) {
+	// arrange
+	client := fake.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{}...)
+	customConfig := predefinedConfig
+	customConfig.EdgeDNSType = depresolver.DNSTypeMultipleProviders
+	customConfig.PowerDNS.APIURL = "http://pdns.example.com:8081"
+	// act
+	f, err := NewDNSProviderFactory(client, customConfig, nil)
+	require.NoError(t, err)
+	provider := f.Provider()
+	// assert
+	assert.Equal(t, "*MultiProvider", utils.GetType(provider))
+	assert.Equal(t, "Infoblox,PowerDNS", fmt.Sprintf("%s", provider))
+}
+
 func TestFactoryNoEdgeDNS(t *testing.T) {
 	// arrange
 	client := fake.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{}...)
 	customConfig := predefinedConfig
 	customConfig.EdgeDNSType = depresolver.DNSTypeNoEdgeDNS
 	// act
-	f, err := NewDNSProviderFactory(client, customConfig)
+	f, err := NewDNSProviderFactory(client, customConfig, nil)
 	require.NoError(t, err)
 	provider := f.Provider()
 	// assert
@@ -96,6 +156,55 @@ func TestFactoryNilClient(t *testing.T) {
 	customConfig.EdgeDNSType = depresolver.DNSTypeNoEdgeDNS
 	// act
 	// assert
-	_, err := NewDNSProviderFactory(nil, customConfig)
+	_, err := NewDNSProviderFactory(nil, customConfig, nil)
 	require.Error(t, err)
 }
+
+func TestFactoryLeaseHeartbeats(t *testing.T) {
+	// arrange
+	client := fake.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{}...)
+	kubeconfig := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, ioutil.WriteFile(kubeconfig, []byte(hubKubeconfig), 0600))
+	customConfig := predefinedConfig
+	customConfig.EdgeDNSType = depresolver.DNSTypePowerDNS
+	customConfig.Heartbeat = depresolver.Heartbeat{Backend: depresolver.HeartbeatBackendLease, LeaseKubeconfig: kubeconfig,
+		LeaseNamespace: "k8gb"}
+	// act
+	f, err := NewDNSProviderFactory(client, customConfig, nil)
+	require.NoError(t, err)
+	provider := f.Provider()
+	// assert
+	assert.IsType(t, &heartbeat.Lease{}, provider.(*PowerDNSProvider).heartbeats)
+}
+
+func TestFactoryLeaseHeartbeatsWithoutKubeconfig(t *testing.T) {
+	// arrange
+	client := fake.NewFakeClientWithScheme(scheme.Scheme, []runtime.Object{}...)
+	customConfig := predefinedConfig
+	customConfig.EdgeDNSType = depresolver.DNSTypePowerDNS
+	customConfig.Heartbeat = depresolver.Heartbeat{Backend: depresolver.HeartbeatBackendLease,
+		LeaseKubeconfig: filepath.Join(t.TempDir(), "missing"), LeaseNamespace: "k8gb"}
+	// act
+	_, err := NewDNSProviderFactory(client, customConfig, nil)
+	// assert
+	assert.Error(t, err)
+}
+
+// hubKubeconfig points to shared cluster which isn't running; the backend must be created anyway
+const hubKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: hub
+  cluster:
+    server: https://127.0.0.1:1
+contexts:
+- name: hub
+  context:
+    cluster: hub
+    user: k8gb
+current-context: hub
+users:
+- name: k8gb
+  user:
+    token: token
+`
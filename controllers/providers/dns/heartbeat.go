@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+)
+
+// staleNSNames returns nameservers of external clusters which didn't refresh heartbeat of any Gslb within
+// its split brain threshold. Nothing is stale when split brain check is disabled. In quorum mode, the nameserver is
+// returned only when majority of clusters consider the cluster stale. The view of this cluster, geo tags of external
+// clusters which look stale regardless of quorum, is returned as well, so it can be published in heartbeats
+func staleNSNames(config depresolver.Config, a assistant.Assistant, gslbs []*k8gbv1beta1.Gslb) (stale map[string]bool, view []string) {
+	stale = make(map[string]bool)
+	if !config.SplitBrainCheck {
+		return stale, nil
+	}
+	nsNames := config.GetExternalClusterNSNames()
+	alive := make(map[string]heartbeat.Heartbeat)
+	for geoTag, nsServerNameExt := range nsNames {
+		var h heartbeat.Heartbeat
+		var err error
+		for _, gslb := range gslbs {
+			h, err = a.InspectHeartbeat(config.GetExternalClusterHeartbeatFQDNs(gslb.Name)[geoTag],
+				time.Second*time.Duration(gslb.Spec.Strategy.SplitBrainThresholdSeconds))
+			if err == nil {
+				break
+			}
+		}
+		if err != nil {
+			log.Err(err).Msgf("Got the error from TXT based checkAlive. External cluster (%s) doesn't look alive", nsServerNameExt)
+			view = append(view, geoTag)
+			continue
+		}
+		alive[geoTag] = h
+	}
+	sort.Strings(view)
+	for _, geoTag := range view {
+		if config.SplitBrainQuorum {
+			votes, clusters := staleVotes(config, geoTag, alive)
+			if votes*2 <= clusters {
+				log.Info().Msgf("External cluster (%s) is kept in delegated zone configuration, only %d of %d clusters "+
+					"consider it stale", nsNames[geoTag], votes, clusters)
+				continue
+			}
+		}
+		log.Info().Msgf("Filtering external cluster (%s) out from delegated zone configuration...", nsNames[geoTag])
+		stale[nsNames[geoTag]] = true
+	}
+	return stale, view
+}
+
+// staleVotes returns number of clusters which consider cluster geoTag stale out of all clusters. This cluster votes
+// for it; alive external clusters vote by the view published in their heartbeat. Stale clusters don't vote, so
+// a cluster which can't read heartbeats of others never reaches the majority alone
+func staleVotes(config depresolver.Config, geoTag string, alive map[string]heartbeat.Heartbeat) (votes, clusters int) {
+	votes = 1
+	for _, h := range alive {
+		for _, s := range h.Stale {
+			if s == geoTag {
+				votes++
+				break
+			}
+		}
+	}
+	return votes, len(config.ExtClustersGeoTags) + 1
+}
+
+// heartbeatPayload returns content of heartbeat TXT record fqdn of gslb written now by this cluster. stale is the view
+// of this cluster on external clusters
+func heartbeatPayload(config depresolver.Config, gslb *k8gbv1beta1.Gslb, fqdn string, stale []string) string {
+	h := heartbeat.Heartbeat{
+		Timestamp:       time.Now(),
+		GeoTag:          config.ClusterGeoTag,
+		OperatorVersion: config.K8gbVersion,
+		Health:          healthSummary(gslb.Status.ServiceHealth),
+		Stale:           stale,
+	}
+	return heartbeat.Payload(fqdn, h, config.DNSSecurity.HeartbeatKey)
+}
+
+// healthSummary returns number of healthy hosts out of all hosts, e.g. 2/3; empty when Gslb has no hosts yet
+func healthSummary(serviceHealth map[string]string) string {
+	if len(serviceHealth) == 0 {
+		return ""
+	}
+	healthy := 0
+	for _, health := range serviceHealth {
+		if health == "Healthy" {
+			healthy++
+		}
+	}
+	return fmt.Sprintf("%d/%d", healthy, len(serviceHealth))
+}
+
+// saveBackendHeartbeat writes heartbeat of gslb to the heartbeat backend, which expires with split brain threshold
+func saveBackendHeartbeat(config depresolver.Config, backend heartbeat.Backend, gslb *k8gbv1beta1.Gslb, view []string) error {
+	fqdn := config.GetClusterHeartbeatFQDN(gslb.Name)
+	log.Info().Str("Heartbeat", fqdn).Msg("Renewing split brain heartbeat in backend")
+	return backend.Write(context.TODO(), fqdn, heartbeatPayload(config, gslb, fqdn, view),
+		time.Second*time.Duration(gslb.Spec.Strategy.SplitBrainThresholdSeconds))
+}
+
+// deleteBackendHeartbeats removes heartbeats of gslbs from the heartbeat backend
+func deleteBackendHeartbeats(config depresolver.Config, backend heartbeat.Backend, gslbs []*k8gbv1beta1.Gslb) error {
+	for _, gslb := range gslbs {
+		fqdn := config.GetClusterHeartbeatFQDN(gslb.Name)
+		log.Info().Msgf("Deleting split brain heartbeat(%s) from backend...", fqdn)
+		if err := backend.Delete(context.TODO(), fqdn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
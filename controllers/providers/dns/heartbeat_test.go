@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	euNS = "gslb-ns-eu-cloud.example.com"
+	zaNS = "gslb-ns-za-cloud.example.com"
+)
+
+func TestStaleClusterIsRemovedWithoutQuorum(t *testing.T) {
+	// arrange
+	config := splitBrainConfig(false)
+	m := mockHeartbeats(t, config, nil, nil)
+	// act
+	stale, view := staleNSNames(config, m, delegation().Gslbs)
+	// assert
+	assert.Equal(t, map[string]bool{euNS: true, zaNS: true}, stale)
+	assert.Equal(t, []string{"eu", "za"}, view)
+}
+
+func TestStaleClusterIsRemovedByMajority(t *testing.T) {
+	// arrange
+	config := splitBrainConfig(true)
+	m := mockHeartbeats(t, config, &heartbeat.Heartbeat{GeoTag: "eu", Stale: []string{"za"}}, nil)
+	// act
+	stale, view := staleNSNames(config, m, delegation().Gslbs)
+	// assert
+	assert.Equal(t, map[string]bool{zaNS: true}, stale)
+	assert.Equal(t, []string{"za"}, view)
+}
+
+func TestStaleClusterIsKeptWithoutMajority(t *testing.T) {
+	// arrange
+	config := splitBrainConfig(true)
+	m := mockHeartbeats(t, config, &heartbeat.Heartbeat{GeoTag: "eu"}, nil)
+	// act
+	stale, view := staleNSNames(config, m, delegation().Gslbs)
+	// assert
+	assert.Empty(t, stale)
+	assert.Equal(t, []string{"za"}, view)
+}
+
+func TestIsolatedClusterRemovesNobodyWithQuorum(t *testing.T) {
+	// arrange
+	config := splitBrainConfig(true)
+	m := mockHeartbeats(t, config, nil, nil)
+	// act
+	stale, view := staleNSNames(config, m, delegation().Gslbs)
+	// assert
+	assert.Empty(t, stale, "single vote of this cluster isn't majority")
+	assert.Equal(t, []string{"eu", "za"}, view)
+}
+
+func TestStaleClusterDoesNotVote(t *testing.T) {
+	// arrange
+	config := splitBrainConfig(true)
+	config.ExtClustersGeoTags = []string{"za", "eu", "uk"}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	heartbeats := config.GetExternalClusterHeartbeatFQDNs(a.Gslb.Name)
+	m.EXPECT().InspectHeartbeat(heartbeats["eu"], gomock.Any()).Return(heartbeat.Heartbeat{Stale: []string{"za"}}, nil)
+	m.EXPECT().InspectHeartbeat(heartbeats["za"], gomock.Any()).Return(heartbeat.Heartbeat{}, fmt.Errorf("expired"))
+	m.EXPECT().InspectHeartbeat(heartbeats["uk"], gomock.Any()).Return(heartbeat.Heartbeat{}, fmt.Errorf("expired"))
+	// act
+	stale, view := staleNSNames(config, m, delegation().Gslbs)
+	// assert
+	assert.Empty(t, stale, "2 of 4 clusters isn't majority")
+	assert.Equal(t, []string{"uk", "za"}, view)
+}
+
+func TestPowerDNSWritesHeartbeatToLease(t *testing.T) {
+	// arrange
+	pdns := newFakePowerDNS(a.Config.EdgeDNSZone)
+	defer pdns.Close()
+	config := powerDNSConfig(pdns.URL())
+	config.SplitBrainCheck = true
+	config.Heartbeat.Backend = depresolver.HeartbeatBackendLease
+	fqdn := config.GetClusterHeartbeatFQDN(a.Gslb.Name)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	m.EXPECT().InspectHeartbeat(gomock.Any(), gomock.Any()).Return(heartbeat.Heartbeat{}, nil).AnyTimes()
+	leases := heartbeat.NewLease(fake.NewFakeClientWithScheme(scheme.Scheme), "k8gb", config.ClusterGeoTag)
+	p := NewPowerDNS(config, m)
+	p.heartbeats = leases
+	// act
+	err := p.CreateZoneDelegationForExternalDNS(delegation())
+	// assert
+	require.NoError(t, err)
+	assert.Empty(t, pdns.content(a.Config.EdgeDNSZone, fqdn, "TXT"), "heartbeat isn't written to edge DNS")
+	payloads, err := leases.Read(context.TODO(), fqdn)
+	require.NoError(t, err)
+	require.Len(t, payloads, 1)
+	written, err := heartbeat.Parse(fqdn, payloads[0], "")
+	assert.NoError(t, err)
+	assert.Equal(t, config.ClusterGeoTag, written.GeoTag)
+}
+
+func TestPowerDNSRemovesLeaseOfReleasedGslb(t *testing.T) {
+	// arrange
+	pdns := newFakePowerDNS(a.Config.EdgeDNSZone)
+	defer pdns.Close()
+	config := powerDNSConfig(pdns.URL())
+	config.Heartbeat.Backend = depresolver.HeartbeatBackendLease
+	fqdn := config.GetClusterHeartbeatFQDN(a.Gslb.Name)
+	leases := heartbeat.NewLease(fake.NewFakeClientWithScheme(scheme.Scheme), "k8gb", config.ClusterGeoTag)
+	require.NoError(t, leases.Write(context.TODO(), fqdn, "v=1;ts=2021-06-10T22:14:03Z", time.Minute))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	p := NewPowerDNS(config, assistant.NewMockAssistant(ctrl))
+	p.heartbeats = leases
+	// act
+	err := p.Finalize(&ZoneDelegation{TTL: 30, Released: []*k8gbv1beta1.Gslb{a.Gslb}})
+	// assert
+	require.NoError(t, err)
+	payloads, err := leases.Read(context.TODO(), fqdn)
+	assert.NoError(t, err)
+	assert.Empty(t, payloads)
+}
+
+func splitBrainConfig(quorum bool) depresolver.Config {
+	config := a.Config
+	config.SplitBrainCheck = true
+	config.SplitBrainQuorum = quorum
+	return config
+}
+
+// mockHeartbeats returns assistant serving heartbeats of eu and za clusters; nil heartbeat is expired
+func mockHeartbeats(t *testing.T, config depresolver.Config, eu, za *heartbeat.Heartbeat) assistant.Assistant {
+	t.Helper()
+	ctrl := gomock.NewController(t)
+	t.Cleanup(ctrl.Finish)
+	m := assistant.NewMockAssistant(ctrl)
+	heartbeats := config.GetExternalClusterHeartbeatFQDNs(a.Gslb.Name)
+	for geoTag, h := range map[string]*heartbeat.Heartbeat{"eu": eu, "za": za} {
+		if h == nil {
+			m.EXPECT().InspectHeartbeat(heartbeats[geoTag], gomock.Any()).Return(heartbeat.Heartbeat{}, fmt.Errorf("expired"))
+			continue
+		}
+		m.EXPECT().InspectHeartbeat(heartbeats[geoTag], gomock.Any()).Return(*h, nil)
+	}
+	return m
+}
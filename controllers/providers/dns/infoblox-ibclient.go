@@ -18,49 +18,94 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package dns
 
 import (
+	"crypto/x509"
 	"fmt"
+	"io/ioutil"
 	"strconv"
+	"strings"
 
 	ibclient "github.com/infobloxopen/infoblox-go-client"
 )
 
-func (p *InfobloxProvider) infobloxConnection() (*ibclient.ObjectManager, error) {
+// connect creates WAPI connector. NewConnector validates the connector by reading user profile, so the
+// returned connector is authenticated.
+func (p *InfobloxProvider) connect() (ibclient.IBConnector, error) {
+	if p.config.Override.FakeInfobloxEnabled {
+		fqdn := "fakezone.example.com"
+		fakeRefReturn := "zone_delegated/ZG5zLnpvbmUkLl9kZWZhdWx0LnphLmNvLmFic2EuY2Fhcy5vaG15Z2xiLmdzbGJpYmNsaWVudA:fakezone.example.com/default"
+		return &fakeInfobloxConnector{
+			getObjectObj: ibclient.NewZoneDelegated(ibclient.ZoneDelegated{Fqdn: fqdn}),
+			getObjectRef: "",
+			resultObject: []ibclient.ZoneDelegated{*ibclient.NewZoneDelegated(ibclient.ZoneDelegated{Fqdn: fqdn, Ref: fakeRefReturn})},
+		}, nil
+	}
+	username, password, err := p.credentials()
+	if err != nil {
+		return nil, err
+	}
+	sslVerify, err := p.sslVerify()
+	if err != nil {
+		return nil, err
+	}
 	hostConfig := ibclient.HostConfig{
 		Host:     p.config.Infoblox.Host,
 		Version:  p.config.Infoblox.Version,
 		Port:     strconv.Itoa(p.config.Infoblox.Port),
-		Username: p.config.Infoblox.Username,
-		Password: p.config.Infoblox.Password,
+		Username: username,
+		Password: password,
 	}
-	transportConfig := ibclient.NewTransportConfig("false", p.config.Infoblox.HTTPRequestTimeout, p.config.Infoblox.HTTPPoolConnections)
+	transportConfig := ibclient.NewTransportConfig(sslVerify, p.config.Infoblox.HTTPRequestTimeout, p.config.Infoblox.HTTPPoolConnections)
 	requestBuilder := &ibclient.WapiRequestBuilder{}
 	requestor := &ibclient.WapiHttpRequestor{}
+	conn, err := ibclient.NewConnector(hostConfig, transportConfig, requestBuilder, requestor)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
 
-	var objMgr *ibclient.ObjectManager
+// credentials returns WAPI username and password. Mounted secret files take precedence over environment
+// variables. The files are read whenever a connection is created, so rotated secret is picked up
+// without restarting the operator.
+func (p *InfobloxProvider) credentials() (username, password string, err error) {
+	username, err = readSecretFile(p.config.Infoblox.UsernameFile, p.config.Infoblox.Username)
+	if err != nil {
+		return
+	}
+	password, err = readSecretFile(p.config.Infoblox.PasswordFile, p.config.Infoblox.Password)
+	return
+}
 
-	if p.config.Override.FakeInfobloxEnabled {
-		fqdn := "fakezone.example.com"
-		fakeRefReturn := "zone_delegated/ZG5zLnpvbmUkLl9kZWZhdWx0LnphLmNvLmFic2EuY2Fhcy5vaG15Z2xiLmdzbGJpYmNsaWVudA:fakezone.example.com/default"
-		k8gbFakeConnector := &fakeInfobloxConnector{
-			getObjectObj: ibclient.NewZoneDelegated(ibclient.ZoneDelegated{Fqdn: fqdn}),
-			getObjectRef: "",
-			resultObject: []ibclient.ZoneDelegated{*ibclient.NewZoneDelegated(ibclient.ZoneDelegated{Fqdn: fqdn, Ref: fakeRefReturn})},
-		}
-		objMgr = ibclient.NewObjectManager(k8gbFakeConnector, "k8gbclient", "")
-	} else {
-		conn, err := ibclient.NewConnector(hostConfig, transportConfig, requestBuilder, requestor)
-		if err != nil {
-			return nil, err
-		}
-		defer func() {
-			err = conn.Logout()
-			if err != nil {
-				log.Err(err).Msg("Failed to close connection to infoblox")
-			}
-		}()
-		objMgr = ibclient.NewObjectManager(conn, "k8gbclient", "")
+// sslVerify returns value understood by ibclient.NewTransportConfig; "true", "false" or path to CA bundle.
+// ibclient silently disables verification when CA bundle can't be loaded, so the bundle is checked upfront.
+func (p *InfobloxProvider) sslVerify() (string, error) {
+	if !p.config.Infoblox.SSLVerify {
+		log.Warn().Msg("Infoblox certificate verification is disabled")
+		return "false", nil
+	}
+	if p.config.Infoblox.CABundle == "" {
+		return "true", nil
+	}
+	pem, err := ioutil.ReadFile(p.config.Infoblox.CABundle)
+	if err != nil {
+		return "", fmt.Errorf("reading infoblox CA bundle: %w", err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(pem) {
+		return "", fmt.Errorf("infoblox CA bundle %s doesn't contain any PEM encoded certificate", p.config.Infoblox.CABundle)
+	}
+	return p.config.Infoblox.CABundle, nil
+}
+
+// readSecretFile returns content of the file without trailing new line, or fallback if the path is empty
+func readSecretFile(path, fallback string) (string, error) {
+	if path == "" {
+		return fallback, nil
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading secret file: %w", err)
 	}
-	return objMgr, nil
+	return strings.TrimRight(string(data), "\r\n"), nil
 }
 
 func (p *InfobloxProvider) checkZoneDelegated(findZone *ibclient.ZoneDelegated) error {
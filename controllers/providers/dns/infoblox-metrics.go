@@ -0,0 +1,58 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"time"
+
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client"
+)
+
+const infobloxProvider = "infoblox"
+
+// instrumentedConnector records latency and errors of WAPI calls
+type instrumentedConnector struct {
+	ibclient.IBConnector
+	metrics *metrics.PrometheusMetrics
+}
+
+func (c *instrumentedConnector) CreateObject(obj ibclient.IBObject) (ref string, err error) {
+	defer c.observe("create", time.Now(), &err)
+	return c.IBConnector.CreateObject(obj)
+}
+
+func (c *instrumentedConnector) GetObject(obj ibclient.IBObject, ref string, res interface{}) (err error) {
+	defer c.observe("get", time.Now(), &err)
+	return c.IBConnector.GetObject(obj, ref, res)
+}
+
+func (c *instrumentedConnector) DeleteObject(ref string) (refRes string, err error) {
+	defer c.observe("delete", time.Now(), &err)
+	return c.IBConnector.DeleteObject(ref)
+}
+
+func (c *instrumentedConnector) UpdateObject(obj ibclient.IBObject, ref string) (refRes string, err error) {
+	defer c.observe("update", time.Now(), &err)
+	return c.IBConnector.UpdateObject(obj, ref)
+}
+
+func (c *instrumentedConnector) observe(operation string, start time.Time, err *error) {
+	c.metrics.ObserveProviderRequest(infobloxProvider, operation, start, *err)
+}
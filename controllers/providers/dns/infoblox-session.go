@@ -0,0 +1,200 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client"
+)
+
+// infobloxSession shares single WAPI connector among all Gslbs. The connector is health-checked when it was idle
+// longer than healthCheckInterval and it is re-created when the grid rejects the credentials. Re-creating
+// the connector re-reads the credentials, so rotated secret is picked up.
+type infobloxSession struct {
+	sync.Mutex
+	connect             func() (ibclient.IBConnector, error)
+	connection          *infobloxConnection
+	lastUsed            time.Time
+	healthCheckInterval time.Duration
+}
+
+// infobloxConnection counts calls using the connector, so it isn't logged out while any of them is in progress
+type infobloxConnection struct {
+	connector ibclient.IBConnector
+	users     int
+	closed    bool
+}
+
+func newInfobloxSession(connect func() (ibclient.IBConnector, error), healthCheckInterval time.Duration) *infobloxSession {
+	return &infobloxSession{
+		connect:             connect,
+		healthCheckInterval: healthCheckInterval,
+	}
+}
+
+// do runs fn with object manager of the session. If the grid rejects the session, fn is retried once
+// with newly authenticated connector.
+func (s *infobloxSession) do(fn func(objMgr *ibclient.ObjectManager) error) error {
+	connection, err := s.run(fn)
+	if !isUnauthorized(err) {
+		return err
+	}
+	log.Info().Msg("Infoblox session was rejected, re-authenticating")
+	s.Lock()
+	// concurrent call rejected by the grid might have re-authenticated already
+	if s.connection == connection {
+		s.close()
+	}
+	s.Unlock()
+	_, err = s.run(fn)
+	return err
+}
+
+// run calls fn with object manager of the session connector and returns the connection used by fn
+func (s *infobloxSession) run(fn func(objMgr *ibclient.ObjectManager) error) (*infobloxConnection, error) {
+	connection, err := s.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer s.release(connection)
+	return connection, fn(ibclient.NewObjectManager(connection.connector, "k8gbclient", ""))
+}
+
+// acquire returns connection of the session, which must be released once it isn't used
+func (s *infobloxSession) acquire() (*infobloxConnection, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.connection != nil && time.Since(s.lastUsed) > s.healthCheckInterval {
+		var profile []ibclient.UserProfile
+		if err := s.connection.connector.GetObject(ibclient.NewUserProfile(ibclient.UserProfile{}), "", &profile); err != nil {
+			log.Err(err).Msg("Infoblox session health check failed, reconnecting")
+			s.close()
+		}
+	}
+	if s.connection == nil {
+		connector, err := s.connect()
+		if err != nil {
+			return nil, err
+		}
+		s.connection = &infobloxConnection{connector: connector}
+	}
+	s.lastUsed = time.Now()
+	s.connection.users++
+	return s.connection, nil
+}
+
+// release logs out from closed connection once its last user is done
+func (s *infobloxSession) release(connection *infobloxConnection) {
+	s.Lock()
+	defer s.Unlock()
+	connection.users--
+	if connection.closed && connection.users == 0 {
+		connection.logout()
+	}
+}
+
+// close drops the connector, it is logged out immediately or by the last call using it; must be called under lock
+func (s *infobloxSession) close() {
+	if s.connection == nil {
+		return
+	}
+	s.connection.closed = true
+	if s.connection.users == 0 {
+		s.connection.logout()
+	}
+	s.connection = nil
+}
+
+func (c *infobloxConnection) logout() {
+	if l, ok := c.connector.(interface{ Logout() error }); ok {
+		if err := l.Logout(); err != nil {
+			log.Err(err).Msg("Failed to close connection to infoblox")
+		}
+	}
+}
+
+// isUnauthorized recognizes WAPI authentication failure. ibclient returns the HTTP status in error text only.
+func isUnauthorized(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "WAPI request error: 401")
+}
+
+// zoneCache coalesces delegated zone reads of all Gslbs. A zone read within ttl is served from the cache
+// and concurrent reads of the same zone wait for the read in flight.
+type zoneCache struct {
+	sync.Mutex
+	ttl     time.Duration
+	entries map[string]*zoneCacheEntry
+}
+
+type zoneCacheEntry struct {
+	zone    *ibclient.ZoneDelegated
+	err     error
+	fetched time.Time
+	done    chan struct{}
+}
+
+func newZoneCache(ttl time.Duration) *zoneCache {
+	return &zoneCache{
+		ttl:     ttl,
+		entries: make(map[string]*zoneCacheEntry),
+	}
+}
+
+// get returns copy of cached zone, so callers may sort or modify the delegation list
+func (c *zoneCache) get(fqdn string, read func() (*ibclient.ZoneDelegated, error)) (*ibclient.ZoneDelegated, error) {
+	c.Lock()
+	if e, found := c.entries[fqdn]; found {
+		select {
+		case <-e.done:
+			if e.err == nil && time.Since(e.fetched) < c.ttl {
+				c.Unlock()
+				return copyZone(e.zone), nil
+			}
+		default:
+			c.Unlock()
+			<-e.done
+			return copyZone(e.zone), e.err
+		}
+	}
+	e := &zoneCacheEntry{done: make(chan struct{})}
+	c.entries[fqdn] = e
+	c.Unlock()
+	e.zone, e.err = read()
+	e.fetched = time.Now()
+	close(e.done)
+	return copyZone(e.zone), e.err
+}
+
+// invalidate drops cached zone; called whenever k8gb changes the zone
+func (c *zoneCache) invalidate(fqdn string) {
+	c.Lock()
+	defer c.Unlock()
+	delete(c.entries, fqdn)
+}
+
+func copyZone(zone *ibclient.ZoneDelegated) *ibclient.ZoneDelegated {
+	if zone == nil {
+		return nil
+	}
+	z := *zone
+	z.DelegateTo = append([]ibclient.NameServer(nil), zone.DelegateTo...)
+	return &z
+}
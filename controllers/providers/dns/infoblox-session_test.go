@@ -0,0 +1,265 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	ibclient "github.com/infobloxopen/infoblox-go-client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sessionConnector counts health checks and logouts of the session
+type sessionConnector struct {
+	fakeInfobloxConnector
+	healthy   bool
+	checks    int
+	loggedOut bool
+}
+
+func (c *sessionConnector) GetObject(ibclient.IBObject, string, interface{}) error {
+	c.checks++
+	if !c.healthy {
+		return fmt.Errorf("connection refused")
+	}
+	return nil
+}
+
+func (c *sessionConnector) Logout() error {
+	c.loggedOut = true
+	return nil
+}
+
+type connectorFactory struct {
+	connectors []*sessionConnector
+}
+
+func (f *connectorFactory) connect() (ibclient.IBConnector, error) {
+	c := &sessionConnector{healthy: true}
+	f.connectors = append(f.connectors, c)
+	return c, nil
+}
+
+func TestInfobloxSessionIsReused(t *testing.T) {
+	// arrange
+	f := &connectorFactory{}
+	s := newInfobloxSession(f.connect, time.Minute)
+	// act
+	for i := 0; i < 10; i++ {
+		require.NoError(t, s.do(func(*ibclient.ObjectManager) error { return nil }))
+	}
+	// assert
+	assert.Len(t, f.connectors, 1)
+	assert.Equal(t, 0, f.connectors[0].checks)
+	assert.False(t, f.connectors[0].loggedOut)
+}
+
+func TestInfobloxSessionReauthenticatesWhenRejected(t *testing.T) {
+	// arrange
+	f := &connectorFactory{}
+	s := newInfobloxSession(f.connect, time.Minute)
+	calls := 0
+	// act
+	err := s.do(func(*ibclient.ObjectManager) error {
+		calls++
+		if calls == 1 {
+			return fmt.Errorf("WAPI request error: 401('401 Authorization Required')")
+		}
+		return nil
+	})
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+	require.Len(t, f.connectors, 2)
+	assert.True(t, f.connectors[0].loggedOut)
+}
+
+func TestInfobloxSessionReconnectsWhenHealthCheckFails(t *testing.T) {
+	// arrange
+	f := &connectorFactory{}
+	s := newInfobloxSession(f.connect, 0)
+	require.NoError(t, s.do(func(*ibclient.ObjectManager) error { return nil }))
+	f.connectors[0].healthy = false
+	// act
+	err := s.do(func(*ibclient.ObjectManager) error { return nil })
+	// assert
+	require.NoError(t, err)
+	require.Len(t, f.connectors, 2)
+	assert.Equal(t, 1, f.connectors[0].checks)
+	assert.True(t, f.connectors[0].loggedOut)
+}
+
+func TestInfobloxSessionIsNotLoggedOutWhileInUse(t *testing.T) {
+	// arrange
+	f := &connectorFactory{}
+	s := newInfobloxSession(f.connect, time.Minute)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan error)
+	go func() {
+		done <- s.do(func(*ibclient.ObjectManager) error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	calls := 0
+	// act
+	err := s.do(func(*ibclient.ObjectManager) error {
+		calls++
+		if calls == 1 {
+			return fmt.Errorf("WAPI request error: 401('401 Authorization Required')")
+		}
+		return nil
+	})
+	loggedOutInUse := f.connectors[0].loggedOut
+	close(release)
+	inUseErr := <-done
+	// assert
+	require.NoError(t, err)
+	require.NoError(t, inUseErr)
+	require.Len(t, f.connectors, 2)
+	assert.False(t, loggedOutInUse)
+	assert.True(t, f.connectors[0].loggedOut)
+	assert.False(t, f.connectors[1].loggedOut)
+}
+
+func TestInfobloxSessionRejectedConcurrentlyReauthenticatesOnce(t *testing.T) {
+	// arrange
+	f := &connectorFactory{}
+	s := newInfobloxSession(f.connect, time.Minute)
+	var rejected, wg sync.WaitGroup
+	rejected.Add(2)
+	errs := make([]error, 2)
+	// act
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			calls := 0
+			errs[i] = s.do(func(*ibclient.ObjectManager) error {
+				calls++
+				if calls == 1 {
+					// both calls use the first connector before any of them re-authenticates
+					rejected.Done()
+					rejected.Wait()
+					return fmt.Errorf("WAPI request error: 401('401 Authorization Required')")
+				}
+				return nil
+			})
+		}(i)
+	}
+	wg.Wait()
+	// assert
+	assert.NoError(t, errs[0])
+	assert.NoError(t, errs[1])
+	require.Len(t, f.connectors, 2)
+	assert.True(t, f.connectors[0].loggedOut)
+	assert.False(t, f.connectors[1].loggedOut)
+}
+
+func TestInfobloxSessionDoesntRetryOtherErrors(t *testing.T) {
+	// arrange
+	f := &connectorFactory{}
+	s := newInfobloxSession(f.connect, time.Minute)
+	calls := 0
+	// act
+	err := s.do(func(*ibclient.ObjectManager) error {
+		calls++
+		return fmt.Errorf("WAPI request error: 400('400 Bad Request')")
+	})
+	// assert
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+	assert.Len(t, f.connectors, 1)
+}
+
+func TestZoneCacheCoalescesConcurrentReads(t *testing.T) {
+	// arrange
+	var reads int32
+	release := make(chan struct{})
+	c := newZoneCache(time.Minute)
+	read := func() (*ibclient.ZoneDelegated, error) {
+		atomic.AddInt32(&reads, 1)
+		<-release
+		return &ibclient.ZoneDelegated{Fqdn: "cloud.example.com", DelegateTo: []ibclient.NameServer{{Address: "10.0.0.1"}}}, nil
+	}
+	// act
+	var wg sync.WaitGroup
+	zones := make([]*ibclient.ZoneDelegated, 20)
+	for i := range zones {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			zones[i], _ = c.get("cloud.example.com", read)
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	cached, err := c.get("cloud.example.com", read)
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reads))
+	for _, z := range zones {
+		assert.Equal(t, cached, z)
+	}
+	// callers get own copy
+	cached.DelegateTo[0].Address = "10.0.0.2"
+	again, _ := c.get("cloud.example.com", read)
+	assert.Equal(t, "10.0.0.1", again.DelegateTo[0].Address)
+}
+
+func TestZoneCacheIsInvalidated(t *testing.T) {
+	// arrange
+	reads := 0
+	c := newZoneCache(time.Minute)
+	read := func() (*ibclient.ZoneDelegated, error) {
+		reads++
+		return nil, nil
+	}
+	_, _ = c.get("cloud.example.com", read)
+	// act
+	c.invalidate("cloud.example.com")
+	_, _ = c.get("cloud.example.com", read)
+	// assert
+	assert.Equal(t, 2, reads)
+}
+
+func TestZoneCacheDoesntKeepErrors(t *testing.T) {
+	// arrange
+	reads := 0
+	c := newZoneCache(time.Minute)
+	read := func() (*ibclient.ZoneDelegated, error) {
+		reads++
+		return nil, fmt.Errorf("connection refused")
+	}
+	// act
+	_, err1 := c.get("cloud.example.com", read)
+	_, err2 := c.get("cloud.example.com", read)
+	// assert
+	assert.Error(t, err1)
+	assert.Error(t, err2)
+	assert.Equal(t, 2, reads)
+}
@@ -18,29 +18,50 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package dns
 
 import (
-	"fmt"
+	"context"
 	"reflect"
 	"time"
 
 	externaldns "sigs.k8s.io/external-dns/endpoint"
 
 	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
 
 	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
 	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
 	ibclient "github.com/infobloxopen/infoblox-go-client"
 )
 
 type InfobloxProvider struct {
 	assistant assistant.Assistant
 	config    depresolver.Config
+	session   *infobloxSession
+	zones     *zoneCache
+	metrics   *metrics.PrometheusMetrics
+	// heartbeats stores heartbeats instead of edge DNS when set
+	heartbeats heartbeat.Backend
 }
 
-func NewInfobloxDNS(config depresolver.Config, assistant assistant.Assistant) *InfobloxProvider {
-	return &InfobloxProvider{
+// NewInfobloxDNS creates Infoblox provider; metrics may be nil
+func NewInfobloxDNS(config depresolver.Config, assistant assistant.Assistant, metrics *metrics.PrometheusMetrics) *InfobloxProvider {
+	interval := time.Duration(config.ReconcileRequeueSeconds) * time.Second
+	p := &InfobloxProvider{
 		assistant: assistant,
 		config:    config,
+		zones:     newZoneCache(interval),
+		metrics:   metrics,
 	}
+	p.session = newInfobloxSession(p.instrumentedConnect, interval)
+	return p
+}
+
+func (p *InfobloxProvider) instrumentedConnect() (ibclient.IBConnector, error) {
+	connector, err := p.connect()
+	if err != nil {
+		return nil, err
+	}
+	return &instrumentedConnector{IBConnector: connector, metrics: p.metrics}, nil
 }
 
 func (p *InfobloxProvider) sanitizeDelegateZone(local, upstream []ibclient.NameServer) []ibclient.NameServer {
@@ -54,23 +75,21 @@ func (p *InfobloxProvider) sanitizeDelegateZone(local, upstream []ibclient.NameS
 	return final
 }
 
-func (p *InfobloxProvider) CreateZoneDelegationForExternalDNS(gslb *k8gbv1beta1.Gslb) error {
-	objMgr, err := p.infobloxConnection()
-	if err != nil {
-		return err
-	}
-	addresses, err := p.assistant.GslbIngressExposedIPs(gslb)
-	if err != nil {
-		return err
-	}
+func (p *InfobloxProvider) CreateZoneDelegationForExternalDNS(zd *ZoneDelegation) error {
+	return p.session.do(func(objMgr *ibclient.ObjectManager) error {
+		return p.createZoneDelegation(objMgr, zd)
+	})
+}
+
+func (p *InfobloxProvider) createZoneDelegation(objMgr *ibclient.ObjectManager, zd *ZoneDelegation) error {
 	var delegateTo []ibclient.NameServer
 
-	for _, address := range addresses {
+	for _, address := range zd.NameserverIPs {
 		nameServer := ibclient.NameServer{Address: address, Name: p.config.GetClusterNSName()}
 		delegateTo = append(delegateTo, nameServer)
 	}
 
-	findZone, err := objMgr.GetZoneDelegated(p.config.DNSZone)
+	findZone, err := p.getZoneDelegated(objMgr)
 	if err != nil {
 		return err
 	}
@@ -78,6 +97,7 @@ func (p *InfobloxProvider) CreateZoneDelegationForExternalDNS(gslb *k8gbv1beta1.
 	if !p.config.SplitBrainCheck {
 		log.Info().Msg("Split-brain handling is disabled")
 	}
+	staleNS, view := staleNSNames(p.config, p.assistant, zd.Gslbs)
 
 	if findZone != nil {
 		err = p.checkZoneDelegated(findZone)
@@ -91,24 +111,15 @@ func (p *InfobloxProvider) CreateZoneDelegationForExternalDNS(gslb *k8gbv1beta1.
 			currentList := p.sanitizeDelegateZone(delegateTo, findZone.DelegateTo)
 
 			// Drop external records if they are stale
-			extClusterHeartbeatFQDNs := p.config.GetExternalClusterHeartbeatFQDNs(gslb.Name)
-			if p.config.SplitBrainCheck {
-				for extClusterGeoTag, nsServerNameExt := range p.config.GetExternalClusterNSNames() {
-					err = p.assistant.InspectTXTThreshold(
-						extClusterHeartbeatFQDNs[extClusterGeoTag],
-						time.Second*time.Duration(gslb.Spec.Strategy.SplitBrainThresholdSeconds))
-					if err != nil {
-						log.Err(err).Msgf("Got the error from TXT based checkAlive. External cluster (%s) doesn't "+
-							"look alive, filtering it out from delegated zone configuration...", nsServerNameExt)
-						currentList = p.filterOutDelegateTo(currentList, nsServerNameExt)
-					}
-				}
+			for nsServerNameExt := range staleNS {
+				currentList = p.filterOutDelegateTo(currentList, nsServerNameExt)
 			}
 
 			if !reflect.DeepEqual(findZone.DelegateTo, currentList) {
 				log.Info().Msgf("Found delegated zone records (%v)", findZone.DelegateTo)
 				log.Info().Msgf("Updating delegated zone(%s) with the server list(%v)", p.config.DNSZone, currentList)
 				_, err = objMgr.UpdateZoneDelegated(findZone.Ref, currentList)
+				p.zones.invalidate(p.config.DNSZone)
 				if err != nil {
 					return err
 				}
@@ -119,22 +130,30 @@ func (p *InfobloxProvider) CreateZoneDelegationForExternalDNS(gslb *k8gbv1beta1.
 		sortZones(delegateTo)
 		log.Debug().Msgf("Delegated records (%v)", delegateTo)
 		_, err = objMgr.CreateZoneDelegated(p.config.DNSZone, delegateTo)
+		p.zones.invalidate(p.config.DNSZone)
 		if err != nil {
 			return err
 		}
 	}
 	if p.config.SplitBrainCheck {
-		return p.saveHeartbeatTXTRecord(objMgr, gslb)
+		for _, gslb := range zd.Gslbs {
+			if err = p.saveHeartbeatTXTRecord(objMgr, gslb, view); err != nil {
+				return err
+			}
+		}
 	}
-	return nil
+	return p.deleteHeartbeatTXTRecords(objMgr, zd.Released)
 }
 
-func (p *InfobloxProvider) Finalize(gslb *k8gbv1beta1.Gslb) error {
-	objMgr, err := p.infobloxConnection()
-	if err != nil {
-		return err
-	}
-	findZone, err := objMgr.GetZoneDelegated(p.config.DNSZone)
+// Finalize removes own nameserver from delegated zone; the zone is deleted once no cluster is left in it
+func (p *InfobloxProvider) Finalize(zd *ZoneDelegation) error {
+	return p.session.do(func(objMgr *ibclient.ObjectManager) error {
+		return p.finalize(objMgr, zd)
+	})
+}
+
+func (p *InfobloxProvider) finalize(objMgr *ibclient.ObjectManager, zd *ZoneDelegation) error {
+	findZone, err := p.getZoneDelegated(objMgr)
 	if err != nil {
 		return err
 	}
@@ -145,24 +164,36 @@ func (p *InfobloxProvider) Finalize(gslb *k8gbv1beta1.Gslb) error {
 			return err
 		}
 		if len(findZone.Ref) > 0 {
-			log.Info().Msgf("Deleting delegated zone(%s)...", p.config.DNSZone)
-			_, err := objMgr.DeleteZoneDelegated(findZone.Ref)
+			remaining := p.filterOutDelegateTo(findZone.DelegateTo, p.config.GetClusterNSName())
+			if len(remaining) == 0 {
+				log.Info().Msgf("Deleting delegated zone(%s)...", p.config.DNSZone)
+				_, err = objMgr.DeleteZoneDelegated(findZone.Ref)
+			} else if len(remaining) != len(findZone.DelegateTo) {
+				log.Info().Msgf("Removing %s from delegated zone(%s)...", p.config.GetClusterNSName(), p.config.DNSZone)
+				_, err = objMgr.UpdateZoneDelegated(findZone.Ref, remaining)
+			}
+			p.zones.invalidate(p.config.DNSZone)
 			if err != nil {
 				return err
 			}
 		}
 	}
+	return p.deleteHeartbeatTXTRecords(objMgr, zd.Released)
+}
 
-	heartbeatTXTName := p.config.GetClusterHeartbeatFQDN(gslb.Name)
-	findTXT, err := objMgr.GetTXTRecord(heartbeatTXTName)
-	if err != nil {
-		return err
+func (p *InfobloxProvider) deleteHeartbeatTXTRecords(objMgr *ibclient.ObjectManager, gslbs []*k8gbv1beta1.Gslb) error {
+	if p.heartbeats != nil {
+		return deleteBackendHeartbeats(p.config, p.heartbeats, gslbs)
 	}
-
-	if findTXT != nil {
-		if len(findTXT.Ref) > 0 {
+	for _, gslb := range gslbs {
+		heartbeatTXTName := p.config.GetClusterHeartbeatFQDN(gslb.Name)
+		findTXT, err := objMgr.GetTXTRecord(heartbeatTXTName)
+		if err != nil {
+			return err
+		}
+		if findTXT != nil && len(findTXT.Ref) > 0 {
 			log.Info().Msgf("Deleting split brain TXT record(%s)...", heartbeatTXTName)
-			_, err := objMgr.DeleteTXTRecord(findTXT.Ref)
+			_, err = objMgr.DeleteTXTRecord(findTXT.Ref)
 			if err != nil {
 				return err
 			}
@@ -171,8 +202,15 @@ func (p *InfobloxProvider) Finalize(gslb *k8gbv1beta1.Gslb) error {
 	return nil
 }
 
-func (p *InfobloxProvider) GetExternalTargets(host string) (targets []string) {
-	return p.assistant.GetExternalTargets(host, p.config.GetExternalClusterNSNames())
+// getZoneDelegated reads delegated zone through the cache shared by all Gslbs
+func (p *InfobloxProvider) getZoneDelegated(objMgr *ibclient.ObjectManager) (*ibclient.ZoneDelegated, error) {
+	return p.zones.get(p.config.DNSZone, func() (*ibclient.ZoneDelegated, error) {
+		return objMgr.GetZoneDelegated(p.config.DNSZone)
+	})
+}
+
+func (p *InfobloxProvider) GetExternalTargets(ctx context.Context, host string) (targets []string) {
+	return p.assistant.GetExternalTargets(ctx, host, p.config.GetExternalClusterNSNames())
 }
 
 func (p *InfobloxProvider) GslbIngressExposedIPs(gslb *k8gbv1beta1.Gslb) ([]string, error) {
@@ -187,10 +225,13 @@ func (p *InfobloxProvider) String() string {
 	return "Infoblox"
 }
 
-func (p *InfobloxProvider) saveHeartbeatTXTRecord(objMgr *ibclient.ObjectManager, gslb *k8gbv1beta1.Gslb) (err error) {
+func (p *InfobloxProvider) saveHeartbeatTXTRecord(objMgr *ibclient.ObjectManager, gslb *k8gbv1beta1.Gslb, view []string) (err error) {
 	var heartbeatTXTRecord *ibclient.RecordTXT
-	edgeTimestamp := fmt.Sprint(time.Now().UTC().Format("2006-01-02T15:04:05"))
+	if p.heartbeats != nil {
+		return saveBackendHeartbeat(p.config, p.heartbeats, gslb, view)
+	}
 	heartbeatTXTName := p.config.GetClusterHeartbeatFQDN(gslb.Name)
+	edgeTimestamp := heartbeatPayload(p.config, gslb, heartbeatTXTName, view)
 	heartbeatTXTRecord, err = objMgr.GetTXTRecord(heartbeatTXTName)
 	if err != nil {
 		return
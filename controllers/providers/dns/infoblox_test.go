@@ -18,13 +18,24 @@ Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
 package dns
 
 import (
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/AbsaOSS/k8gb/controllers/depresolver"
 	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
 
 	ibclient "github.com/infobloxopen/infoblox-go-client"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var predefinedConfig = depresolver.Config{
@@ -65,8 +76,8 @@ func TestCanFilterOutDelegatedZoneEntryAccordingFQDNProvided(t *testing.T) {
 	customConfig := predefinedConfig
 	customConfig.EdgeDNSZone = "example.com"
 	customConfig.ExtClustersGeoTags = []string{"za"}
-	a := assistant.NewGslbAssistant(nil, customConfig.K8gbNamespace, customConfig.EdgeDNSServer, customConfig.EdgeDNSServerPort)
-	provider := NewInfobloxDNS(customConfig, a)
+	a := assistant.NewGslbAssistant(nil, customConfig.K8gbNamespace, customConfig.EdgeDNSServer, customConfig.EdgeDNSServerPort, nil)
+	provider := NewInfobloxDNS(customConfig, a, nil)
 	// act
 	extClusters := customConfig.GetExternalClusterNSNames()
 	got := provider.filterOutDelegateTo(delegateTo, extClusters["za"])
@@ -100,8 +111,8 @@ func TestCanSanitizeDelegatedZone(t *testing.T) {
 	customConfig.EdgeDNSZone = "example.com"
 	customConfig.ExtClustersGeoTags = []string{"za"}
 	customConfig.ClusterGeoTag = "eu"
-	a := assistant.NewGslbAssistant(nil, customConfig.K8gbNamespace, customConfig.EdgeDNSServer, customConfig.EdgeDNSServerPort)
-	provider := NewInfobloxDNS(customConfig, a)
+	a := assistant.NewGslbAssistant(nil, customConfig.K8gbNamespace, customConfig.EdgeDNSServer, customConfig.EdgeDNSServerPort, nil)
+	provider := NewInfobloxDNS(customConfig, a, nil)
 	// act
 	got := provider.sanitizeDelegateZone(local, upstream)
 	// assert
@@ -142,3 +153,117 @@ func TestNilSort(t *testing.T) {
 	sortZones(delegateTo)
 	assert.Nil(t, delegateTo)
 }
+
+func TestInfobloxCredentialsAreReadFromFiles(t *testing.T) {
+	// arrange
+	dir, err := ioutil.TempDir("", "k8gb-infoblox")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	customConfig := predefinedConfig
+	customConfig.Infoblox.UsernameFile = filepath.Join(dir, "username")
+	customConfig.Infoblox.PasswordFile = filepath.Join(dir, "password")
+	require.NoError(t, ioutil.WriteFile(customConfig.Infoblox.UsernameFile, []byte("admin\n"), 0600))
+	require.NoError(t, ioutil.WriteFile(customConfig.Infoblox.PasswordFile, []byte("secret\r\n"), 0600))
+	provider := NewInfobloxDNS(customConfig, nil, nil)
+	// act
+	username, password, err := provider.credentials()
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "admin", username)
+	assert.Equal(t, "secret", password)
+
+	// act: rotated secret is picked up
+	require.NoError(t, ioutil.WriteFile(customConfig.Infoblox.PasswordFile, []byte("rotated"), 0600))
+	_, password, err = provider.credentials()
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "rotated", password)
+}
+
+func TestInfobloxCredentialsFallBackToEnvironment(t *testing.T) {
+	// arrange
+	provider := NewInfobloxDNS(predefinedConfig, nil, nil)
+	// act
+	username, password, err := provider.credentials()
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, "foo", username)
+	assert.Equal(t, "blah", password)
+}
+
+func TestInfobloxCredentialsFileIsMissing(t *testing.T) {
+	// arrange
+	customConfig := predefinedConfig
+	customConfig.Infoblox.PasswordFile = "/tmp/k8gb-missing-infoblox-password"
+	provider := NewInfobloxDNS(customConfig, nil, nil)
+	// act
+	_, _, err := provider.credentials()
+	// assert
+	assert.Error(t, err)
+}
+
+func TestInfobloxSSLVerify(t *testing.T) {
+	// arrange
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	dir, err := ioutil.TempDir("", "k8gb-infoblox")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	validBundle := filepath.Join(dir, "ca.crt")
+	invalidBundle := filepath.Join(dir, "invalid.crt")
+	require.NoError(t, ioutil.WriteFile(validBundle, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0600))
+	require.NoError(t, ioutil.WriteFile(invalidBundle, []byte("not a certificate"), 0600))
+	tests := []struct {
+		name      string
+		sslVerify bool
+		caBundle  string
+		expected  string
+		err       bool
+	}{
+		{name: "disabled", sslVerify: false, expected: "false"},
+		{name: "system roots", sslVerify: true, expected: "true"},
+		{name: "CA bundle", sslVerify: true, caBundle: validBundle, expected: validBundle},
+		{name: "invalid CA bundle", sslVerify: true, caBundle: invalidBundle, err: true},
+		{name: "missing CA bundle", sslVerify: true, caBundle: filepath.Join(dir, "missing.crt"), err: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			customConfig := predefinedConfig
+			customConfig.Infoblox.SSLVerify = test.sslVerify
+			customConfig.Infoblox.CABundle = test.caBundle
+			provider := NewInfobloxDNS(customConfig, nil, nil)
+			// act
+			got, err := provider.sslVerify()
+			// assert
+			if test.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, got)
+		})
+	}
+}
+
+type failingGetConnector struct {
+	fakeInfobloxConnector
+}
+
+func (c *failingGetConnector) GetObject(ibclient.IBObject, string, interface{}) error {
+	return fmt.Errorf("WAPI unavailable")
+}
+
+func TestInfobloxCallsAreCountedInMetrics(t *testing.T) {
+	// arrange
+	m := metrics.NewPrometheusMetrics(predefinedConfig)
+	connector := &instrumentedConnector{IBConnector: &failingGetConnector{}, metrics: m}
+	// act
+	_, createErr := connector.CreateObject(ibclient.NewZoneDelegated(ibclient.ZoneDelegated{}))
+	getErr := connector.GetObject(ibclient.NewZoneDelegated(ibclient.ZoneDelegated{}), "", nil)
+	// assert
+	require.NoError(t, createErr)
+	require.Error(t, getErr)
+	providerErrors := m.GetProviderErrorsMetric()
+	assert.Equal(t, 1., testutil.ToFloat64(providerErrors.With(prometheus.Labels{"provider": "infoblox", "operation": "get"})))
+	assert.Equal(t, 0., testutil.ToFloat64(providerErrors.With(prometheus.Labels{"provider": "infoblox", "operation": "create"})))
+}
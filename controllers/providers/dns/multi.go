@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+)
+
+// MultiProvider fans out zone delegation (including heartbeat records) and finalization to several
+// edge DNS providers at once; e.g. when the zone is migrated from one edge DNS to another.
+type MultiProvider struct {
+	providers []Provider
+}
+
+// ProvidersError collects errors of the providers which failed while MultiProvider fanned out the call
+type ProvidersError struct {
+	// Errors keyed by provider name
+	Errors map[string]error
+	// Total number of providers the call was fanned out to
+	Total int
+}
+
+func NewMultiProvider(providers ...Provider) *MultiProvider {
+	return &MultiProvider{
+		providers: providers,
+	}
+}
+
+// CreateZoneDelegationForExternalDNS creates zone delegation in all providers. Failure of one provider
+// doesn't stop the others; returns *ProvidersError if any of them failed
+func (p *MultiProvider) CreateZoneDelegationForExternalDNS(zd *ZoneDelegation) error {
+	return p.fanOut(func(provider Provider) error {
+		return provider.CreateZoneDelegationForExternalDNS(zd)
+	})
+}
+
+// Finalize removes zone delegation from all providers; returns *ProvidersError if any of them failed
+func (p *MultiProvider) Finalize(zd *ZoneDelegation) error {
+	return p.fanOut(func(provider Provider) error {
+		return provider.Finalize(zd)
+	})
+}
+
+// GetExternalTargets doesn't depend on edge DNS provider, the first one is asked
+func (p *MultiProvider) GetExternalTargets(ctx context.Context, host string) (targets []string) {
+	return p.providers[0].GetExternalTargets(ctx, host)
+}
+
+// GslbIngressExposedIPs doesn't depend on edge DNS provider, the first one is asked
+func (p *MultiProvider) GslbIngressExposedIPs(gslb *k8gbv1beta1.Gslb) ([]string, error) {
+	return p.providers[0].GslbIngressExposedIPs(gslb)
+}
+
+// SaveDNSEndpoint stores the local DNSEndpoint which is shared by all edge DNS providers, so it is saved once
+func (p *MultiProvider) SaveDNSEndpoint(gslb *k8gbv1beta1.Gslb, i *externaldns.DNSEndpoint) error {
+	return p.providers[0].SaveDNSEndpoint(gslb, i)
+}
+
+func (p *MultiProvider) String() string {
+	names := make([]string, 0, len(p.providers))
+	for _, provider := range p.providers {
+		names = append(names, fmt.Sprintf("%s", provider))
+	}
+	return strings.Join(names, ",")
+}
+
+func (p *MultiProvider) fanOut(call func(Provider) error) error {
+	e := &ProvidersError{Errors: make(map[string]error), Total: len(p.providers)}
+	for _, provider := range p.providers {
+		if err := call(provider); err != nil {
+			log.Err(err).Msgf("%s provider failed", provider)
+			e.Errors[fmt.Sprintf("%s", provider)] = err
+		}
+	}
+	if len(e.Errors) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e *ProvidersError) Error() string {
+	var messages []string
+	for _, name := range e.Failed() {
+		messages = append(messages, fmt.Sprintf("%s: %s", name, e.Errors[name]))
+	}
+	return fmt.Sprintf("%v of %v providers failed (%s)", len(e.Errors), e.Total, strings.Join(messages, "; "))
+}
+
+// Failed returns sorted names of failed providers
+func (e *ProvidersError) Failed() []string {
+	names := make([]string, 0, len(e.Errors))
+	for name := range e.Errors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Partial returns true if at least one provider succeeded
+func (e *ProvidersError) Partial() bool {
+	return len(e.Errors) < e.Total
+}
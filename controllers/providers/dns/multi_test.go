@@ -0,0 +1,114 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// namedProvider gives the mock a name, as real providers have
+type namedProvider struct {
+	*MockProvider
+	name string
+}
+
+func (p namedProvider) String() string {
+	return p.name
+}
+
+func newNamedProvider(ctrl *gomock.Controller, name string) namedProvider {
+	return namedProvider{NewMockProvider(ctrl), name}
+}
+
+func TestMultiProviderFansOutZoneDelegation(t *testing.T) {
+	// arrange
+	zd := delegation()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	infoblox := newNamedProvider(ctrl, "Infoblox")
+	route53 := newNamedProvider(ctrl, "Route53")
+	infoblox.EXPECT().CreateZoneDelegationForExternalDNS(zd).Return(nil).Times(1)
+	route53.EXPECT().CreateZoneDelegationForExternalDNS(zd).Return(nil).Times(1)
+	p := NewMultiProvider(infoblox, route53)
+	// act
+	err := p.CreateZoneDelegationForExternalDNS(zd)
+	// assert
+	assert.NoError(t, err)
+	assert.Equal(t, "Infoblox,Route53", p.String())
+}
+
+func TestMultiProviderReportsPartialFailure(t *testing.T) {
+	// arrange
+	zd := delegation()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	infoblox := newNamedProvider(ctrl, "Infoblox")
+	route53 := newNamedProvider(ctrl, "Route53")
+	ns1 := newNamedProvider(ctrl, "NS1")
+	infoblox.EXPECT().CreateZoneDelegationForExternalDNS(zd).Return(fmt.Errorf("connection refused")).Times(1)
+	route53.EXPECT().CreateZoneDelegationForExternalDNS(zd).Return(nil).Times(1)
+	ns1.EXPECT().CreateZoneDelegationForExternalDNS(zd).Return(fmt.Errorf("unauthorized")).Times(1)
+	p := NewMultiProvider(infoblox, route53, ns1)
+	// act
+	err := p.CreateZoneDelegationForExternalDNS(zd)
+	// assert
+	require.Error(t, err)
+	providersErr, ok := err.(*ProvidersError)
+	require.True(t, ok)
+	assert.True(t, providersErr.Partial())
+	assert.Equal(t, []string{"Infoblox", "NS1"}, providersErr.Failed())
+	assert.EqualError(t, providersErr.Errors["Infoblox"], "connection refused")
+	assert.Equal(t, "2 of 3 providers failed (Infoblox: connection refused; NS1: unauthorized)", err.Error())
+}
+
+func TestMultiProviderFinalizeContinuesWhenProviderFails(t *testing.T) {
+	// arrange
+	zd := delegation()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	infoblox := newNamedProvider(ctrl, "Infoblox")
+	route53 := newNamedProvider(ctrl, "Route53")
+	infoblox.EXPECT().Finalize(zd).Return(fmt.Errorf("connection refused")).Times(1)
+	route53.EXPECT().Finalize(zd).Return(nil).Times(1)
+	p := NewMultiProvider(infoblox, route53)
+	// act
+	err := p.Finalize(zd)
+	// assert
+	require.Error(t, err)
+	assert.Equal(t, []string{"Infoblox"}, err.(*ProvidersError).Failed())
+}
+
+func TestMultiProviderSavesDNSEndpointOnce(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	infoblox := newNamedProvider(ctrl, "Infoblox")
+	route53 := newNamedProvider(ctrl, "Route53")
+	infoblox.EXPECT().SaveDNSEndpoint(a.Gslb, gomock.Any()).Return(nil).Times(1)
+	route53.EXPECT().SaveDNSEndpoint(gomock.Any(), gomock.Any()).Times(0)
+	p := NewMultiProvider(infoblox, route53)
+	// act
+	err := p.SaveDNSEndpoint(a.Gslb, nil)
+	// assert
+	assert.NoError(t, err)
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"reflect"
+	"sort"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+)
+
+// Actions of planned DNS changes
+const (
+	ChangeCreate = "create"
+	ChangeUpdate = "update"
+	ChangeDelete = "delete"
+)
+
+// PlanZoneDelegation computes changes of edge DNS which CreateZoneDelegationForExternalDNS would apply, without
+// writing them. Current records are resolved from edge DNS server, so the plan is the same for every provider.
+func PlanZoneDelegation(config depresolver.Config, a assistant.Assistant, zd *ZoneDelegation) ([]k8gbv1beta1.DNSChange, error) {
+	nsName := config.GetClusterNSName()
+	ttl := int64(zd.TTL)
+	currentNS, err := a.LookupRecords(config.DNSZone, "NS")
+	if err != nil {
+		return nil, err
+	}
+	stale, _ := staleNSNames(config, a, zd.Gslbs)
+	desiredNS := []string{nsName}
+	for _, ns := range currentNS {
+		if ns != nsName && !stale[ns] {
+			desiredNS = append(desiredNS, ns)
+		}
+	}
+	changes := diffRecord(config.DNSZone, "NS", ttl, currentNS, desiredNS)
+	currentGlue, err := a.LookupRecords(nsName, "A")
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, diffRecord(nsName, "A", ttl, currentGlue, zd.NameserverIPs)...)
+	// heartbeats stored in Leases aren't edge DNS changes
+	if config.SplitBrainCheck && config.Heartbeat.Backend != depresolver.HeartbeatBackendLease {
+		for _, gslb := range zd.Gslbs {
+			// heartbeat carries timestamp, so it is rewritten on every delegation
+			changes = append(changes, k8gbv1beta1.DNSChange{Action: ChangeUpdate, Name: config.GetClusterHeartbeatFQDN(gslb.Name), Type: "TXT", TTL: ttl})
+		}
+	}
+	return append(changes, planHeartbeatRemoval(config, zd.Released)...), nil
+}
+
+// PlanFinalize computes changes of edge DNS which Finalize would apply, without writing them
+func PlanFinalize(config depresolver.Config, a assistant.Assistant, zd *ZoneDelegation) ([]k8gbv1beta1.DNSChange, error) {
+	nsName := config.GetClusterNSName()
+	currentNS, err := a.LookupRecords(config.DNSZone, "NS")
+	if err != nil {
+		return nil, err
+	}
+	var desiredNS []string
+	for _, ns := range currentNS {
+		if ns != nsName {
+			desiredNS = append(desiredNS, ns)
+		}
+	}
+	changes := diffRecord(config.DNSZone, "NS", int64(zd.TTL), currentNS, desiredNS)
+	currentGlue, err := a.LookupRecords(nsName, "A")
+	if err != nil {
+		return nil, err
+	}
+	changes = append(changes, diffRecord(nsName, "A", int64(zd.TTL), currentGlue, nil)...)
+	return append(changes, planHeartbeatRemoval(config, zd.Released)...), nil
+}
+
+func planHeartbeatRemoval(config depresolver.Config, released []*k8gbv1beta1.Gslb) (changes []k8gbv1beta1.DNSChange) {
+	if config.Heartbeat.Backend == depresolver.HeartbeatBackendLease {
+		return nil
+	}
+	for _, gslb := range released {
+		changes = append(changes, k8gbv1beta1.DNSChange{Action: ChangeDelete, Name: config.GetClusterHeartbeatFQDN(gslb.Name), Type: "TXT"})
+	}
+	return changes
+}
+
+// diffRecord returns change turning current targets of the record into desired ones; targets are compared
+// regardless of their order
+func diffRecord(name, recordType string, ttl int64, current, desired []string) []k8gbv1beta1.DNSChange {
+	current = sortedCopy(current)
+	desired = sortedCopy(desired)
+	switch {
+	case len(desired) == 0 && len(current) == 0:
+		return nil
+	case len(desired) == 0:
+		return []k8gbv1beta1.DNSChange{{Action: ChangeDelete, Name: name, Type: recordType}}
+	case len(current) == 0:
+		return []k8gbv1beta1.DNSChange{{Action: ChangeCreate, Name: name, Type: recordType, Targets: desired, TTL: ttl}}
+	case !reflect.DeepEqual(current, desired):
+		return []k8gbv1beta1.DNSChange{{Action: ChangeUpdate, Name: name, Type: recordType, Targets: desired, TTL: ttl}}
+	}
+	return nil
+}
+
+func sortedCopy(s []string) []string {
+	if len(s) == 0 {
+		return nil
+	}
+	c := append([]string{}, s...)
+	sort.Strings(c)
+	return c
+}
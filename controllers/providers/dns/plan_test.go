@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"fmt"
+	"testing"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanZoneDelegationDropsStaleClusters(t *testing.T) {
+	// arrange
+	config := a.Config
+	config.SplitBrainCheck = true
+	heartbeats := config.GetExternalClusterHeartbeatFQDNs(a.Gslb.Name)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	m.EXPECT().LookupRecords(config.DNSZone, "NS").Return([]string{"gslb-ns-eu-cloud.example.com", "gslb-ns-za-cloud.example.com"}, nil).Times(1)
+	m.EXPECT().LookupRecords(config.GetClusterNSName(), "A").Return(nil, nil).Times(1)
+	m.EXPECT().InspectHeartbeat(heartbeats["za"], gomock.Any()).Return(heartbeat.Heartbeat{}, fmt.Errorf("expired")).Times(1)
+	m.EXPECT().InspectHeartbeat(heartbeats["eu"], gomock.Any()).Return(heartbeat.Heartbeat{}, nil).Times(1)
+	// act
+	changes, err := PlanZoneDelegation(config, m, delegation())
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []k8gbv1beta1.DNSChange{
+		{Action: ChangeUpdate, Name: config.DNSZone, Type: "NS",
+			Targets: []string{"gslb-ns-eu-cloud.example.com", "gslb-ns-us-cloud.example.com"}, TTL: 30},
+		{Action: ChangeCreate, Name: config.GetClusterNSName(), Type: "A", Targets: []string{"10.0.1.38", "10.0.1.39", "10.0.1.40"}, TTL: 30},
+		{Action: ChangeUpdate, Name: config.GetClusterHeartbeatFQDN(a.Gslb.Name), Type: "TXT", TTL: 30},
+	}, changes)
+}
+
+func TestPlanZoneDelegationWithoutChanges(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	m.EXPECT().LookupRecords(a.Config.DNSZone, "NS").Return([]string{"gslb-ns-us-cloud.example.com"}, nil).Times(1)
+	m.EXPECT().LookupRecords(a.Config.GetClusterNSName(), "A").Return([]string{"10.0.1.40", "10.0.1.39", "10.0.1.38"}, nil).Times(1)
+	// act
+	changes, err := PlanZoneDelegation(a.Config, m, delegation())
+	// assert
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestPlanZoneDelegationWithLeaseHeartbeats(t *testing.T) {
+	// arrange
+	config := a.Config
+	config.SplitBrainCheck = true
+	config.Heartbeat.Backend = depresolver.HeartbeatBackendLease
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	m.EXPECT().LookupRecords(config.DNSZone, "NS").Return([]string{"gslb-ns-us-cloud.example.com"}, nil).Times(1)
+	m.EXPECT().LookupRecords(config.GetClusterNSName(), "A").Return([]string{"10.0.1.40", "10.0.1.39", "10.0.1.38"}, nil).Times(1)
+	m.EXPECT().InspectHeartbeat(gomock.Any(), gomock.Any()).Return(heartbeat.Heartbeat{}, nil).AnyTimes()
+	// act
+	changes, err := PlanZoneDelegation(config, m, &ZoneDelegation{TTL: 30, NameserverIPs: a.TargetIPs,
+		Gslbs: []*k8gbv1beta1.Gslb{a.Gslb}, Released: []*k8gbv1beta1.Gslb{a.Gslb}})
+	// assert
+	require.NoError(t, err)
+	assert.Empty(t, changes, "heartbeats in Leases aren't changes of edge DNS")
+}
+
+func TestPlanFinalizeKeepsOtherClusters(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	m.EXPECT().LookupRecords(a.Config.DNSZone, "NS").Return([]string{"gslb-ns-us-cloud.example.com", "gslb-ns-za-cloud.example.com"}, nil).Times(1)
+	m.EXPECT().LookupRecords(a.Config.GetClusterNSName(), "A").Return(a.TargetIPs, nil).Times(1)
+	// act
+	changes, err := PlanFinalize(a.Config, m, &ZoneDelegation{TTL: 30, Released: []*k8gbv1beta1.Gslb{a.Gslb}})
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []k8gbv1beta1.DNSChange{
+		{Action: ChangeUpdate, Name: a.Config.DNSZone, Type: "NS", Targets: []string{"gslb-ns-za-cloud.example.com"}, TTL: 30},
+		{Action: ChangeDelete, Name: a.Config.GetClusterNSName(), Type: "A"},
+		{Action: ChangeDelete, Name: a.Config.GetClusterHeartbeatFQDN(a.Gslb.Name), Type: "TXT"},
+	}, changes)
+}
@@ -0,0 +1,182 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+	"github.com/AbsaOSS/k8gb/controllers/providers/dns/plugin"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+)
+
+// PluginProvider delegates edge DNS handling to out-of-process plugin implementing plugin.ProviderServer.
+// The plugin is reached over Unix socket; methods the plugin doesn't implement are handled by the assistant.
+type PluginProvider struct {
+	assistant assistant.Assistant
+	config    depresolver.Config
+	client    plugin.ProviderClient
+}
+
+func NewPluginProvider(config depresolver.Config, assistant assistant.Assistant) (*PluginProvider, error) {
+	if config.ProviderPlugin.Socket == "" {
+		return nil, fmt.Errorf("socket of DNS provider plugin isn't set")
+	}
+	// dial doesn't block and calls wait for the plugin until timeout, so the plugin may start later than the operator
+	conn, err := grpc.Dial("unix://"+config.ProviderPlugin.Socket, grpc.WithInsecure(),
+		grpc.WithDefaultCallOptions(grpc.WaitForReady(true)))
+	if err != nil {
+		return nil, fmt.Errorf("dialing DNS provider plugin %s: %w", config.ProviderPlugin.Socket, err)
+	}
+	return &PluginProvider{
+		assistant: assistant,
+		config:    config,
+		client:    plugin.NewProviderClient(conn),
+	}, nil
+}
+
+func (p *PluginProvider) CreateZoneDelegationForExternalDNS(zd *ZoneDelegation) error {
+	var gslbs [][]byte
+	for _, gslb := range zd.Gslbs {
+		data, err := json.Marshal(gslb)
+		if err != nil {
+			return err
+		}
+		gslbs = append(gslbs, data)
+	}
+	ctx, cancel := p.context()
+	defer cancel()
+	log.Info().Msgf("Calling %s to create zone delegation for %s", p, p.config.DNSZone)
+	_, err := p.client.CreateZoneDelegation(ctx, &plugin.CreateZoneDelegationRequest{
+		Gslbs:              gslbs,
+		Cluster:            p.cluster(),
+		NameserverIps:      zd.NameserverIPs,
+		Ttl:                int32(zd.TTL),
+		Heartbeats:         p.heartbeats(zd.Gslbs),
+		ReleasedHeartbeats: p.heartbeats(zd.Released),
+	})
+	return pluginError("CreateZoneDelegation", err)
+}
+
+func (p *PluginProvider) Finalize(zd *ZoneDelegation) error {
+	ctx, cancel := p.context()
+	defer cancel()
+	_, err := p.client.Finalize(ctx, &plugin.FinalizeRequest{
+		Cluster:            p.cluster(),
+		Ttl:                int32(zd.TTL),
+		ReleasedHeartbeats: p.heartbeats(zd.Released),
+	})
+	return pluginError("Finalize", err)
+}
+
+func (p *PluginProvider) GetExternalTargets(ctx context.Context, host string) (targets []string) {
+	requestCtx, cancel := context.WithTimeout(ctx, time.Duration(p.config.ProviderPlugin.RequestTimeout)*time.Second)
+	defer cancel()
+	resp, err := p.client.GetExternalTargets(requestCtx, &plugin.GetExternalTargetsRequest{Host: host, Cluster: p.cluster()})
+	if status.Code(err) == codes.Unimplemented {
+		return p.assistant.GetExternalTargets(ctx, host, p.config.GetExternalClusterNSNames())
+	}
+	if err != nil {
+		log.Err(pluginError("GetExternalTargets", err)).Msgf("Can't get external targets for %s", host)
+		return nil
+	}
+	return resp.Targets
+}
+
+func (p *PluginProvider) GslbIngressExposedIPs(gslb *k8gbv1beta1.Gslb) ([]string, error) {
+	data, err := json.Marshal(gslb)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := p.context()
+	defer cancel()
+	resp, err := p.client.GslbIngressExposedIPs(ctx, &plugin.GslbIngressExposedIPsRequest{Gslb: data})
+	if status.Code(err) == codes.Unimplemented {
+		return p.assistant.GslbIngressExposedIPs(gslb)
+	}
+	if err != nil {
+		return nil, pluginError("GslbIngressExposedIPs", err)
+	}
+	return resp.Ips, nil
+}
+
+func (p *PluginProvider) SaveDNSEndpoint(gslb *k8gbv1beta1.Gslb, i *externaldns.DNSEndpoint) error {
+	gslbData, err := json.Marshal(gslb)
+	if err != nil {
+		return err
+	}
+	endpointData, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := p.context()
+	defer cancel()
+	_, err = p.client.SaveDNSEndpoint(ctx, &plugin.SaveDNSEndpointRequest{Gslb: gslbData, DnsEndpoint: endpointData})
+	if status.Code(err) == codes.Unimplemented {
+		return p.assistant.SaveDNSEndpoint(gslb.Namespace, i)
+	}
+	return pluginError("SaveDNSEndpoint", err)
+}
+
+func (p *PluginProvider) String() string {
+	return "Plugin"
+}
+
+// cluster describes this cluster to the plugin
+func (p *PluginProvider) cluster() *plugin.Cluster {
+	return &plugin.Cluster{
+		GeoTag:              p.config.ClusterGeoTag,
+		DnsZone:             p.config.DNSZone,
+		EdgeDnsZone:         p.config.EdgeDNSZone,
+		Nameserver:          p.config.GetClusterNSName(),
+		ExternalNameservers: p.config.GetExternalClusterNSNames(),
+		SplitBrainCheck:     p.config.SplitBrainCheck,
+	}
+}
+
+func (p *PluginProvider) heartbeats(gslbs []*k8gbv1beta1.Gslb) (heartbeats []*plugin.Heartbeat) {
+	for _, gslb := range gslbs {
+		heartbeats = append(heartbeats, &plugin.Heartbeat{
+			Gslb:                       gslb.Name,
+			Fqdn:                       p.config.GetClusterHeartbeatFQDN(gslb.Name),
+			ExternalFqdns:              p.config.GetExternalClusterHeartbeatFQDNs(gslb.Name),
+			SplitBrainThresholdSeconds: int32(gslb.Spec.Strategy.SplitBrainThresholdSeconds),
+		})
+	}
+	return heartbeats
+}
+
+func (p *PluginProvider) context() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), time.Duration(p.config.ProviderPlugin.RequestTimeout)*time.Second)
+}
+
+func pluginError(method string, err error) error {
+	if err == nil {
+		return nil
+	}
+	return fmt.Errorf("DNS provider plugin %s: %w", method, err)
+}
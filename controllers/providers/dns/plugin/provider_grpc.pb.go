@@ -0,0 +1,273 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package plugin
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ProviderClient is the client API for Provider service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ProviderClient interface {
+	// CreateZoneDelegation handles cluster-wide delegated zone in edge DNS
+	CreateZoneDelegation(ctx context.Context, in *CreateZoneDelegationRequest, opts ...grpc.CallOption) (*CreateZoneDelegationResponse, error)
+	// GslbIngressExposedIPs retrieves list of IP's exposed by all GSLB ingresses
+	GslbIngressExposedIPs(ctx context.Context, in *GslbIngressExposedIPsRequest, opts ...grpc.CallOption) (*GslbIngressExposedIPsResponse, error)
+	// GetExternalTargets retrieves list of external targets for specified host
+	GetExternalTargets(ctx context.Context, in *GetExternalTargetsRequest, opts ...grpc.CallOption) (*GetExternalTargetsResponse, error)
+	// SaveDNSEndpoint updates DNS endpoint of gslb or creates new one if doesn't exist
+	SaveDNSEndpoint(ctx context.Context, in *SaveDNSEndpointRequest, opts ...grpc.CallOption) (*SaveDNSEndpointResponse, error)
+	// Finalize removes delegation of the zone to the cluster from edge DNS once no Gslb references it
+	Finalize(ctx context.Context, in *FinalizeRequest, opts ...grpc.CallOption) (*FinalizeResponse, error)
+}
+
+type providerClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProviderClient(cc grpc.ClientConnInterface) ProviderClient {
+	return &providerClient{cc}
+}
+
+func (c *providerClient) CreateZoneDelegation(ctx context.Context, in *CreateZoneDelegationRequest, opts ...grpc.CallOption) (*CreateZoneDelegationResponse, error) {
+	out := new(CreateZoneDelegationResponse)
+	err := c.cc.Invoke(ctx, "/k8gb.dns.plugin.v1.Provider/CreateZoneDelegation", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *providerClient) GslbIngressExposedIPs(ctx context.Context, in *GslbIngressExposedIPsRequest, opts ...grpc.CallOption) (*GslbIngressExposedIPsResponse, error) {
+	out := new(GslbIngressExposedIPsResponse)
+	err := c.cc.Invoke(ctx, "/k8gb.dns.plugin.v1.Provider/GslbIngressExposedIPs", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *providerClient) GetExternalTargets(ctx context.Context, in *GetExternalTargetsRequest, opts ...grpc.CallOption) (*GetExternalTargetsResponse, error) {
+	out := new(GetExternalTargetsResponse)
+	err := c.cc.Invoke(ctx, "/k8gb.dns.plugin.v1.Provider/GetExternalTargets", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *providerClient) SaveDNSEndpoint(ctx context.Context, in *SaveDNSEndpointRequest, opts ...grpc.CallOption) (*SaveDNSEndpointResponse, error) {
+	out := new(SaveDNSEndpointResponse)
+	err := c.cc.Invoke(ctx, "/k8gb.dns.plugin.v1.Provider/SaveDNSEndpoint", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *providerClient) Finalize(ctx context.Context, in *FinalizeRequest, opts ...grpc.CallOption) (*FinalizeResponse, error) {
+	out := new(FinalizeResponse)
+	err := c.cc.Invoke(ctx, "/k8gb.dns.plugin.v1.Provider/Finalize", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ProviderServer is the server API for Provider service.
+// All implementations must embed UnimplementedProviderServer
+// for forward compatibility
+type ProviderServer interface {
+	// CreateZoneDelegation handles cluster-wide delegated zone in edge DNS
+	CreateZoneDelegation(context.Context, *CreateZoneDelegationRequest) (*CreateZoneDelegationResponse, error)
+	// GslbIngressExposedIPs retrieves list of IP's exposed by all GSLB ingresses
+	GslbIngressExposedIPs(context.Context, *GslbIngressExposedIPsRequest) (*GslbIngressExposedIPsResponse, error)
+	// GetExternalTargets retrieves list of external targets for specified host
+	GetExternalTargets(context.Context, *GetExternalTargetsRequest) (*GetExternalTargetsResponse, error)
+	// SaveDNSEndpoint updates DNS endpoint of gslb or creates new one if doesn't exist
+	SaveDNSEndpoint(context.Context, *SaveDNSEndpointRequest) (*SaveDNSEndpointResponse, error)
+	// Finalize removes delegation of the zone to the cluster from edge DNS once no Gslb references it
+	Finalize(context.Context, *FinalizeRequest) (*FinalizeResponse, error)
+	mustEmbedUnimplementedProviderServer()
+}
+
+// UnimplementedProviderServer must be embedded to have forward compatible implementations.
+type UnimplementedProviderServer struct {
+}
+
+func (UnimplementedProviderServer) CreateZoneDelegation(context.Context, *CreateZoneDelegationRequest) (*CreateZoneDelegationResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateZoneDelegation not implemented")
+}
+func (UnimplementedProviderServer) GslbIngressExposedIPs(context.Context, *GslbIngressExposedIPsRequest) (*GslbIngressExposedIPsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GslbIngressExposedIPs not implemented")
+}
+func (UnimplementedProviderServer) GetExternalTargets(context.Context, *GetExternalTargetsRequest) (*GetExternalTargetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetExternalTargets not implemented")
+}
+func (UnimplementedProviderServer) SaveDNSEndpoint(context.Context, *SaveDNSEndpointRequest) (*SaveDNSEndpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveDNSEndpoint not implemented")
+}
+func (UnimplementedProviderServer) Finalize(context.Context, *FinalizeRequest) (*FinalizeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Finalize not implemented")
+}
+func (UnimplementedProviderServer) mustEmbedUnimplementedProviderServer() {}
+
+// UnsafeProviderServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProviderServer will
+// result in compilation errors.
+type UnsafeProviderServer interface {
+	mustEmbedUnimplementedProviderServer()
+}
+
+func RegisterProviderServer(s grpc.ServiceRegistrar, srv ProviderServer) {
+	s.RegisterService(&Provider_ServiceDesc, srv)
+}
+
+func _Provider_CreateZoneDelegation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateZoneDelegationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProviderServer).CreateZoneDelegation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/k8gb.dns.plugin.v1.Provider/CreateZoneDelegation",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProviderServer).CreateZoneDelegation(ctx, req.(*CreateZoneDelegationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Provider_GslbIngressExposedIPs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GslbIngressExposedIPsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProviderServer).GslbIngressExposedIPs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/k8gb.dns.plugin.v1.Provider/GslbIngressExposedIPs",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProviderServer).GslbIngressExposedIPs(ctx, req.(*GslbIngressExposedIPsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Provider_GetExternalTargets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetExternalTargetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProviderServer).GetExternalTargets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/k8gb.dns.plugin.v1.Provider/GetExternalTargets",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProviderServer).GetExternalTargets(ctx, req.(*GetExternalTargetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Provider_SaveDNSEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SaveDNSEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProviderServer).SaveDNSEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/k8gb.dns.plugin.v1.Provider/SaveDNSEndpoint",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProviderServer).SaveDNSEndpoint(ctx, req.(*SaveDNSEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Provider_Finalize_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FinalizeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProviderServer).Finalize(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/k8gb.dns.plugin.v1.Provider/Finalize",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProviderServer).Finalize(ctx, req.(*FinalizeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Provider_ServiceDesc is the grpc.ServiceDesc for Provider service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Provider_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "k8gb.dns.plugin.v1.Provider",
+	HandlerType: (*ProviderServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateZoneDelegation",
+			Handler:    _Provider_CreateZoneDelegation_Handler,
+		},
+		{
+			MethodName: "GslbIngressExposedIPs",
+			Handler:    _Provider_GslbIngressExposedIPs_Handler,
+		},
+		{
+			MethodName: "GetExternalTargets",
+			Handler:    _Provider_GetExternalTargets_Handler,
+		},
+		{
+			MethodName: "SaveDNSEndpoint",
+			Handler:    _Provider_SaveDNSEndpoint_Handler,
+		},
+		{
+			MethodName: "Finalize",
+			Handler:    _Provider_Finalize_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "provider.proto",
+}
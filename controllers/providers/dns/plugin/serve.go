@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+
+// Package plugin contains gRPC contract of out-of-process edge DNS provider. Plugin authors implement
+// ProviderServer, usually by embedding UnimplementedProviderServer and implementing CreateZoneDelegation
+// and Finalize only, and run it by Serve next to the operator.
+//
+// Regenerate the contract by `make proto` when provider.proto changes.
+package plugin
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+
+	"google.golang.org/grpc"
+)
+
+// Serve listens on Unix socket and serves the provider until the context is done.
+// Stale socket file left by previous run is removed.
+func Serve(ctx context.Context, socket string, provider ProviderServer) error {
+	if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing stale socket %s: %w", socket, err)
+	}
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", socket, err)
+	}
+	server := grpc.NewServer()
+	RegisterProviderServer(server, provider)
+	go func() {
+		<-ctx.Done()
+		server.GracefulStop()
+	}()
+	return server.Serve(listener)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+	"github.com/AbsaOSS/k8gb/controllers/providers/dns/plugin"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+)
+
+// fakePlugin implements delegation only, as typical plugin does
+type fakePlugin struct {
+	plugin.UnimplementedProviderServer
+	delegation *plugin.CreateZoneDelegationRequest
+	finalized  []string
+	err        error
+}
+
+func (f *fakePlugin) CreateZoneDelegation(_ context.Context, r *plugin.CreateZoneDelegationRequest) (*plugin.CreateZoneDelegationResponse, error) {
+	f.delegation = r
+	return &plugin.CreateZoneDelegationResponse{}, f.err
+}
+
+func (f *fakePlugin) Finalize(_ context.Context, r *plugin.FinalizeRequest) (*plugin.FinalizeResponse, error) {
+	for _, heartbeat := range r.ReleasedHeartbeats {
+		f.finalized = append(f.finalized, heartbeat.Gslb)
+	}
+	return &plugin.FinalizeResponse{}, f.err
+}
+
+func servePlugin(t *testing.T, server plugin.ProviderServer) (config depresolver.Config, stop func()) {
+	dir, err := ioutil.TempDir("", "k8gb-plugin")
+	require.NoError(t, err)
+	socket := filepath.Join(dir, "provider.sock")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		done <- plugin.Serve(ctx, socket, server)
+	}()
+	for i := 0; i < 100; i++ {
+		if _, err = os.Stat(socket); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	config = a.Config
+	config.EdgeDNSType = depresolver.DNSTypePlugin
+	config.ProviderPlugin = depresolver.ProviderPlugin{Socket: socket, RequestTimeout: 5}
+	return config, func() {
+		cancel()
+		<-done
+		_ = os.RemoveAll(dir)
+	}
+}
+
+func TestPluginCreatesZoneDelegation(t *testing.T) {
+	// arrange
+	fake := &fakePlugin{}
+	config, stop := servePlugin(t, fake)
+	defer stop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	p, err := NewPluginProvider(config, m)
+	require.NoError(t, err)
+	// act
+	err = p.CreateZoneDelegationForExternalDNS(delegation())
+	// assert
+	require.NoError(t, err)
+	require.NotNil(t, fake.delegation)
+	assert.Equal(t, a.TargetIPs, fake.delegation.NameserverIps)
+	assert.Equal(t, "gslb-ns-us-cloud.example.com", fake.delegation.Cluster.Nameserver)
+	assert.Equal(t, config.GetExternalClusterNSNames(), fake.delegation.Cluster.ExternalNameservers)
+	assert.Equal(t, int32(30), fake.delegation.Ttl)
+	require.Len(t, fake.delegation.Heartbeats, 1)
+	assert.Equal(t, config.GetClusterHeartbeatFQDN(a.Gslb.Name), fake.delegation.Heartbeats[0].Fqdn)
+	assert.Empty(t, fake.delegation.ReleasedHeartbeats)
+	require.Len(t, fake.delegation.Gslbs, 1)
+	gslb := &k8gbv1beta1.Gslb{}
+	require.NoError(t, json.Unmarshal(fake.delegation.Gslbs[0], gslb))
+	assert.Equal(t, a.Gslb.Spec, gslb.Spec)
+}
+
+func TestPluginReturnsErrorOfPlugin(t *testing.T) {
+	// arrange
+	fake := &fakePlugin{err: status.Error(codes.PermissionDenied, "zone is locked")}
+	config, stop := servePlugin(t, fake)
+	defer stop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	p, err := NewPluginProvider(config, m)
+	require.NoError(t, err)
+	// act
+	err = p.CreateZoneDelegationForExternalDNS(delegation())
+	// assert
+	assert.Error(t, err)
+	assert.Equal(t, codes.PermissionDenied, status.Code(errors.Unwrap(err)))
+}
+
+func TestPluginFinalize(t *testing.T) {
+	// arrange
+	fake := &fakePlugin{}
+	config, stop := servePlugin(t, fake)
+	defer stop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	p, err := NewPluginProvider(config, assistant.NewMockAssistant(ctrl))
+	require.NoError(t, err)
+	// act
+	err = p.Finalize(&ZoneDelegation{TTL: 30, Released: []*k8gbv1beta1.Gslb{a.Gslb}})
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{a.Gslb.Name}, fake.finalized)
+}
+
+func TestPluginFallsBackToAssistantForUnimplementedMethods(t *testing.T) {
+	// arrange
+	config, stop := servePlugin(t, &fakePlugin{})
+	defer stop()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	m.EXPECT().GetExternalTargets(gomock.Any(), "roundrobin.cloud.example.com", config.GetExternalClusterNSNames()).Return([]string{"10.1.0.1"}).Times(1)
+	m.EXPECT().GslbIngressExposedIPs(a.Gslb).Return(a.TargetIPs, nil).Times(1)
+	m.EXPECT().SaveDNSEndpoint(a.Gslb.Namespace, gomock.Any()).Return(nil).Times(1)
+	p, err := NewPluginProvider(config, m)
+	require.NoError(t, err)
+	// act
+	targets := p.GetExternalTargets(context.TODO(), "roundrobin.cloud.example.com")
+	ips, err := p.GslbIngressExposedIPs(a.Gslb)
+	require.NoError(t, err)
+	err = p.SaveDNSEndpoint(a.Gslb, &externaldns.DNSEndpoint{})
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{"10.1.0.1"}, targets)
+	assert.Equal(t, a.TargetIPs, ips)
+}
+
+func TestPluginIsNotRunning(t *testing.T) {
+	// arrange
+	config := a.Config
+	config.ProviderPlugin = depresolver.ProviderPlugin{Socket: "/tmp/k8gb-missing-plugin.sock", RequestTimeout: 1}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	p, err := NewPluginProvider(config, m)
+	require.NoError(t, err)
+	// act
+	err = p.CreateZoneDelegationForExternalDNS(delegation())
+	// assert
+	assert.Error(t, err)
+}
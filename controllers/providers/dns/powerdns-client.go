@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+)
+
+const (
+	pdnsChangeTypeReplace = "REPLACE"
+	pdnsChangeTypeDelete  = "DELETE"
+	// pdnsMaxAttempts limits optimistic read-compare-write cycles when the RRset keeps changing under our hands
+	pdnsMaxAttempts = 5
+)
+
+// pdnsZone is the subset of PowerDNS zone object k8gb is interested in
+type pdnsZone struct {
+	ID     string      `json:"id"`
+	Name   string      `json:"name"`
+	Serial int64       `json:"serial"`
+	RRSets []pdnsRRSet `json:"rrsets"`
+}
+
+// pdnsRRSet represents PowerDNS resource record set; see: https://doc.powerdns.com/authoritative/http-api/zone.html#rrset
+type pdnsRRSet struct {
+	Name       string       `json:"name"`
+	Type       string       `json:"type"`
+	TTL        int          `json:"ttl,omitempty"`
+	ChangeType string       `json:"changetype,omitempty"`
+	Records    []pdnsRecord `json:"records"`
+}
+
+type pdnsRecord struct {
+	Content  string `json:"content"`
+	Disabled bool   `json:"disabled"`
+}
+
+type pdnsPatch struct {
+	RRSets []pdnsRRSet `json:"rrsets"`
+}
+
+// powerDNSClient talks to PowerDNS Authoritative HTTP API
+type powerDNSClient struct {
+	baseURL  string
+	apiKey   string
+	serverID string
+	http     *http.Client
+}
+
+func newPowerDNSClient(config depresolver.PowerDNS) *powerDNSClient {
+	return &powerDNSClient{
+		baseURL:  strings.TrimSuffix(config.APIURL, "/"),
+		apiKey:   config.APIKey,
+		serverID: config.ServerID,
+		http:     &http.Client{Timeout: time.Duration(config.HTTPRequestTimeout) * time.Second},
+	}
+}
+
+// zone reads zone including all RRSets
+func (c *powerDNSClient) zone(zone string) (z *pdnsZone, err error) {
+	z = &pdnsZone{}
+	err = c.do(http.MethodGet, c.zoneURL(zone), nil, z)
+	return
+}
+
+// patch applies RRSet changes to the zone. PowerDNS applies all RRSets of a single PATCH atomically
+func (c *powerDNSClient) patch(zone string, rrsets ...pdnsRRSet) error {
+	return c.do(http.MethodPatch, c.zoneURL(zone), pdnsPatch{RRSets: rrsets}, nil)
+}
+
+// rrset returns sorted content of RRSet or empty slice if RRSet doesn't exist
+func (c *powerDNSClient) rrset(zone, name, rrType string) ([]string, error) {
+	z, err := c.zone(zone)
+	if err != nil {
+		return nil, err
+	}
+	return z.contentOf(name, rrType), nil
+}
+
+// replace sets RRSet content regardless of its current state; empty content deletes the RRSet
+func (c *powerDNSClient) replace(zone, name, rrType string, ttl int, content []string) error {
+	return c.patch(zone, newPDNSRRSet(name, rrType, ttl, content))
+}
+
+// update applies mutate on the current content of RRSet using optimistic concurrency. The RRSet is read,
+// mutated and read again right before PATCH. When the RRSet changed in between, i.e. another cluster
+// modified it, the whole cycle is repeated. After PATCH the RRSet is verified and the cycle is repeated
+// in case the write was overtaken. PowerDNS has no conditional PATCH, so retries narrow the window in which
+// a concurrent write can be lost, but don't close it.
+func (c *powerDNSClient) update(zone, name, rrType string, ttl int, mutate func([]string) []string) error {
+	for attempt := 1; attempt <= pdnsMaxAttempts; attempt++ {
+		current, err := c.rrset(zone, name, rrType)
+		if err != nil {
+			return err
+		}
+		desired := mutate(current)
+		sort.Strings(desired)
+		if equalContent(current, desired) {
+			return nil
+		}
+		latest, err := c.rrset(zone, name, rrType)
+		if err != nil {
+			return err
+		}
+		if !equalContent(current, latest) {
+			log.Info().Msgf("RRSet %s %s modified concurrently, retrying (%v/%v)", name, rrType, attempt, pdnsMaxAttempts)
+			continue
+		}
+		err = c.replace(zone, name, rrType, ttl, desired)
+		if err != nil {
+			return err
+		}
+		written, err := c.rrset(zone, name, rrType)
+		if err != nil {
+			return err
+		}
+		if equalContent(written, desired) {
+			return nil
+		}
+		log.Info().Msgf("RRSet %s %s overwritten concurrently, retrying (%v/%v)", name, rrType, attempt, pdnsMaxAttempts)
+	}
+	return fmt.Errorf("can't update RRSet %s %s, it keeps changing concurrently (%v attempts)", name, rrType, pdnsMaxAttempts)
+}
+
+func (c *powerDNSClient) zoneURL(zone string) string {
+	return fmt.Sprintf("%s/api/v1/servers/%s/zones/%s", c.baseURL, url.PathEscape(c.serverID), url.PathEscape(canonical(zone)))
+}
+
+func (c *powerDNSClient) do(method, url string, in, out interface{}) error {
+	var body []byte
+	var err error
+	if in != nil {
+		body, err = json.Marshal(in)
+		if err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-API-Key", c.apiKey)
+	req.Header.Set("Accept", "application/json")
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("powerdns %s %s: %s (%s)", method, url, resp.Status, strings.TrimSpace(string(data)))
+	}
+	if out != nil && len(data) > 0 {
+		return json.Unmarshal(data, out)
+	}
+	return nil
+}
+
+// contentOf returns sorted content of enabled records within RRSet
+func (z *pdnsZone) contentOf(name, rrType string) []string {
+	content := []string{}
+	for _, rrset := range z.RRSets {
+		if rrset.Name == canonical(name) && rrset.Type == rrType {
+			for _, r := range rrset.Records {
+				if !r.Disabled {
+					content = append(content, r.Content)
+				}
+			}
+		}
+	}
+	sort.Strings(content)
+	return content
+}
+
+func newPDNSRRSet(name, rrType string, ttl int, content []string) pdnsRRSet {
+	rrset := pdnsRRSet{Name: canonical(name), Type: rrType, TTL: ttl, ChangeType: pdnsChangeTypeReplace, Records: []pdnsRecord{}}
+	if len(content) == 0 {
+		rrset.ChangeType = pdnsChangeTypeDelete
+		rrset.TTL = 0
+		return rrset
+	}
+	for _, c := range content {
+		rrset.Records = append(rrset.Records, pdnsRecord{Content: c})
+	}
+	return rrset
+}
+
+// canonical returns FQDN terminated by dot as PowerDNS expects
+func canonical(fqdn string) string {
+	return strings.TrimSuffix(fqdn, ".") + "."
+}
+
+func equalContent(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
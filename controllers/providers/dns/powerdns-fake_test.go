@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+)
+
+const fakePowerDNSAPIKey = "secret"
+
+// fakePowerDNS is in-memory stand-in of PowerDNS Authoritative HTTP API. It serves single server "localhost"
+// and supports zone GET and RRSet PATCH with REPLACE and DELETE change types.
+type fakePowerDNS struct {
+	sync.Mutex
+	server *httptest.Server
+	zones  map[string]*pdnsZone
+	reads  int
+	// onRead is called after every zone GET with number of reads so far; used to simulate concurrent writers
+	onRead func(reads int)
+}
+
+func newFakePowerDNS(zones ...string) *fakePowerDNS {
+	f := &fakePowerDNS{zones: make(map[string]*pdnsZone)}
+	for _, z := range zones {
+		f.zones[canonical(z)] = &pdnsZone{ID: canonical(z), Name: canonical(z), Serial: 1}
+	}
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+func (f *fakePowerDNS) Close() {
+	f.server.Close()
+}
+
+func (f *fakePowerDNS) URL() string {
+	return f.server.URL
+}
+
+// set writes RRSet directly, bypassing the API
+func (f *fakePowerDNS) set(zone string, rrset pdnsRRSet) {
+	f.Lock()
+	defer f.Unlock()
+	f.apply(f.zones[canonical(zone)], rrset)
+}
+
+func (f *fakePowerDNS) content(zone, name, rrType string) []string {
+	f.Lock()
+	defer f.Unlock()
+	return f.zones[canonical(zone)].contentOf(name, rrType)
+}
+
+func (f *fakePowerDNS) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-API-Key") != fakePowerDNSAPIKey {
+		http.Error(w, `{"error": "Unauthorized"}`, http.StatusUnauthorized)
+		return
+	}
+	const prefix = "/api/v1/servers/localhost/zones/"
+	if !strings.HasPrefix(r.URL.Path, prefix) {
+		http.Error(w, `{"error": "Not Found"}`, http.StatusNotFound)
+		return
+	}
+	f.Lock()
+	zone, found := f.zones[strings.TrimPrefix(r.URL.Path, prefix)]
+	if !found {
+		f.Unlock()
+		http.Error(w, `{"error": "Could not find domain"}`, http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		f.reads++
+		data, _ := json.Marshal(zone)
+		reads := f.reads
+		f.Unlock()
+		if f.onRead != nil {
+			f.onRead(reads)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	case http.MethodPatch:
+		defer f.Unlock()
+		patch := pdnsPatch{}
+		if err := json.NewDecoder(r.Body).Decode(&patch); err != nil {
+			http.Error(w, `{"error": "malformed"}`, http.StatusBadRequest)
+			return
+		}
+		for _, rrset := range patch.RRSets {
+			if !strings.HasSuffix(rrset.Name, zone.Name) {
+				http.Error(w, `{"error": "RRset is out of zone"}`, http.StatusUnprocessableEntity)
+				return
+			}
+		}
+		for _, rrset := range patch.RRSets {
+			f.apply(zone, rrset)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.Unlock()
+		http.Error(w, `{"error": "Method not allowed"}`, http.StatusMethodNotAllowed)
+	}
+}
+
+func (f *fakePowerDNS) apply(zone *pdnsZone, rrset pdnsRRSet) {
+	var rrsets []pdnsRRSet
+	for _, existing := range zone.RRSets {
+		if existing.Name != rrset.Name || existing.Type != rrset.Type {
+			rrsets = append(rrsets, existing)
+		}
+	}
+	if rrset.ChangeType != pdnsChangeTypeDelete {
+		rrset.ChangeType = ""
+		rrsets = append(rrsets, rrset)
+	}
+	zone.RRSets = rrsets
+	zone.Serial++
+}
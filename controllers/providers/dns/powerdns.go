@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"context"
+	"fmt"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+
+	externaldns "sigs.k8s.io/external-dns/endpoint"
+)
+
+// PowerDNSProvider manages zone delegation directly in the parent zone of PowerDNS Authoritative server
+// through its HTTP API, without external-dns in between.
+type PowerDNSProvider struct {
+	assistant assistant.Assistant
+	config    depresolver.Config
+	client    *powerDNSClient
+	// heartbeats stores heartbeats instead of edge DNS when set
+	heartbeats heartbeat.Backend
+}
+
+func NewPowerDNS(config depresolver.Config, assistant assistant.Assistant) *PowerDNSProvider {
+	return &PowerDNSProvider{
+		assistant: assistant,
+		config:    config,
+		client:    newPowerDNSClient(config.PowerDNS),
+	}
+}
+
+// CreateZoneDelegationForExternalDNS writes glue A record of the cluster nameserver and adds the nameserver
+// into the NS RRSet of delegated zone. NS entries of other clusters are kept untouched, unless the split brain
+// check finds them stale.
+func (p *PowerDNSProvider) CreateZoneDelegationForExternalDNS(zd *ZoneDelegation) (err error) {
+	if !p.config.SplitBrainCheck {
+		log.Info().Msg("Split-brain handling is disabled")
+	}
+	stale := make(map[string]bool)
+	staleNS, view := staleNSNames(p.config, p.assistant, zd.Gslbs)
+	for ns := range staleNS {
+		stale[canonical(ns)] = true
+	}
+	clusterNS := canonical(p.config.GetClusterNSName())
+
+	// glue first, so the NS entry never points to nameserver without address
+	log.Info().Msgf("Updating glue record %s with %v", clusterNS, zd.NameserverIPs)
+	err = p.client.replace(p.config.EdgeDNSZone, clusterNS, "A", zd.TTL, zd.NameserverIPs)
+	if err != nil {
+		return err
+	}
+
+	log.Info().Msgf("Updating delegated zone(%s) NS records...", p.config.DNSZone)
+	err = p.client.update(p.config.EdgeDNSZone, p.config.DNSZone, "NS", zd.TTL, func(current []string) (desired []string) {
+		for _, ns := range current {
+			if ns != clusterNS && !stale[ns] {
+				desired = append(desired, ns)
+			}
+		}
+		if len(zd.NameserverIPs) > 0 {
+			desired = append(desired, clusterNS)
+		}
+		return desired
+	})
+	if err != nil {
+		return err
+	}
+
+	if p.config.SplitBrainCheck {
+		for _, gslb := range zd.Gslbs {
+			if err = p.saveHeartbeatTXTRecord(gslb, view); err != nil {
+				return err
+			}
+		}
+	}
+	return p.deleteHeartbeatTXTRecords(zd.Released)
+}
+
+// Finalize removes own NS entry from delegated zone together with glue and heartbeat records.
+// Entries owned by other clusters are kept.
+func (p *PowerDNSProvider) Finalize(zd *ZoneDelegation) error {
+	clusterNS := canonical(p.config.GetClusterNSName())
+	log.Info().Msgf("Removing %s from delegated zone(%s)...", clusterNS, p.config.DNSZone)
+	err := p.client.update(p.config.EdgeDNSZone, p.config.DNSZone, "NS", zd.TTL,
+		func(current []string) (desired []string) {
+			for _, ns := range current {
+				if ns != clusterNS {
+					desired = append(desired, ns)
+				}
+			}
+			return desired
+		})
+	if err != nil {
+		return err
+	}
+	log.Info().Msgf("Deleting glue record(%s)...", clusterNS)
+	err = p.client.patch(p.config.EdgeDNSZone, newPDNSRRSet(clusterNS, "A", 0, nil))
+	if err != nil {
+		return err
+	}
+	return p.deleteHeartbeatTXTRecords(zd.Released)
+}
+
+func (p *PowerDNSProvider) GetExternalTargets(ctx context.Context, host string) (targets []string) {
+	return p.assistant.GetExternalTargets(ctx, host, p.config.GetExternalClusterNSNames())
+}
+
+func (p *PowerDNSProvider) GslbIngressExposedIPs(gslb *k8gbv1beta1.Gslb) ([]string, error) {
+	return p.assistant.GslbIngressExposedIPs(gslb)
+}
+
+func (p *PowerDNSProvider) SaveDNSEndpoint(gslb *k8gbv1beta1.Gslb, i *externaldns.DNSEndpoint) error {
+	return p.assistant.SaveDNSEndpoint(gslb.Namespace, i)
+}
+
+func (p *PowerDNSProvider) String() string {
+	return "PowerDNS"
+}
+
+func (p *PowerDNSProvider) saveHeartbeatTXTRecord(gslb *k8gbv1beta1.Gslb, view []string) error {
+	if p.heartbeats != nil {
+		return saveBackendHeartbeat(p.config, p.heartbeats, gslb, view)
+	}
+	heartbeatTXTName := p.config.GetClusterHeartbeatFQDN(gslb.Name)
+	edgeTimestamp := heartbeatPayload(p.config, gslb, heartbeatTXTName, view)
+	log.Info().Str("HeartbeatTXTName", heartbeatTXTName).Msg("Updating split brain TXT record")
+	return p.client.replace(p.config.EdgeDNSZone, heartbeatTXTName, "TXT", gslb.Spec.Strategy.DNSTtlSeconds,
+		[]string{fmt.Sprintf("%q", edgeTimestamp)})
+}
+
+func (p *PowerDNSProvider) deleteHeartbeatTXTRecords(gslbs []*k8gbv1beta1.Gslb) error {
+	if len(gslbs) == 0 {
+		return nil
+	}
+	if p.heartbeats != nil {
+		return deleteBackendHeartbeats(p.config, p.heartbeats, gslbs)
+	}
+	var rrsets []pdnsRRSet
+	for _, gslb := range gslbs {
+		heartbeatTXTName := p.config.GetClusterHeartbeatFQDN(gslb.Name)
+		log.Info().Msgf("Deleting split brain TXT record(%s)...", heartbeatTXTName)
+		rrsets = append(rrsets, newPDNSRRSet(heartbeatTXTName, "TXT", 0, nil))
+	}
+	return p.client.patch(p.config.EdgeDNSZone, rrsets...)
+}
@@ -0,0 +1,184 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	pdnsUsNS = "gslb-ns-us-cloud.example.com."
+	pdnsZaNS = "gslb-ns-za-cloud.example.com."
+	pdnsEuNS = "gslb-ns-eu-cloud.example.com."
+)
+
+func powerDNSConfig(url string) depresolver.Config {
+	config := a.Config
+	config.EdgeDNSType = depresolver.DNSTypePowerDNS
+	config.PowerDNS = depresolver.PowerDNS{
+		APIURL:             url,
+		APIKey:             fakePowerDNSAPIKey,
+		ServerID:           "localhost",
+		HTTPRequestTimeout: 5,
+	}
+	return config
+}
+
+func TestPowerDNSCreatesZoneDelegationAndKeepsForeignNS(t *testing.T) {
+	// arrange
+	pdns := newFakePowerDNS(a.Config.EdgeDNSZone)
+	defer pdns.Close()
+	pdns.set(a.Config.EdgeDNSZone, newPDNSRRSet(a.Config.DNSZone, "NS", 30, []string{pdnsZaNS}))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	p := NewPowerDNS(powerDNSConfig(pdns.URL()), m)
+	// act
+	err := p.CreateZoneDelegationForExternalDNS(delegation())
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{pdnsUsNS, pdnsZaNS}, pdns.content(a.Config.EdgeDNSZone, a.Config.DNSZone, "NS"))
+	assert.Equal(t, []string{"10.0.1.38", "10.0.1.39", "10.0.1.40"}, pdns.content(a.Config.EdgeDNSZone, pdnsUsNS, "A"))
+	assert.Empty(t, pdns.content(a.Config.EdgeDNSZone, "test-gslb-heartbeat-us.example.com", "TXT"))
+}
+
+func TestPowerDNSFiltersOutStaleClustersAndWritesHeartbeat(t *testing.T) {
+	// arrange
+	pdns := newFakePowerDNS(a.Config.EdgeDNSZone)
+	defer pdns.Close()
+	pdns.set(a.Config.EdgeDNSZone, newPDNSRRSet(a.Config.DNSZone, "NS", 30, []string{pdnsEuNS, pdnsZaNS}))
+	config := powerDNSConfig(pdns.URL())
+	config.SplitBrainCheck = true
+	heartbeats := config.GetExternalClusterHeartbeatFQDNs(a.Gslb.Name)
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	m.EXPECT().InspectHeartbeat(heartbeats["za"], gomock.Any()).Return(heartbeat.Heartbeat{}, fmt.Errorf("expired")).Times(1)
+	m.EXPECT().InspectHeartbeat(heartbeats["eu"], gomock.Any()).Return(heartbeat.Heartbeat{}, nil).Times(1)
+	p := NewPowerDNS(config, m)
+	// act
+	err := p.CreateZoneDelegationForExternalDNS(delegation())
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{pdnsEuNS, pdnsUsNS}, pdns.content(a.Config.EdgeDNSZone, a.Config.DNSZone, "NS"))
+	txt := pdns.content(a.Config.EdgeDNSZone, config.GetClusterHeartbeatFQDN(a.Gslb.Name), "TXT")
+	require.Len(t, txt, 1)
+	published, err := heartbeat.Parse(config.GetClusterHeartbeatFQDN(a.Gslb.Name), strings.Trim(txt[0], `"`), "")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"za"}, published.Stale, "view of this cluster is published for quorum")
+}
+
+func TestPowerDNSWritesSignedHeartbeat(t *testing.T) {
+	// arrange
+	pdns := newFakePowerDNS(a.Config.EdgeDNSZone)
+	defer pdns.Close()
+	config := powerDNSConfig(pdns.URL())
+	config.SplitBrainCheck = true
+	config.DNSSecurity.HeartbeatKey = "heartbeat-secret"
+	config.K8gbVersion = "v0.8.0"
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	m.EXPECT().InspectHeartbeat(gomock.Any(), gomock.Any()).Return(heartbeat.Heartbeat{}, nil).AnyTimes()
+	p := NewPowerDNS(config, m)
+	fqdn := config.GetClusterHeartbeatFQDN(a.Gslb.Name)
+	// act
+	err := p.CreateZoneDelegationForExternalDNS(delegation())
+	// assert
+	require.NoError(t, err)
+	txt := pdns.content(a.Config.EdgeDNSZone, fqdn, "TXT")
+	require.Len(t, txt, 1)
+	written, err := heartbeat.Parse(fqdn, strings.Trim(txt[0], `"`), "heartbeat-secret")
+	assert.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), written.Timestamp, time.Minute)
+	assert.Equal(t, config.ClusterGeoTag, written.GeoTag)
+	assert.Equal(t, "v0.8.0", written.OperatorVersion)
+}
+
+func TestPowerDNSDoesNotClobberConcurrentlyWrittenNS(t *testing.T) {
+	// arrange
+	pdns := newFakePowerDNS(a.Config.EdgeDNSZone)
+	defer pdns.Close()
+	pdns.set(a.Config.EdgeDNSZone, newPDNSRRSet(a.Config.DNSZone, "NS", 30, []string{pdnsZaNS}))
+	// eu cluster writes its NS right after we read the RRSet for the first time
+	pdns.onRead = func(reads int) {
+		if reads == 1 {
+			pdns.set(a.Config.EdgeDNSZone, newPDNSRRSet(a.Config.DNSZone, "NS", 30, []string{pdnsEuNS, pdnsZaNS}))
+		}
+	}
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	p := NewPowerDNS(powerDNSConfig(pdns.URL()), m)
+	// act
+	err := p.CreateZoneDelegationForExternalDNS(delegation())
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{pdnsEuNS, pdnsUsNS, pdnsZaNS}, pdns.content(a.Config.EdgeDNSZone, a.Config.DNSZone, "NS"))
+}
+
+func TestPowerDNSFinalizeRemovesOnlyOwnRecords(t *testing.T) {
+	// arrange
+	pdns := newFakePowerDNS(a.Config.EdgeDNSZone)
+	defer pdns.Close()
+	config := powerDNSConfig(pdns.URL())
+	heartbeat := config.GetClusterHeartbeatFQDN(a.Gslb.Name)
+	pdns.set(a.Config.EdgeDNSZone, newPDNSRRSet(a.Config.DNSZone, "NS", 30, []string{pdnsUsNS, pdnsZaNS}))
+	pdns.set(a.Config.EdgeDNSZone, newPDNSRRSet(pdnsUsNS, "A", 30, a.TargetIPs))
+	pdns.set(a.Config.EdgeDNSZone, newPDNSRRSet(pdnsZaNS, "A", 30, []string{"10.1.0.1"}))
+	pdns.set(a.Config.EdgeDNSZone, newPDNSRRSet(heartbeat, "TXT", 30, []string{`"2021-05-13T10:00:00"`}))
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	p := NewPowerDNS(config, assistant.NewMockAssistant(ctrl))
+	// act
+	err := p.Finalize(&ZoneDelegation{TTL: 30, Released: []*k8gbv1beta1.Gslb{a.Gslb}})
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, []string{pdnsZaNS}, pdns.content(a.Config.EdgeDNSZone, a.Config.DNSZone, "NS"))
+	assert.Empty(t, pdns.content(a.Config.EdgeDNSZone, pdnsUsNS, "A"))
+	assert.Empty(t, pdns.content(a.Config.EdgeDNSZone, heartbeat, "TXT"))
+	assert.Equal(t, []string{"10.1.0.1"}, pdns.content(a.Config.EdgeDNSZone, pdnsZaNS, "A"))
+}
+
+func TestPowerDNSReturnsErrorOnInvalidAPIKey(t *testing.T) {
+	// arrange
+	pdns := newFakePowerDNS(a.Config.EdgeDNSZone)
+	defer pdns.Close()
+	config := powerDNSConfig(pdns.URL())
+	config.PowerDNS.APIKey = "invalid"
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	p := NewPowerDNS(config, m)
+	// act
+	err := p.CreateZoneDelegationForExternalDNS(delegation())
+	// assert
+	assert.Error(t, err)
+}
@@ -0,0 +1,51 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"sort"
+
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+)
+
+// DelegatedZone is delegation of DNSZone as seen in edge DNS
+type DelegatedZone struct {
+	Zone string `json:"zone"`
+	// Nameservers of the zone with addresses of their glue records
+	Nameservers map[string][]string `json:"nameservers"`
+}
+
+// ResolveDelegatedZone resolves delegation of DNSZone from edge DNS server, so the view is the same for every
+// provider, like in PlanZoneDelegation
+func ResolveDelegatedZone(config depresolver.Config, a assistant.Assistant) (*DelegatedZone, error) {
+	nameservers, err := a.LookupRecords(config.DNSZone, "NS")
+	if err != nil {
+		return nil, err
+	}
+	zone := &DelegatedZone{Zone: config.DNSZone, Nameservers: make(map[string][]string, len(nameservers))}
+	for _, ns := range nameservers {
+		glue, err := a.LookupRecords(ns, "A")
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(glue)
+		zone.Nameservers[ns] = glue
+	}
+	return zone, nil
+}
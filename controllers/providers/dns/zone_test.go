@@ -0,0 +1,47 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package dns
+
+import (
+	"testing"
+
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDelegatedZoneWithGlueRecords(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	m := assistant.NewMockAssistant(ctrl)
+	m.EXPECT().LookupRecords(a.Config.DNSZone, "NS").Return([]string{"gslb-ns-eu-cloud.example.com", "gslb-ns-us-cloud.example.com"}, nil).Times(1)
+	m.EXPECT().LookupRecords("gslb-ns-eu-cloud.example.com", "A").Return([]string{"10.0.1.39", "10.0.1.38"}, nil).Times(1)
+	m.EXPECT().LookupRecords("gslb-ns-us-cloud.example.com", "A").Return(nil, nil).Times(1)
+	// act
+	zone, err := ResolveDelegatedZone(a.Config, m)
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, a.Config.DNSZone, zone.Zone)
+	assert.Equal(t, map[string][]string{
+		"gslb-ns-eu-cloud.example.com": {"10.0.1.38", "10.0.1.39"},
+		"gslb-ns-us-cloud.example.com": nil,
+	}, zone.Nameservers)
+}
@@ -19,7 +19,9 @@ package metrics
 
 import (
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 
 	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
 	"github.com/AbsaOSS/k8gb/controllers/depresolver"
@@ -34,10 +36,38 @@ const (
 	NotFoundStatus  = "NotFound"
 )
 
+const (
+	// Reconcile stages of Gslb
+	IngressStage     = "ingress"
+	DNSEndpointStage = "dnsendpoint"
+	DelegationStage  = "delegation"
+	StatusStage      = "status"
+	// NoActiveCluster is reported when no cluster serves failover host
+	NoActiveCluster = "none"
+	// Kinds of DNS data failing verification
+	HeartbeatVerification = "heartbeat"
+	TSIGVerification      = "tsig"
+)
+
+// PrometheusMetrics holds K8GB metrics. Methods observing reconciliation, failover, peers and providers are no-op
+// on nil receiver, so components may run without metrics
 type PrometheusMetrics struct {
 	healthyRecordsMetric        *prometheus.GaugeVec
 	ingressHostsPerStatusMetric *prometheus.GaugeVec
-	once                        sync.Once
+	reconcileDurationMetric     *prometheus.HistogramVec
+	reconcileErrorsMetric       *prometheus.CounterVec
+	failoverTransitionsMetric   *prometheus.CounterVec
+	failoverActiveClusterMetric *prometheus.GaugeVec
+	peerLookupDurationMetric    *prometheus.HistogramVec
+	peerLookupErrorsMetric      *prometheus.CounterVec
+	heartbeatAgeMetric          *prometheus.GaugeVec
+	verificationFailuresMetric  *prometheus.CounterVec
+	providerDurationMetric      *prometheus.HistogramVec
+	providerErrorsMetric        *prometheus.CounterVec
+	// activeClusters keeps geo tag of active cluster per failover host, so transitions can be counted
+	activeClusters map[string]string
+	mu             sync.Mutex
+	once           sync.Once
 }
 
 // NewPrometheusMetrics creates new prometheus metrics instance
@@ -61,6 +91,100 @@ func NewPrometheusMetrics(config depresolver.Config) (metrics *PrometheusMetrics
 		},
 		[]string{"namespace", "name", "status"},
 	)
+	metrics.reconcileDurationMetric = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: config.K8gbNamespace,
+			Subsystem: gslbSubsystem,
+			Name:      "reconcile_duration_seconds",
+			Help:      "Duration of reconcile stages in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"stage"},
+	)
+	metrics.reconcileErrorsMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.K8gbNamespace,
+			Subsystem: gslbSubsystem,
+			Name:      "reconcile_errors_total",
+			Help:      "Number of failed reconcile stages.",
+		},
+		[]string{"stage"},
+	)
+	metrics.failoverTransitionsMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.K8gbNamespace,
+			Subsystem: gslbSubsystem,
+			Name:      "failover_transitions_total",
+			Help:      "Number of changes of active cluster of failover hosts.",
+		},
+		[]string{"namespace", "name", "host", "from", "to"},
+	)
+	metrics.failoverActiveClusterMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.K8gbNamespace,
+			Subsystem: gslbSubsystem,
+			Name:      "failover_active_cluster",
+			Help:      "Geo tag of the cluster serving failover host.",
+		},
+		[]string{"namespace", "name", "host", "geotag"},
+	)
+	metrics.peerLookupDurationMetric = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: config.K8gbNamespace,
+			Subsystem: gslbSubsystem,
+			Name:      "peer_lookup_duration_seconds",
+			Help:      "Duration of external targets lookup per peer cluster in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"peer"},
+	)
+	metrics.peerLookupErrorsMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.K8gbNamespace,
+			Subsystem: gslbSubsystem,
+			Name:      "peer_lookup_errors_total",
+			Help:      "Number of failed external targets lookups per peer cluster.",
+		},
+		[]string{"peer"},
+	)
+	metrics.heartbeatAgeMetric = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Namespace: config.K8gbNamespace,
+			Subsystem: gslbSubsystem,
+			Name:      "heartbeat_age_seconds",
+			Help:      "Age of heartbeat TXT record of peer cluster in seconds.",
+		},
+		[]string{"fqdn"},
+	)
+	metrics.verificationFailuresMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.K8gbNamespace,
+			Subsystem: gslbSubsystem,
+			Name:      "dns_verification_failures_total",
+			Help:      "Number of heartbeats with invalid signature and peer lookups with invalid TSIG.",
+		},
+		[]string{"kind", "name"},
+	)
+	metrics.providerDurationMetric = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: config.K8gbNamespace,
+			Subsystem: gslbSubsystem,
+			Name:      "provider_request_duration_seconds",
+			Help:      "Duration of DNS provider API calls in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{"provider", "operation"},
+	)
+	metrics.providerErrorsMetric = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: config.K8gbNamespace,
+			Subsystem: gslbSubsystem,
+			Name:      "provider_request_errors_total",
+			Help:      "Number of failed DNS provider API calls.",
+		},
+		[]string{"provider", "operation"},
+	)
+	metrics.activeClusters = make(map[string]string)
 	return
 }
 
@@ -94,15 +218,108 @@ func (m *PrometheusMetrics) UpdateHealthyRecordsMetric(gslb *k8gbv1beta1.Gslb, h
 	return nil
 }
 
+// ObserveReconcileStage records duration of reconcile stage started at start; failed stage is counted as error
+func (m *PrometheusMetrics) ObserveReconcileStage(stage string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.reconcileDurationMetric.With(prometheus.Labels{"stage": stage}).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.reconcileErrorsMetric.With(prometheus.Labels{"stage": stage}).Inc()
+	}
+}
+
+// UpdateFailoverActiveCluster sets geo tag of the cluster serving failover host. Change of the active cluster is
+// counted as transition; the first observation isn't.
+func (m *PrometheusMetrics) UpdateFailoverActiveCluster(gslb *k8gbv1beta1.Gslb, host, geoTag string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key := gslb.Namespace + "/" + gslb.Name + "/" + host
+	previous, seen := m.activeClusters[key]
+	if seen && previous == geoTag {
+		return
+	}
+	if seen {
+		m.failoverActiveClusterMetric.Delete(prometheus.Labels{"namespace": gslb.Namespace, "name": gslb.Name, "host": host, "geotag": previous})
+		m.failoverTransitionsMetric.With(prometheus.Labels{"namespace": gslb.Namespace, "name": gslb.Name, "host": host,
+			"from": previous, "to": geoTag}).Inc()
+	}
+	m.activeClusters[key] = geoTag
+	m.failoverActiveClusterMetric.With(prometheus.Labels{"namespace": gslb.Namespace, "name": gslb.Name, "host": host, "geotag": geoTag}).Set(1)
+}
+
+// RetainFailoverHosts removes active cluster of failover hosts of the Gslb which aren't listed in hosts, i.e. hosts
+// removed from the spec or switched to other strategy. Nil hosts remove all hosts of the Gslb once it is deleted.
+func (m *PrometheusMetrics) RetainFailoverHosts(gslb *k8gbv1beta1.Gslb, hosts []string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	retained := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		retained[host] = true
+	}
+	prefix := gslb.Namespace + "/" + gslb.Name + "/"
+	for key, geoTag := range m.activeClusters {
+		host := strings.TrimPrefix(key, prefix)
+		if host == key || retained[host] {
+			continue
+		}
+		m.failoverActiveClusterMetric.Delete(prometheus.Labels{"namespace": gslb.Namespace, "name": gslb.Name, "host": host, "geotag": geoTag})
+		delete(m.activeClusters, key)
+	}
+}
+
+// ObservePeerLookup records duration of external targets lookup of peer cluster
+func (m *PrometheusMetrics) ObservePeerLookup(peer string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.peerLookupDurationMetric.With(prometheus.Labels{"peer": peer}).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.peerLookupErrorsMetric.With(prometheus.Labels{"peer": peer}).Inc()
+	}
+}
+
+// UpdateHeartbeatAge sets age of heartbeat TXT record read from edge DNS
+func (m *PrometheusMetrics) UpdateHeartbeatAge(fqdn string, age time.Duration) {
+	if m == nil {
+		return
+	}
+	m.heartbeatAgeMetric.With(prometheus.Labels{"fqdn": fqdn}).Set(age.Seconds())
+}
+
+// ObserveVerificationFailure counts DNS data of kind failing verification; name is heartbeat FQDN or geo tag of peer
+func (m *PrometheusMetrics) ObserveVerificationFailure(kind, name string) {
+	if m == nil {
+		return
+	}
+	m.verificationFailuresMetric.With(prometheus.Labels{"kind": kind, "name": name}).Inc()
+}
+
+// ObserveProviderRequest records duration of DNS provider API call
+func (m *PrometheusMetrics) ObserveProviderRequest(provider, operation string, start time.Time, err error) {
+	if m == nil {
+		return
+	}
+	m.providerDurationMetric.With(prometheus.Labels{"provider": provider, "operation": operation}).Observe(time.Since(start).Seconds())
+	if err != nil {
+		m.providerErrorsMetric.With(prometheus.Labels{"provider": provider, "operation": operation}).Inc()
+	}
+}
+
 // Register prometheus metrics. Read register documentation, but shortly:
 // You can register metric with given name only once
 func (m *PrometheusMetrics) Register() (err error) {
 	m.once.Do(func() {
-		if err = crm.Registry.Register(m.healthyRecordsMetric); err != nil {
-			return
-		}
-		if err = crm.Registry.Register(m.ingressHostsPerStatusMetric); err != nil {
-			return
+		for _, c := range m.collectors() {
+			if err = crm.Registry.Register(c); err != nil {
+				return
+			}
 		}
 	})
 	if err != nil {
@@ -113,8 +330,26 @@ func (m *PrometheusMetrics) Register() (err error) {
 
 // Unregister prometheus metrics
 func (m *PrometheusMetrics) Unregister() {
-	crm.Registry.Unregister(m.healthyRecordsMetric)
-	crm.Registry.Unregister(m.ingressHostsPerStatusMetric)
+	for _, c := range m.collectors() {
+		crm.Registry.Unregister(c)
+	}
+}
+
+func (m *PrometheusMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.healthyRecordsMetric,
+		m.ingressHostsPerStatusMetric,
+		m.reconcileDurationMetric,
+		m.reconcileErrorsMetric,
+		m.failoverTransitionsMetric,
+		m.failoverActiveClusterMetric,
+		m.peerLookupDurationMetric,
+		m.peerLookupErrorsMetric,
+		m.heartbeatAgeMetric,
+		m.verificationFailuresMetric,
+		m.providerDurationMetric,
+		m.providerErrorsMetric,
+	}
 }
 
 // GetHealthyRecordsMetric retrieves actual copy of healthy record metric
@@ -128,3 +363,43 @@ func (m *PrometheusMetrics) GetHealthyRecordsMetric() prometheus.GaugeVec {
 func (m *PrometheusMetrics) GetIngressHostsPerStatusMetric() prometheus.GaugeVec {
 	return *m.ingressHostsPerStatusMetric
 }
+
+// GetReconcileErrorsMetric retrieves actual copy of reconcile errors metric
+func (m *PrometheusMetrics) GetReconcileErrorsMetric() prometheus.CounterVec {
+	return *m.reconcileErrorsMetric
+}
+
+// GetReconcileDurationMetric retrieves actual copy of reconcile duration metric
+func (m *PrometheusMetrics) GetReconcileDurationMetric() prometheus.HistogramVec {
+	return *m.reconcileDurationMetric
+}
+
+// GetFailoverTransitionsMetric retrieves actual copy of failover transitions metric
+func (m *PrometheusMetrics) GetFailoverTransitionsMetric() prometheus.CounterVec {
+	return *m.failoverTransitionsMetric
+}
+
+// GetFailoverActiveClusterMetric retrieves actual copy of failover active cluster metric
+func (m *PrometheusMetrics) GetFailoverActiveClusterMetric() prometheus.GaugeVec {
+	return *m.failoverActiveClusterMetric
+}
+
+// GetPeerLookupErrorsMetric retrieves actual copy of peer lookup errors metric
+func (m *PrometheusMetrics) GetPeerLookupErrorsMetric() prometheus.CounterVec {
+	return *m.peerLookupErrorsMetric
+}
+
+// GetHeartbeatAgeMetric retrieves actual copy of heartbeat age metric
+func (m *PrometheusMetrics) GetHeartbeatAgeMetric() prometheus.GaugeVec {
+	return *m.heartbeatAgeMetric
+}
+
+// GetVerificationFailuresMetric retrieves actual copy of DNS verification failures metric
+func (m *PrometheusMetrics) GetVerificationFailuresMetric() prometheus.CounterVec {
+	return *m.verificationFailuresMetric
+}
+
+// GetProviderErrorsMetric retrieves actual copy of provider errors metric
+func (m *PrometheusMetrics) GetProviderErrorsMetric() prometheus.CounterVec {
+	return *m.providerErrorsMetric
+}
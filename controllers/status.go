@@ -23,13 +23,19 @@ import (
 
 	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	types "k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	externaldns "sigs.k8s.io/external-dns/endpoint"
 )
 
-func (r *GslbReconciler) updateGslbStatus(gslb *k8gbv1beta1.Gslb) error {
+// specValidCondition reports whether the spec is valid and complies with GslbPolicy
+const specValidCondition = "SpecValid"
+
+func (r *GslbReconciler) updateGslbStatus(gslb *k8gbv1beta1.Gslb, observed *k8gbv1beta1.GslbStatus) error {
 	var err error
 
 	gslb.Status.ServiceHealth, err = r.getServiceHealthStatus(gslb)
@@ -49,56 +55,105 @@ func (r *GslbReconciler) updateGslbStatus(gslb *k8gbv1beta1.Gslb) error {
 
 	gslb.Status.GeoTag = r.Config.ClusterGeoTag
 
+	gslb.Status.Strategies = make(map[string]k8gbv1beta1.Strategy, len(gslb.Spec.Ingress.Rules))
+	for _, rule := range gslb.Spec.Ingress.Rules {
+		gslb.Status.Strategies[rule.Host] = hostStrategy(gslb, rule.Host)
+	}
+
 	err = r.Metrics.UpdateHealthyRecordsMetric(gslb, gslb.Status.HealthyRecords)
 	if err != nil {
 		return err
 	}
 
-	err = r.Status().Update(context.TODO(), gslb)
-	return err
+	return r.saveStatus(gslb, observed)
+}
+
+// saveStatus writes status of the Gslb only when it differs from the observed one
+func (r *GslbReconciler) saveStatus(gslb *k8gbv1beta1.Gslb, observed *k8gbv1beta1.GslbStatus) error {
+	if equality.Semantic.DeepEqual(observed, &gslb.Status) {
+		return nil
+	}
+	return r.Status().Update(context.TODO(), gslb)
+}
+
+// setSpecValidCondition reports result of spec validation in conditions of the Gslb
+func setSpecValidCondition(gslb *k8gbv1beta1.Gslb, err error) {
+	condition := metav1.Condition{
+		Type:               specValidCondition,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Valid",
+		Message:            "Spec is valid",
+		ObservedGeneration: gslb.Generation,
+	}
+	if err != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "InvalidSpec"
+		condition.Message = err.Error()
+	}
+	meta.SetStatusCondition(&gslb.Status.Conditions, condition)
 }
 
 func (r *GslbReconciler) getServiceHealthStatus(gslb *k8gbv1beta1.Gslb) (map[string]string, error) {
 	serviceHealth := make(map[string]string)
 	for _, rule := range gslb.Spec.Ingress.Rules {
-		for _, path := range rule.HTTP.Paths {
-			service := &corev1.Service{}
-			finder := client.ObjectKey{
-				Namespace: gslb.Namespace,
-				Name:      path.Backend.ServiceName,
-			}
-			err := r.Get(context.TODO(), finder, service)
+		for _, serviceName := range ruleBackendServices(gslb.Spec.Ingress, rule) {
+			health, err := r.getServiceHealth(gslb.Namespace, serviceName)
 			if err != nil {
-				if errors.IsNotFound(err) {
-					serviceHealth[rule.Host] = "NotFound"
-					continue
-				}
 				return serviceHealth, err
 			}
+			serviceHealth[rule.Host] = health
+		}
+	}
+	return serviceHealth, nil
+}
+
+// ruleBackendServices returns services serving the host of the rule. Rule without HTTP paths is served
+// by the default backend, if there is any.
+func ruleBackendServices(spec k8gbv1beta1.IngressSpec, rule k8gbv1beta1.IngressRule) (services []string) {
+	if rule.HTTP == nil || len(rule.HTTP.Paths) == 0 {
+		if spec.Backend != nil && spec.Backend.ServiceName != "" {
+			services = append(services, spec.Backend.ServiceName)
+		}
+		return services
+	}
+	for _, path := range rule.HTTP.Paths {
+		services = append(services, path.Backend.ServiceName)
+	}
+	return services
+}
 
-			endpoints := &corev1.Endpoints{}
+func (r *GslbReconciler) getServiceHealth(namespace, serviceName string) (string, error) {
+	service := &corev1.Service{}
+	finder := client.ObjectKey{
+		Namespace: namespace,
+		Name:      serviceName,
+	}
+	err := r.Get(context.TODO(), finder, service)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return "NotFound", nil
+		}
+		return "", err
+	}
 
-			nn := types.NamespacedName{
-				Name:      path.Backend.ServiceName,
-				Namespace: gslb.Namespace,
-			}
+	endpoints := &corev1.Endpoints{}
 
-			err = r.Get(context.TODO(), nn, endpoints)
-			if err != nil {
-				return serviceHealth, err
-			}
+	nn := types.NamespacedName{
+		Name:      serviceName,
+		Namespace: namespace,
+	}
 
-			serviceHealth[rule.Host] = "Unhealthy"
-			if len(endpoints.Subsets) > 0 {
-				for _, subset := range endpoints.Subsets {
-					if len(subset.Addresses) > 0 {
-						serviceHealth[rule.Host] = "Healthy"
-					}
-				}
-			}
+	err = r.Get(context.TODO(), nn, endpoints)
+	if err != nil {
+		return "", err
+	}
+
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return "Healthy", nil
 		}
 	}
-	return serviceHealth, nil
+	return "Unhealthy", nil
 }
 
 func (r *GslbReconciler) getHealthyRecords(gslb *k8gbv1beta1.Gslb) (map[string][]string, error) {
@@ -110,13 +165,17 @@ func (r *GslbReconciler) getHealthyRecords(gslb *k8gbv1beta1.Gslb) (map[string][
 		Namespace: gslb.Namespace,
 	}
 
+	healthyRecords := make(map[string][]string)
+
 	err := r.Get(context.TODO(), nn, dnsEndpoint)
 	if err != nil {
+		if errors.IsNotFound(err) {
+			// DNSEndpoint is not written in dry-run mode
+			return healthyRecords, nil
+		}
 		return nil, err
 	}
 
-	healthyRecords := make(map[string][]string)
-
 	serviceRegex := regexp.MustCompile("^localtargets")
 	for _, endpoint := range dnsEndpoint.Spec.Endpoints {
 		local := serviceRegex.Match([]byte(endpoint.DNSName))
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package tracing
+
+import (
+	"context"
+
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/AbsaOSS/k8gb"
+
+// Attribute keys recorded on spans
+const (
+	GslbKey            = attribute.Key("k8gb.gslb")
+	HostKey            = attribute.Key("k8gb.host")
+	PeerKey            = attribute.Key("k8gb.peer")
+	StrategyKey        = attribute.Key("k8gb.strategy")
+	HealthKey          = attribute.Key("k8gb.health")
+	LocalTargetsKey    = attribute.Key("k8gb.targets.local")
+	ExternalTargetsKey = attribute.Key("k8gb.targets.external")
+	TargetsKey         = attribute.Key("k8gb.targets")
+	ProviderKey        = attribute.Key("k8gb.provider")
+	SourceKey          = attribute.Key("k8gb.source")
+)
+
+// Setup installs global tracer provider exporting spans to OTLP collector over gRPC. Nothing is installed when
+// tracing is disabled, so spans are no-op. Returned function flushes pending spans and stops the exporter.
+func Setup(ctx context.Context, config depresolver.Config) (shutdown func(context.Context) error, err error) {
+	if !config.Tracing.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+	options := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(config.Tracing.Endpoint)}
+	if config.Tracing.Insecure {
+		options = append(options, otlptracegrpc.WithInsecure())
+	}
+	exporter, err := otlptracegrpc.New(ctx, options...)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceNameKey.String("k8gb"),
+			semconv.ServiceNamespaceKey.String(config.K8gbNamespace),
+			attribute.String("k8gb.geotag", config.ClusterGeoTag))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown, nil
+}
+
+// Start creates span as a child of the span in ctx
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End records err in span, if any, and ends the span
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,363 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/utils"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+	"github.com/AbsaOSS/k8gb/controllers/providers/dns"
+	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+	"github.com/AbsaOSS/k8gb/controllers/tracing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/pointer"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+// ZoneDelegationReconciler reconciles delegation of DNSZone to this cluster in edge DNS. The delegation is
+// cluster-wide, Gslbs only reference it. Every Gslb which is not being deleted counts as reference; the
+// delegation is torn down when the last Gslb is released by its finalizer, or when the operator is uninstalled.
+type ZoneDelegationReconciler struct {
+	client.Client
+	Config      *depresolver.Config
+	DepResolver *depresolver.DependencyResolver
+	DNSProvider dns.Provider
+	Assistant   assistant.Assistant
+	Metrics     *metrics.PrometheusMetrics
+	// serializes reconciliation with releasing of Gslbs, so the delegation isn't written back after teardown
+	mu sync.Mutex
+}
+
+// operatorDeployment is the name of the operator Deployment in K8gbNamespace created by the chart
+const operatorDeployment = "k8gb"
+
+// operatorStopTimeout limits waiting for pods of the operator to terminate during uninstallation
+const operatorStopTimeout = 2 * time.Minute
+
+// zoneDelegationRequest is the only request handled by ZoneDelegationReconciler; any Gslb change maps to it
+var zoneDelegationRequest = reconcile.Request{NamespacedName: types.NamespacedName{Name: "zone-delegation"}}
+
+// Reconcile creates or updates zone delegation for all Gslbs referencing it
+func (r *ZoneDelegationReconciler) Reconcile(ctx context.Context, _ ctrl.Request) (ctrl.Result, error) {
+	result := utils.NewReconcileResultHandler(r.Config.ReconcileRequeueSeconds)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	gslbs, err := r.referencingGslbs(ctx, nil)
+	if err != nil {
+		return result.RequeueError(err)
+	}
+	if len(gslbs) == 0 {
+		log.Debug().Msg("No Gslb references zone delegation")
+		return result.Stop()
+	}
+	live, dryRun := r.splitDryRun(gslbs)
+	var delegationErr error
+	if len(live) > 0 {
+		delegationCtx, span := tracing.Start(ctx, "reconcile."+metrics.DelegationStage)
+		start := time.Now()
+		err = r.delegate(delegationCtx, live, nil)
+		r.Metrics.ObserveReconcileStage(metrics.DelegationStage, start, err)
+		tracing.End(span, err)
+		// delegation written by some of edge DNS providers is refreshed by regular requeue
+		if providersErr, ok := err.(*dns.ProvidersError); err != nil && !(ok && providersErr.Partial()) {
+			log.Err(err).Msg("Unable to create zone delegation")
+			delegationErr = err
+		}
+	}
+	if len(dryRun) > 0 {
+		r.plan(ctx, gslbs, dryRun)
+	}
+	if delegationErr != nil {
+		return result.RequeueError(delegationErr)
+	}
+	// requeue also on success to refresh heartbeats and pick up changes of the other clusters
+	return result.Requeue()
+}
+
+// Release is called by the finalizer of Gslb. Heartbeat records of the Gslb are removed; the delegation itself
+// is removed when no other Gslb references it.
+func (r *ZoneDelegationReconciler) Release(ctx context.Context, gslb *k8gbv1beta1.Gslb) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if isDryRun(r.Config, gslb) {
+		log.Info().Msgf("Dry-run: Gslb %s released zone delegation, edge DNS is not changed", gslb.Name)
+		return nil
+	}
+	gslbs, err := r.referencingGslbs(ctx, gslb)
+	if err != nil {
+		return err
+	}
+	// delegation is held only by Gslbs which write it
+	gslbs, _ = r.splitDryRun(gslbs)
+	if len(gslbs) == 0 {
+		log.Info().Msgf("Gslb %s was the last reference, removing zone delegation of %s", gslb.Name, r.Config.DNSZone)
+		return r.DNSProvider.Finalize(&dns.ZoneDelegation{TTL: r.Config.ZoneDelegation.TTL, Released: []*k8gbv1beta1.Gslb{gslb}})
+	}
+	log.Info().Msgf("Gslb %s released zone delegation, %v Gslb(s) still reference it", gslb.Name, len(gslbs))
+	return r.delegate(ctx, gslbs, []*k8gbv1beta1.Gslb{gslb})
+}
+
+// Uninstall stops the operator, removes zone delegation regardless of Gslbs and removes finalizers of all Gslbs,
+// so they can be deleted once the operator is gone
+func (r *ZoneDelegationReconciler) Uninstall(ctx context.Context) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// running operator would write the delegation and finalizers back before its Deployment is deleted
+	if err := r.stopOperator(ctx); err != nil {
+		return err
+	}
+	gslbList := &k8gbv1beta1.GslbList{}
+	if err := r.List(ctx, gslbList); err != nil {
+		return err
+	}
+	var gslbs []*k8gbv1beta1.Gslb
+	for i := range gslbList.Items {
+		gslbs = append(gslbs, &gslbList.Items[i])
+	}
+	zd := &dns.ZoneDelegation{TTL: r.Config.ZoneDelegation.TTL, Released: gslbs}
+	if r.Config.DryRun {
+		changes, err := dns.PlanFinalize(*r.Config, r.Assistant, zd)
+		if err != nil {
+			log.Err(err).Msg("Dry-run: unable to plan removal of zone delegation")
+		}
+		log.Info().Interface("changes", changes).Msg("Dry-run: zone delegation is not removed")
+	} else {
+		log.Info().Msgf("Removing zone delegation of %s, operator is being uninstalled", r.Config.DNSZone)
+		if err := r.DNSProvider.Finalize(zd); err != nil {
+			return err
+		}
+	}
+	for _, gslb := range gslbs {
+		finalizers := gslb.GetFinalizers()
+		gslb.SetFinalizers(remove(remove(finalizers, gslbFinalizer), "finalizer.k8gb.absa.oss"))
+		if err := r.Update(ctx, gslb); err != nil {
+			return fmt.Errorf("removing finalizer of Gslb %s/%s: %w", gslb.Namespace, gslb.Name, err)
+		}
+	}
+	return nil
+}
+
+// stopOperator scales the operator Deployment to zero and waits until all its pods are gone
+func (r *ZoneDelegationReconciler) stopOperator(ctx context.Context) error {
+	deployment := &appsv1.Deployment{}
+	key := client.ObjectKey{Namespace: r.Config.K8gbNamespace, Name: operatorDeployment}
+	if err := r.Get(ctx, key, deployment); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("getting operator deployment %s: %w", key, err)
+	}
+	if deployment.Spec.Replicas == nil || *deployment.Spec.Replicas != 0 {
+		log.Info().Msgf("Scaling operator deployment %s to zero", key)
+		deployment.Spec.Replicas = pointer.Int32Ptr(0)
+		if err := r.Update(ctx, deployment); err != nil {
+			return fmt.Errorf("scaling operator deployment %s to zero: %w", key, err)
+		}
+	}
+	selector, err := metav1.LabelSelectorAsSelector(deployment.Spec.Selector)
+	if err != nil {
+		return fmt.Errorf("selector of operator deployment %s: %w", key, err)
+	}
+	err = wait.PollImmediate(time.Second, operatorStopTimeout, func() (bool, error) {
+		pods := &corev1.PodList{}
+		if err := r.List(ctx, pods, client.InNamespace(key.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return false, err
+		}
+		return len(pods.Items) == 0, nil
+	})
+	if err != nil {
+		return fmt.Errorf("waiting for pods of operator deployment %s to terminate: %w", key, err)
+	}
+	return nil
+}
+
+// SetupWithManager configures controller manager
+func (r *ZoneDelegationReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	c, err := controller.New("zonedelegation", mgr, controller.Options{Reconciler: r})
+	if err != nil {
+		return err
+	}
+	// status updates don't change the delegation, heartbeats are refreshed by periodic requeue
+	return c.Watch(&source.Kind{Type: &k8gbv1beta1.Gslb{}}, handler.EnqueueRequestsFromMapFunc(
+		func(client.Object) []reconcile.Request {
+			return []reconcile.Request{zoneDelegationRequest}
+		}), predicate.Or(predicate.GenerationChangedPredicate{}, predicate.AnnotationChangedPredicate{}))
+}
+
+// delegate writes zone delegation and surfaces failures of particular edge DNS providers in status of all Gslbs
+func (r *ZoneDelegationReconciler) delegate(ctx context.Context, gslbs, released []*k8gbv1beta1.Gslb) error {
+	ips, err := r.nameserverIPs(gslbs)
+	if err != nil {
+		return err
+	}
+	_, span := tracing.Start(ctx, "provider.CreateZoneDelegationForExternalDNS",
+		tracing.ProviderKey.String(providerName(r.DNSProvider)), tracing.TargetsKey.StringSlice(ips))
+	err = r.DNSProvider.CreateZoneDelegationForExternalDNS(&dns.ZoneDelegation{
+		TTL:           r.Config.ZoneDelegation.TTL,
+		NameserverIPs: ips,
+		Gslbs:         gslbs,
+		Released:      released,
+	})
+	tracing.End(span, err)
+	var edgeDNSErrors map[string]string
+	if providersErr, ok := err.(*dns.ProvidersError); ok {
+		if providersErr.Partial() {
+			log.Err(err).Msg("Unable to create zone delegation in some of edge DNS providers")
+		}
+		edgeDNSErrors = make(map[string]string, len(providersErr.Errors))
+		for name, e := range providersErr.Errors {
+			edgeDNSErrors[name] = e.Error()
+		}
+	} else if err != nil {
+		return err
+	}
+	for _, gslb := range gslbs {
+		if len(gslb.Status.EdgeDNSErrors) == 0 && len(edgeDNSErrors) == 0 || reflect.DeepEqual(gslb.Status.EdgeDNSErrors, edgeDNSErrors) {
+			continue
+		}
+		gslb.Status.EdgeDNSErrors = edgeDNSErrors
+		if e := r.Status().Update(ctx, gslb); e != nil {
+			log.Err(e).Msgf("Can't update edge DNS errors of Gslb %s", gslb.Name)
+		}
+	}
+	return err
+}
+
+// plan computes zone delegation of all Gslbs without writing it. Changes of shared delegation records and own
+// heartbeat are stored in status of every Gslb in dry-run mode.
+func (r *ZoneDelegationReconciler) plan(ctx context.Context, gslbs, dryRun []*k8gbv1beta1.Gslb) {
+	ips, err := r.nameserverIPs(gslbs)
+	if err != nil {
+		log.Err(err).Msg("Dry-run: unable to plan zone delegation")
+		return
+	}
+	changes, err := dns.PlanZoneDelegation(*r.Config, r.Assistant, &dns.ZoneDelegation{
+		TTL:           r.Config.ZoneDelegation.TTL,
+		NameserverIPs: ips,
+		Gslbs:         dryRun,
+	})
+	if err != nil {
+		log.Err(err).Msg("Dry-run: unable to plan zone delegation")
+		return
+	}
+	if len(changes) > 0 {
+		log.Info().Interface("changes", changes).Msg("Dry-run: zone delegation changes not written")
+	}
+	for _, gslb := range dryRun {
+		var own []k8gbv1beta1.DNSChange
+		for _, change := range changes {
+			if change.Type != "TXT" || change.Name == r.Config.GetClusterHeartbeatFQDN(gslb.Name) {
+				own = append(own, change)
+			}
+		}
+		if gslb.Status.DryRun == nil {
+			gslb.Status.DryRun = &k8gbv1beta1.DryRunStatus{}
+		} else if reflect.DeepEqual(gslb.Status.DryRun.ZoneDelegation, own) {
+			continue
+		}
+		gslb.Status.DryRun.ZoneDelegation = own
+		if e := r.Status().Update(ctx, gslb); e != nil {
+			log.Err(e).Msgf("Can't update dry-run status of Gslb %s", gslb.Name)
+		}
+	}
+}
+
+// splitDryRun separates Gslbs whose DNS changes are written from Gslbs in dry-run mode
+func (r *ZoneDelegationReconciler) splitDryRun(gslbs []*k8gbv1beta1.Gslb) (live, dryRun []*k8gbv1beta1.Gslb) {
+	for _, gslb := range gslbs {
+		if isDryRun(r.Config, gslb) {
+			dryRun = append(dryRun, gslb)
+		} else {
+			live = append(live, gslb)
+		}
+	}
+	return live, dryRun
+}
+
+// nameserverIPs returns glue records of the cluster nameserver. Static addresses take precedence over exposed
+// CoreDNS; addresses of all Gslb ingresses are used otherwise.
+func (r *ZoneDelegationReconciler) nameserverIPs(gslbs []*k8gbv1beta1.Gslb) ([]string, error) {
+	if len(r.Config.ZoneDelegation.GlueIPs) > 0 {
+		return r.Config.ZoneDelegation.GlueIPs, nil
+	}
+	if r.Config.CoreDNSExposed {
+		return r.Assistant.CoreDNSExposedIPs()
+	}
+	unique := make(map[string]bool)
+	for _, gslb := range gslbs {
+		ips, err := r.DNSProvider.GslbIngressExposedIPs(gslb)
+		if err != nil {
+			return nil, err
+		}
+		for _, ip := range ips {
+			unique[ip] = true
+		}
+	}
+	ips := make([]string, 0, len(unique))
+	for ip := range unique {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+	return ips, nil
+}
+
+// referencingGslbs lists Gslbs of all namespaces which are not being deleted, except the released one
+func (r *ZoneDelegationReconciler) referencingGslbs(ctx context.Context, released *k8gbv1beta1.Gslb) ([]*k8gbv1beta1.Gslb, error) {
+	gslbList := &k8gbv1beta1.GslbList{}
+	if err := r.List(ctx, gslbList); err != nil {
+		return nil, err
+	}
+	var gslbs []*k8gbv1beta1.Gslb
+	for i := range gslbList.Items {
+		gslb := &gslbList.Items[i]
+		if gslb.GetDeletionTimestamp() != nil {
+			continue
+		}
+		if released != nil && gslb.Namespace == released.Namespace && gslb.Name == released.Name {
+			continue
+		}
+		// heartbeats need TTL and split brain threshold, which are resolved in memory only;
+		// invalid Gslb still references the delegation
+		err := r.DepResolver.ResolveGslbSpec(ctx, gslb, r.Client)
+		if _, invalid := err.(*depresolver.InvalidSpecError); err != nil && !invalid {
+			return nil, err
+		}
+		gslbs = append(gslbs, gslb)
+	}
+	return gslbs, nil
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2021 The k8gb Contributors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+
+Generated by GoLic, for more details see: https://github.com/AbsaOSS/golic
+*/
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	k8gbv1beta1 "github.com/AbsaOSS/k8gb/api/v1beta1"
+	"github.com/AbsaOSS/k8gb/controllers/depresolver"
+	"github.com/AbsaOSS/k8gb/controllers/internal/heartbeat"
+	"github.com/AbsaOSS/k8gb/controllers/providers/assistant"
+	"github.com/AbsaOSS/k8gb/controllers/providers/dns"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newZoneDelegationReconciler(t *testing.T, ctrl *gomock.Controller, gslbs ...string) (*ZoneDelegationReconciler, *dns.MockProvider) {
+	s := runtime.NewScheme()
+	require.NoError(t, k8gbv1beta1.AddToScheme(s))
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	var objs []runtime.Object
+	for _, name := range gslbs {
+		objs = append(objs, &k8gbv1beta1.Gslb{ObjectMeta: metav1.ObjectMeta{Namespace: "test-gslb", Name: name, Finalizers: []string{gslbFinalizer}}})
+	}
+	provider := dns.NewMockProvider(ctrl)
+	config := predefinedConfig
+	return &ZoneDelegationReconciler{
+		Client:      fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(objs...).Build(),
+		Config:      &config,
+		DepResolver: depresolver.NewDependencyResolver(),
+		DNSProvider: provider,
+		Assistant:   assistant.NewMockAssistant(ctrl),
+	}, provider
+}
+
+func names(gslbs []*k8gbv1beta1.Gslb) (n []string) {
+	for _, gslb := range gslbs {
+		n = append(n, gslb.Name)
+	}
+	return n
+}
+
+func TestZoneDelegationIsCreatedOnceForAllGslbs(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1", "app2")
+	r.Config.ZoneDelegation.TTL = 300
+	provider.EXPECT().GslbIngressExposedIPs(gomock.Any()).Return([]string{"10.0.0.2", "10.0.0.1"}, nil).Times(1)
+	provider.EXPECT().GslbIngressExposedIPs(gomock.Any()).Return([]string{"10.0.0.3", "10.0.0.2"}, nil).Times(1)
+	var zd *dns.ZoneDelegation
+	provider.EXPECT().CreateZoneDelegationForExternalDNS(gomock.Any()).DoAndReturn(func(z *dns.ZoneDelegation) error {
+		zd = z
+		return nil
+	}).Times(1)
+	// act
+	result, err := r.Reconcile(context.TODO(), zoneDelegationRequest)
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, predefinedConfig.ReconcileRequeueSeconds, int(result.RequeueAfter.Seconds()))
+	require.NotNil(t, zd)
+	assert.Equal(t, 300, zd.TTL)
+	assert.Equal(t, []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"}, zd.NameserverIPs)
+	assert.Equal(t, []string{"app1", "app2"}, names(zd.Gslbs))
+	assert.Empty(t, zd.Released)
+}
+
+func TestZoneDelegationUsesStaticGlue(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1")
+	r.Config.ZoneDelegation.GlueIPs = []string{"192.168.0.1"}
+	provider.EXPECT().GslbIngressExposedIPs(gomock.Any()).Times(0)
+	provider.EXPECT().CreateZoneDelegationForExternalDNS(gomock.Any()).DoAndReturn(func(z *dns.ZoneDelegation) error {
+		assert.Equal(t, []string{"192.168.0.1"}, z.NameserverIPs)
+		return nil
+	}).Times(1)
+	// act
+	_, err := r.Reconcile(context.TODO(), zoneDelegationRequest)
+	// assert
+	require.NoError(t, err)
+}
+
+func TestZoneDelegationIsNotCreatedWithoutGslbs(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl)
+	provider.EXPECT().CreateZoneDelegationForExternalDNS(gomock.Any()).Times(0)
+	// act
+	result, err := r.Reconcile(context.TODO(), zoneDelegationRequest)
+	// assert
+	require.NoError(t, err)
+	assert.Zero(t, result.RequeueAfter)
+}
+
+func TestZoneDelegationIsRetriedWhenAllProvidersFail(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1")
+	r.Config.ZoneDelegation.GlueIPs = []string{"192.168.0.1"}
+	provider.EXPECT().CreateZoneDelegationForExternalDNS(gomock.Any()).Return(&dns.ProvidersError{
+		Errors: map[string]error{"Infoblox": errors.New("connection refused")}, Total: 1}).Times(1)
+	// act
+	_, err := r.Reconcile(context.TODO(), zoneDelegationRequest)
+	// assert
+	require.Error(t, err)
+	gslb := &k8gbv1beta1.Gslb{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKey{Namespace: "test-gslb", Name: "app1"}, gslb))
+	assert.Equal(t, map[string]string{"Infoblox": "connection refused"}, gslb.Status.EdgeDNSErrors)
+}
+
+func TestPartialZoneDelegationIsRequeuedRegularly(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1")
+	r.Config.ZoneDelegation.GlueIPs = []string{"192.168.0.1"}
+	provider.EXPECT().CreateZoneDelegationForExternalDNS(gomock.Any()).Return(&dns.ProvidersError{
+		Errors: map[string]error{"Infoblox": errors.New("connection refused")}, Total: 2}).Times(1)
+	// act
+	result, err := r.Reconcile(context.TODO(), zoneDelegationRequest)
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, predefinedConfig.ReconcileRequeueSeconds, int(result.RequeueAfter.Seconds()))
+}
+
+func TestReleaseOfGslbKeepsZoneDelegation(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1", "app2")
+	released := &k8gbv1beta1.Gslb{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKey{Namespace: "test-gslb", Name: "app1"}, released))
+	provider.EXPECT().GslbIngressExposedIPs(gomock.Any()).Return([]string{"10.0.0.1"}, nil).Times(1)
+	provider.EXPECT().Finalize(gomock.Any()).Times(0)
+	provider.EXPECT().CreateZoneDelegationForExternalDNS(gomock.Any()).DoAndReturn(func(z *dns.ZoneDelegation) error {
+		assert.Equal(t, []string{"app2"}, names(z.Gslbs))
+		assert.Equal(t, []string{"app1"}, names(z.Released))
+		return nil
+	}).Times(1)
+	// act
+	err := r.Release(context.TODO(), released)
+	// assert
+	require.NoError(t, err)
+}
+
+func TestReleaseOfLastGslbRemovesZoneDelegation(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1")
+	released := &k8gbv1beta1.Gslb{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKey{Namespace: "test-gslb", Name: "app1"}, released))
+	provider.EXPECT().CreateZoneDelegationForExternalDNS(gomock.Any()).Times(0)
+	provider.EXPECT().Finalize(gomock.Any()).DoAndReturn(func(z *dns.ZoneDelegation) error {
+		assert.Equal(t, []string{"app1"}, names(z.Released))
+		return nil
+	}).Times(1)
+	// act
+	err := r.Release(context.TODO(), released)
+	// assert
+	require.NoError(t, err)
+}
+
+func TestUninstallRemovesZoneDelegationAndFinalizers(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1", "app2")
+	provider.EXPECT().Finalize(gomock.Any()).DoAndReturn(func(z *dns.ZoneDelegation) error {
+		assert.Equal(t, []string{"app1", "app2"}, names(z.Released))
+		return nil
+	}).Times(1)
+	// act
+	err := r.Uninstall(context.TODO())
+	// assert
+	require.NoError(t, err)
+	gslb := &k8gbv1beta1.Gslb{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKey{Namespace: "test-gslb", Name: "app2"}, gslb))
+	assert.Empty(t, gslb.Finalizers)
+}
+
+func TestUninstallStopsOperatorBeforeRemovingZoneDelegation(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1")
+	key := client.ObjectKey{Namespace: r.Config.K8gbNamespace, Name: operatorDeployment}
+	require.NoError(t, r.Create(context.TODO(), &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Namespace: key.Namespace, Name: key.Name},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: pointer.Int32Ptr(1),
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"name": "k8gb"}},
+		},
+	}))
+	provider.EXPECT().Finalize(gomock.Any()).DoAndReturn(func(*dns.ZoneDelegation) error {
+		deployment := &appsv1.Deployment{}
+		require.NoError(t, r.Get(context.TODO(), key, deployment))
+		assert.Equal(t, int32(0), *deployment.Spec.Replicas, "operator must be stopped before delegation is removed")
+		return nil
+	}).Times(1)
+	// act
+	err := r.Uninstall(context.TODO())
+	// assert
+	require.NoError(t, err)
+}
+
+func TestFailedUninstallKeepsFinalizers(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1")
+	provider.EXPECT().Finalize(gomock.Any()).Return(errors.New("connection refused")).Times(1)
+	// act
+	err := r.Uninstall(context.TODO())
+	// assert
+	require.Error(t, err)
+	gslb := &k8gbv1beta1.Gslb{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKey{Namespace: "test-gslb", Name: "app1"}, gslb))
+	assert.Equal(t, []string{gslbFinalizer}, gslb.Finalizers)
+}
+
+func TestDryRunGslbDoesNotWriteZoneDelegation(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1", "app2")
+	r.Config.ZoneDelegation.GlueIPs = []string{"10.0.0.1"}
+	r.Config.SplitBrainCheck = true
+	gslb := &k8gbv1beta1.Gslb{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKey{Namespace: "test-gslb", Name: "app2"}, gslb))
+	gslb.Annotations = map[string]string{dryRunAnnotation: "true"}
+	require.NoError(t, r.Update(context.TODO(), gslb))
+	m := r.Assistant.(*assistant.MockAssistant)
+	m.EXPECT().InspectHeartbeat(gomock.Any(), gomock.Any()).Return(heartbeat.Heartbeat{}, nil).AnyTimes()
+	m.EXPECT().LookupRecords(r.Config.DNSZone, "NS").Return([]string{r.Config.GetClusterNSName()}, nil).Times(1)
+	m.EXPECT().LookupRecords(r.Config.GetClusterNSName(), "A").Return([]string{"10.0.0.1"}, nil).Times(1)
+	provider.EXPECT().CreateZoneDelegationForExternalDNS(gomock.Any()).DoAndReturn(func(z *dns.ZoneDelegation) error {
+		assert.Equal(t, []string{"app1"}, names(z.Gslbs))
+		return nil
+	}).Times(1)
+	// act
+	_, err := r.Reconcile(context.TODO(), zoneDelegationRequest)
+	// assert
+	require.NoError(t, err)
+	planned := &k8gbv1beta1.Gslb{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKey{Namespace: "test-gslb", Name: "app2"}, planned))
+	require.NotNil(t, planned.Status.DryRun)
+	assert.Equal(t, []k8gbv1beta1.DNSChange{{Action: "update", Name: r.Config.GetClusterHeartbeatFQDN("app2"), Type: "TXT", TTL: 30}},
+		planned.Status.DryRun.ZoneDelegation)
+}
+
+func TestReleaseInDryRunDoesNotChangeEdgeDNS(t *testing.T) {
+	// arrange
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	r, provider := newZoneDelegationReconciler(t, ctrl, "app1")
+	r.Config.DryRun = true
+	released := &k8gbv1beta1.Gslb{}
+	require.NoError(t, r.Get(context.TODO(), client.ObjectKey{Namespace: "test-gslb", Name: "app1"}, released))
+	provider.EXPECT().Finalize(gomock.Any()).Times(0)
+	provider.EXPECT().CreateZoneDelegationForExternalDNS(gomock.Any()).Times(0)
+	// act
+	err := r.Release(context.TODO(), released)
+	// assert
+	require.NoError(t, err)
+}
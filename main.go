@@ -15,7 +15,10 @@ limitations under the License.
 package main
 
 import (
+	"context"
+	"flag"
 	"os"
+	"time"
 
 	str "github.com/AbsaOSS/gopkg/strings"
 
@@ -23,14 +26,17 @@ import (
 	"github.com/AbsaOSS/k8gb/controllers"
 	"github.com/AbsaOSS/k8gb/controllers/depresolver"
 	"github.com/AbsaOSS/k8gb/controllers/logging"
+	"github.com/AbsaOSS/k8gb/controllers/peerstatus"
 	"github.com/AbsaOSS/k8gb/controllers/providers/dns"
 	"github.com/AbsaOSS/k8gb/controllers/providers/metrics"
+	"github.com/AbsaOSS/k8gb/controllers/tracing"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/scheme"
 	externaldns "sigs.k8s.io/external-dns/endpoint"
 	// +kubebuilder:scaffold:imports
@@ -49,6 +55,9 @@ func init() {
 
 func main() {
 	var f *dns.ProviderFactory
+	var uninstall bool
+	flag.BoolVar(&uninstall, "uninstall", false, "remove zone delegation from edge DNS and finalizers from all Gslbs, then exit")
+	flag.Parse()
 	resolver := depresolver.NewDependencyResolver()
 	config, err := resolver.ResolveOperatorConfig()
 	// Initialize desired log or default log in case of configuration failed.
@@ -94,25 +103,99 @@ func main() {
 		Scheme:      mgr.GetScheme(),
 	}
 
+	reconciler.Metrics = metrics.NewPrometheusMetrics(*reconciler.Config)
+
 	log.Info().Msg("starting DNS provider")
-	f, err = dns.NewDNSProviderFactory(reconciler.Client, *reconciler.Config)
+	f, err = dns.NewDNSProviderFactory(reconciler.Client, *reconciler.Config, reconciler.Metrics)
 	if err != nil {
 		log.Err(err).Msgf("unable to create factory (%s)", err)
 		os.Exit(1)
 	}
 	reconciler.DNSProvider = f.Provider()
 	log.Info().Msgf("provider: %s", reconciler.DNSProvider)
+	a := f.Assistant()
+	reconciler.ZoneDelegation = &controllers.ZoneDelegationReconciler{
+		Client:      mgr.GetClient(),
+		Config:      config,
+		DepResolver: resolver,
+		DNSProvider: reconciler.DNSProvider,
+		Assistant:   a,
+		Metrics:     reconciler.Metrics,
+	}
+	if uninstall {
+		// manager isn't started, so the cache backing the client isn't available
+		c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+		if err != nil {
+			log.Err(err).Msg("unable to create client")
+			os.Exit(1)
+		}
+		reconciler.ZoneDelegation.Client = c
+		if err = reconciler.ZoneDelegation.Uninstall(context.Background()); err != nil {
+			// failed hook stops uninstallation of the chart, so the delegation isn't left behind unnoticed
+			log.Err(err).Msg("unable to remove zone delegation")
+			os.Exit(1)
+		}
+		return
+	}
 	log.Info().Msg("starting metrics")
-	reconciler.Metrics = metrics.NewPrometheusMetrics(*reconciler.Config)
 	err = reconciler.Metrics.Register()
 	if err != nil {
 		log.Err(err).Msg("register metrics error")
 		os.Exit(1)
 	}
+	shutdownTracing, err := tracing.Setup(context.Background(), *config)
+	if err != nil {
+		log.Err(err).Msg("unable to set up tracing")
+		os.Exit(1)
+	}
+	reconciler.PeerTargets = controllers.NewPeerTargetsRefresher(mgr.GetClient(), reconciler.DNSProvider,
+		time.Duration(config.ReconcileRequeueSeconds)*time.Second)
 	if err = reconciler.SetupWithManager(mgr); err != nil {
 		log.Err(err).Msg("unable to create controller Gslb")
 		os.Exit(1)
 	}
+	if err = reconciler.ZoneDelegation.SetupWithManager(mgr); err != nil {
+		log.Err(err).Msg("unable to create controller ZoneDelegation")
+		os.Exit(1)
+	}
+	ingressReconciler := &controllers.IngressReconciler{
+		Client: mgr.GetClient(),
+		Scheme: mgr.GetScheme(),
+		Config: config,
+	}
+	if err = ingressReconciler.SetupWithManager(mgr); err != nil {
+		log.Err(err).Msg("unable to create controller Ingress")
+		os.Exit(1)
+	}
+	if config.DryRun || config.DebugAPI {
+		if err = mgr.AddMetricsExtraHandler("/debug/dryrun", controllers.NewDryRunHandler(mgr.GetClient(), config)); err != nil {
+			log.Err(err).Msg("unable to register dry-run handler")
+			os.Exit(1)
+		}
+	}
+	if config.DebugAPI {
+		reconciler.Debug = controllers.NewDebugState()
+		debugHandler := controllers.NewDebugHandler(mgr.GetClient(), config, reconciler.Debug, a, reconciler.DNSProvider)
+		if err = mgr.AddMetricsExtraHandler("/debug/state", debugHandler); err != nil {
+			log.Err(err).Msg("unable to register debug handler")
+			os.Exit(1)
+		}
+	}
+	if config.PeerStatus.Enabled {
+		peerStatus, err := peerstatus.NewClient(*config)
+		if err != nil {
+			log.Err(err).Msg("unable to create peer status client")
+			os.Exit(1)
+		}
+		a.UsePeerStatus(peerStatus)
+		if err = mgr.Add(peerstatus.NewServer(*config, reconciler.PeerStatus)); err != nil {
+			log.Err(err).Msg("unable to register peer status server")
+			os.Exit(1)
+		}
+	}
+	if config.DryRun {
+		log.Warn().Msg("dry-run mode, DNS changes are computed but not written")
+	}
 	// +kubebuilder:scaffold:builder
 	log.Info().Msg("starting manager")
 	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
@@ -120,4 +203,7 @@ func main() {
 		os.Exit(1)
 	}
 	reconciler.Metrics.Unregister()
+	if err = shutdownTracing(context.Background()); err != nil {
+		log.Err(err).Msg("unable to flush traces")
+	}
 }